@@ -12,11 +12,13 @@ import (
 	"github.com/gin-gonic/gin"
 	"kubey/api/internal/config"
 	"kubey/api/internal/middlewares/logging"
+	"kubey/api/internal/middlewares/metrics"
 	"kubey/api/internal/middlewares/recovery"
 	"kubey/api/internal/middlewares/request"
 	"kubey/api/internal/middlewares/security"
 	"kubey/api/internal/routes"
 	"kubey/api/internal/services/kubernetes"
+	"kubey/api/internal/store"
 )
 
 func main() {
@@ -27,6 +29,40 @@ func main() {
 		log.Fatalf("Failed to initialize Kubernetes client: %v", err)
 	}
 
+	// Open the embedded store
+	db, err := store.Open(cfg.StorePath)
+	if err != nil {
+		log.Fatalf("Failed to open store: %v", err)
+	}
+	defer db.Close()
+
+	// Start background watches; they stop when the server shuts down
+	watchCtx, stopWatches := context.WithCancel(context.Background())
+	defer stopWatches()
+
+	if cfg.EventRecordingEnabled {
+		kubernetes.RecordEvents(watchCtx, db, cfg.EventRetention)
+	}
+	if cfg.ChangeRecordingEnabled {
+		kubernetes.RecordChanges(watchCtx, db, cfg.ChangeRetention)
+	}
+	if cfg.SummaryTrackingEnabled {
+		kubernetes.TrackSummaries(watchCtx, cfg.SummaryPushInterval)
+	}
+	if cfg.SummaryHistoryEnabled {
+		kubernetes.RecordSummaryHistory(watchCtx, db, kubernetes.HistoryOptions{
+			Interval:     cfg.SummaryHistoryInterval,
+			RawRetention: cfg.SummaryHistoryRawRetention,
+			Retention:    cfg.SummaryHistoryRetention,
+			PoolLabel:    cfg.NodePoolLabel,
+			CostLabels:   cfg.CostLabels,
+		})
+	}
+
+	if err := kubernetes.StartWatches(watchCtx); err != nil {
+		log.Printf("Failed to start watches: %v", err)
+	}
+
 	if cfg.Environment == "production" {
 		gin.SetMode(gin.ReleaseMode)
 	}
@@ -38,12 +74,16 @@ func main() {
 	router.Use(recovery.Recover())
 	// 2. Request ID - generate/track request IDs
 	router.Use(request.RequestID())
-	// 3. Logging - log requests after request ID is set
+	// 3. Caller - identity from the authenticating proxy, for audit logs
+	router.Use(request.Caller(cfg.AuditUserHeader))
+	// 4. Logging - log requests after request ID is set
 	router.Use(logging.Logger())
-	// 4. CORS - handle cross-origin requests
+	// 5. Metrics - record latency and status by route
+	router.Use(metrics.Metrics())
+	// 6. CORS - handle cross-origin requests
 	router.Use(security.CORS(cfg))
 
-	routes.Setup(router, cfg)
+	routes.Setup(router, cfg, db)
 
 	srv := &http.Server{
 		Addr:         cfg.Host + ":" + cfg.Port,
@@ -65,6 +105,8 @@ func main() {
 	<-quit
 
 	log.Println("Shutting down server...")
+	stopWatches()
+
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
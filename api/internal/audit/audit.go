@@ -0,0 +1,36 @@
+package audit
+
+import (
+	"log"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Log records a privileged action with the request ID, identity and client IP of the caller.
+// Callers without an identity from the authenticating proxy are logged as anonymous.
+// A nil err is logged as success; otherwise the error is logged as the failure reason.
+func Log(c *gin.Context, action, target string, err error) {
+	requestID := c.GetString("RequestID")
+	if requestID == "" {
+		requestID = "no-request-id"
+	}
+
+	user := c.GetString("Caller")
+	if user == "" {
+		user = "anonymous"
+	}
+
+	outcome := "success"
+	if err != nil {
+		outcome = "failure: " + err.Error()
+	}
+
+	log.Printf("[%s] AUDIT action=%s target=%s user=%q client=%s outcome=%s",
+		requestID,
+		action,
+		target,
+		user,
+		c.ClientIP(),
+		outcome,
+	)
+}
@@ -7,6 +7,8 @@ import (
 	"strings"
 	"time"
 
+	"kubey/api/internal/models"
+
 	"github.com/joho/godotenv"
 )
 
@@ -21,6 +23,52 @@ type ApiConfig struct {
 	HTTPIdleTimeout  time.Duration
 	AllowedOrigins   []string
 	RequestIDHeader  string
+	AuditUserHeader  string
+
+	// Debug sessions (ephemeral containers and node debug pods)
+	DebugEnabled       bool
+	DebugDefaultImage  string
+	DebugAllowedImages []string
+	DebugNamespace     string
+	DebugStartTimeout  time.Duration
+
+	// ConfigMap and Secret inventory
+	ConfigMapMaxValueBytes int
+	SecretRevealEnabled    bool
+	SecretRevealNamespaces []string
+	SecretRevealDenyTypes  []string
+
+	// Embedded store and event recording
+	StorePath             string
+	EventRecordingEnabled bool
+	EventRetention        time.Duration
+
+	// Change feed
+	ChangeRecordingEnabled bool
+	ChangeRetention        time.Duration
+
+	// Live cluster summaries
+	SummaryTrackingEnabled bool
+	SummaryPushInterval    time.Duration
+
+	// Summary history
+	SummaryHistoryEnabled      bool
+	SummaryHistoryInterval     time.Duration
+	SummaryHistoryRawRetention time.Duration
+	SummaryHistoryRetention    time.Duration
+
+	// Capacity reporting
+	NodePoolLabel string
+
+	// Historical metrics from Prometheus, keyed by cluster ID
+	PrometheusURLs    map[string]string
+	PrometheusTimeout time.Duration
+
+	// Cost allocation
+	CostCurrency     string
+	CostDefaultPrice models.ResourcePrice
+	CostPoolPrices   map[string]models.ResourcePrice // By NodePoolLabel value
+	CostLabels       []string                        // Pod labels costs can be aggregated by
 }
 
 func LoadApi() *ApiConfig {
@@ -40,6 +88,55 @@ func LoadApi() *ApiConfig {
 		HTTPIdleTimeout:  getDurationEnv("HTTP_IDLE_TIMEOUT", 30*time.Second),
 		AllowedOrigins:   getSliceEnv("ALLOWED_ORIGINS", []string{"http://localhost:5173", "http://127.0.0.1:5173"}),
 		RequestIDHeader:  getEnv("REQUEST_ID_HEADER", "X-Request-ID"),
+		AuditUserHeader:  getEnv("AUDIT_USER_HEADER", "X-Forwarded-User"),
+
+		DebugEnabled:       getBoolEnv("DEBUG_ENABLED", false),
+		DebugDefaultImage:  getEnv("DEBUG_DEFAULT_IMAGE", "busybox:1.36"),
+		DebugAllowedImages: getSliceEnv("DEBUG_ALLOWED_IMAGES", nil),
+		DebugNamespace:     getEnv("DEBUG_NAMESPACE", "default"),
+		DebugStartTimeout:  getDurationEnv("DEBUG_START_TIMEOUT", 60*time.Second),
+
+		ConfigMapMaxValueBytes: getIntEnv("CONFIGMAP_MAX_VALUE_BYTES", 16*1024),
+		SecretRevealEnabled:    getBoolEnv("SECRET_REVEAL_ENABLED", false),
+		SecretRevealNamespaces: getSliceEnv("SECRET_REVEAL_NAMESPACES", []string{}),
+		SecretRevealDenyTypes: getSliceEnv("SECRET_REVEAL_DENY_TYPES", []string{
+			"kubernetes.io/service-account-token",
+			"kubernetes.io/dockerconfigjson",
+			"kubernetes.io/dockercfg",
+		}),
+
+		StorePath:             getEnv("STORE_PATH", "data/kubey.db"),
+		EventRecordingEnabled: getBoolEnv("EVENT_RECORDING_ENABLED", true),
+		EventRetention:        getDurationEnv("EVENT_RETENTION", 7*24*time.Hour),
+
+		ChangeRecordingEnabled: getBoolEnv("CHANGE_RECORDING_ENABLED", true),
+		ChangeRetention:        getDurationEnv("CHANGE_RETENTION", 7*24*time.Hour),
+
+		SummaryTrackingEnabled: getBoolEnv("SUMMARY_TRACKING_ENABLED", true),
+		SummaryPushInterval:    getDurationEnv("SUMMARY_PUSH_INTERVAL", 2*time.Second),
+
+		SummaryHistoryEnabled:      getBoolEnv("SUMMARY_HISTORY_ENABLED", true),
+		SummaryHistoryInterval:     getDurationEnv("SUMMARY_HISTORY_INTERVAL", time.Minute),
+		SummaryHistoryRawRetention: getDurationEnv("SUMMARY_HISTORY_RAW_RETENTION", 48*time.Hour),
+		SummaryHistoryRetention:    getDurationEnv("SUMMARY_HISTORY_RETENTION", 30*24*time.Hour),
+
+		NodePoolLabel: getEnv("NODE_POOL_LABEL", "node.kubernetes.io/instance-type"),
+
+		PrometheusURLs:    getMapEnv("PROMETHEUS_URLS"),
+		PrometheusTimeout: getDurationEnv("PROMETHEUS_TIMEOUT", 30*time.Second),
+
+		CostCurrency: getEnv("COST_CURRENCY", "USD"),
+		CostDefaultPrice: models.ResourcePrice{
+			CPUHour:       getFloatEnv("COST_CPU_HOUR_PRICE", 0.0316),
+			MemoryGiBHour: getFloatEnv("COST_MEMORY_GIB_HOUR_PRICE", 0.0042),
+		},
+		CostPoolPrices: getPriceMapEnv("COST_POOL_PRICES"),
+		CostLabels:     getSliceEnv("COST_LABELS", []string{"team"}),
+	}
+
+	// Only the default debug image is allowed unless an allowlist is configured
+	if len(config.DebugAllowedImages) == 0 {
+		config.DebugAllowedImages = []string{config.DebugDefaultImage}
 	}
 
 	return config
@@ -71,13 +168,77 @@ func getSliceEnv(key string, defaultValue []string) []string {
 	return defaultValue
 }
 
+// getMapEnv parses "key=value" pairs separated by commas
+func getMapEnv(key string) map[string]string {
+	result := make(map[string]string)
+	for _, item := range getSliceEnv(key, nil) {
+		k, v, ok := strings.Cut(item, "=")
+		if !ok || strings.TrimSpace(k) == "" {
+			log.Printf("Invalid entry for %s: %s, ignoring", key, item)
+			continue
+		}
+		result[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+	return result
+}
+
+func getIntEnv(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil {
+			return parsed
+		}
+		log.Printf("Invalid integer for %s: %s, using default", key, value)
+	}
+	return defaultValue
+}
+
+func getFloatEnv(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+			return parsed
+		}
+		log.Printf("Invalid number for %s: %s, using default", key, value)
+	}
+	return defaultValue
+}
+
+// getPriceMapEnv parses "pool=cpuHourPrice:memoryGiBHourPrice" pairs separated by commas
+func getPriceMapEnv(key string) map[string]models.ResourcePrice {
+	prices := make(map[string]models.ResourcePrice)
+	for pool, value := range getMapEnv(key) {
+		cpu, memory, ok := strings.Cut(value, ":")
+		cpuPrice, cpuErr := strconv.ParseFloat(cpu, 64)
+		memoryPrice, memoryErr := strconv.ParseFloat(memory, 64)
+		if !ok || cpuErr != nil || memoryErr != nil {
+			log.Printf("Invalid price for pool %s in %s: %s, ignoring", pool, key, value)
+			continue
+		}
+		prices[pool] = models.ResourcePrice{CPUHour: cpuPrice, MemoryGiBHour: memoryPrice}
+	}
+	return prices
+}
+
 func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
 	if value := os.Getenv(key); value != "" {
 		// Parse as seconds
 		if seconds, err := strconv.Atoi(value); err == nil {
 			return time.Duration(seconds) * time.Second
 		}
+		// Fall back to Go duration syntax (e.g. "168h")
+		if duration, err := time.ParseDuration(value); err == nil {
+			return duration
+		}
 		log.Printf("Invalid duration for %s: %s, using default", key, value)
 	}
 	return defaultValue
 }
+
+func getBoolEnv(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			return parsed
+		}
+		log.Printf("Invalid boolean for %s: %s, using default", key, value)
+	}
+	return defaultValue
+}
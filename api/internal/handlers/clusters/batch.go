@@ -0,0 +1,53 @@
+package clusters
+
+import (
+	"net/http"
+
+	"kubey/api/internal/services/kubernetes"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetJobs returns the jobs of a cluster, optionally narrowed with "namespace"
+func GetJobs(c *gin.Context) {
+	jobs, err := kubernetes.GetJobs(c.Param("id"), c.Query("namespace"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, jobs)
+}
+
+// GetJob returns a single job
+func GetJob(c *gin.Context) {
+	job, err := kubernetes.GetJob(c.Param("id"), c.Param("namespace"), c.Param("name"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// GetCronJobs returns the cronjobs of a cluster with their job history, optionally narrowed with "namespace"
+func GetCronJobs(c *gin.Context) {
+	cronJobs, err := kubernetes.GetCronJobs(c.Param("id"), c.Query("namespace"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, cronJobs)
+}
+
+// GetCronJob returns a single cronjob with its job history
+func GetCronJob(c *gin.Context) {
+	cronJob, err := kubernetes.GetCronJob(c.Param("id"), c.Param("namespace"), c.Param("name"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, cronJob)
+}
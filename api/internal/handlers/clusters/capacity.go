@@ -0,0 +1,27 @@
+package clusters
+
+import (
+	"net/http"
+
+	"kubey/api/internal/config"
+	"kubey/api/internal/services/kubernetes"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetCapacity returns a handler reporting capacity and headroom per node and node pool.
+// Pools are grouped by the configured node label unless overridden with "poolLabel".
+func GetCapacity(cfg *config.ApiConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		clusterID := c.Param("id")
+		poolLabel := c.DefaultQuery("poolLabel", cfg.NodePoolLabel)
+
+		report, err := kubernetes.GetCapacity(clusterID, poolLabel)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, report)
+	}
+}
@@ -0,0 +1,73 @@
+package clusters
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"kubey/api/internal/handlers/params"
+	"kubey/api/internal/models"
+	"kubey/api/internal/services/kubernetes"
+	"kubey/api/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// defaultChangeWindow is used when no "since" parameter is given
+	defaultChangeWindow = time.Hour
+	// defaultChangeLimit caps the number of changes returned when no limit is given
+	defaultChangeLimit = 500
+)
+
+// GetClusterChanges returns a handler listing recorded spec changes for a cluster
+func GetClusterChanges(s *store.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query, err := parseChangeQuery(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		changes, err := kubernetes.QueryChanges(s, query)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, changes)
+	}
+}
+
+func parseChangeQuery(c *gin.Context) (models.ChangeQuery, error) {
+	now := time.Now()
+	query := models.ChangeQuery{
+		ClusterID: c.Param("id"),
+		Namespace: c.Query("namespace"),
+		Kind:      c.Query("kind"),
+		Name:      c.Query("name"),
+		Since:     now.Add(-defaultChangeWindow),
+		Limit:     defaultChangeLimit,
+	}
+
+	var err error
+	if since := c.Query("since"); since != "" {
+		if query.Since, err = params.ParseTime(since, now); err != nil {
+			return query, err
+		}
+	}
+	if query.Until, err = params.ParseTime(c.Query("until"), now); err != nil {
+		return query, err
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		if query.Limit, err = strconv.Atoi(limit); err != nil || query.Limit < 0 {
+			return query, fmt.Errorf("invalid limit %q", limit)
+		}
+	}
+
+	return query, nil
+}
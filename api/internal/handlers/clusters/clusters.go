@@ -43,30 +43,37 @@ func GetCluster(c *gin.Context) {
 	c.JSON(http.StatusOK, cluster)
 }
 
-// GetClusterNodes returns nodes for a specific cluster
+// GetClusterNodes returns nodes for a specific cluster, with kubelet storage stats if ?stats=true
 func GetClusterNodes(c *gin.Context) {
 	clusterID := c.Param("id")
 
-	nodes, err := kubernetes.GetClusterNodes(clusterID)
+	nodes, err := kubernetes.GetClusterNodes(clusterID, c.Query("stats") == "true")
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": err.Error(),
-		})
+		respondError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, nodes)
 }
 
-// GetClusterPods returns pods for a specific cluster
+// GetNodePods returns the pods scheduled on a node with their resource requests
+func GetNodePods(c *gin.Context) {
+	pods, err := kubernetes.GetNodePods(c.Param("id"), c.Param("node"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, pods)
+}
+
+// GetClusterPods returns pods for a specific cluster, with kubelet storage stats if ?stats=true
 func GetClusterPods(c *gin.Context) {
 	clusterID := c.Param("id")
 
-	pods, err := kubernetes.GetClusterPods(clusterID)
+	pods, err := kubernetes.GetClusterPods(clusterID, c.Query("stats") == "true")
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": err.Error(),
-		})
+		respondError(c, err)
 		return
 	}
 
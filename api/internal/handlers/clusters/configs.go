@@ -0,0 +1,101 @@
+package clusters
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+
+	"kubey/api/internal/audit"
+	"kubey/api/internal/config"
+	"kubey/api/internal/services/kubernetes"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetConfigMaps returns a handler that lists ConfigMaps, optionally narrowed with "namespace"
+func GetConfigMaps(cfg *config.ApiConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		configMaps, err := kubernetes.GetConfigMaps(c.Param("id"), c.Query("namespace"), cfg.ConfigMapMaxValueBytes)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, configMaps)
+	}
+}
+
+// GetConfigMap returns a handler that returns a single ConfigMap
+func GetConfigMap(cfg *config.ApiConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		configMap, err := kubernetes.GetConfigMap(c.Param("id"), c.Param("namespace"), c.Param("name"), cfg.ConfigMapMaxValueBytes)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, configMap)
+	}
+}
+
+// GetSecrets returns the Secrets of a cluster without their values, optionally narrowed with "namespace"
+func GetSecrets(c *gin.Context) {
+	secrets, err := kubernetes.GetSecrets(c.Param("id"), c.Query("namespace"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, secrets)
+}
+
+// GetSecret returns a single Secret without its values
+func GetSecret(c *gin.Context) {
+	secret, err := kubernetes.GetSecret(c.Param("id"), c.Param("namespace"), c.Param("name"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, secret)
+}
+
+// RevealSecret returns a handler that returns the values of a Secret, or of the key in "key".
+// Every attempt is audit logged, including those the reveal policy denies.
+func RevealSecret(cfg *config.ApiConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		clusterID := c.Param("id")
+		namespace := c.Param("namespace")
+		name := c.Param("name")
+		key := c.Query("key")
+		target := fmt.Sprintf("%s/%s/%s", clusterID, namespace, name)
+		if key != "" {
+			target += "/" + key
+		}
+
+		var denied error
+		switch {
+		case !cfg.SecretRevealEnabled:
+			denied = fmt.Errorf("secret reveal is disabled")
+		case len(cfg.SecretRevealNamespaces) > 0 && !slices.Contains(cfg.SecretRevealNamespaces, namespace):
+			denied = fmt.Errorf("secret reveal is not allowed in namespace %s", namespace)
+		}
+		if denied != nil {
+			audit.Log(c, "secret.reveal", target, denied)
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": denied.Error(),
+			})
+			return
+		}
+
+		secret, err := kubernetes.RevealSecret(clusterID, namespace, name, key, cfg.SecretRevealDenyTypes)
+		audit.Log(c, "secret.reveal", target, err)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+
+		c.Header("Cache-Control", "no-store")
+		c.JSON(http.StatusOK, secret)
+	}
+}
@@ -0,0 +1,96 @@
+package clusters
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"kubey/api/internal/config"
+	"kubey/api/internal/handlers/params"
+	"kubey/api/internal/services/kubernetes"
+	"kubey/api/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultCostWindow is used when no "since" parameter is given
+const defaultCostWindow = 7 * 24 * time.Hour
+
+// GetCost returns a handler allocating node cost to namespaces or pod label values.
+// "basis" is requests (default) or usage, "aggregate" is namespace (default) or label:<key>,
+// and "format=csv" returns a CSV export instead of JSON.
+func GetCost(cfg *config.ApiConfig, s *store.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		clusterID := c.Param("id")
+		now := time.Now()
+
+		// Cost usage is recorded by the summary history sampler
+		if !cfg.SummaryHistoryEnabled {
+			respondError(c, fmt.Errorf("%w: cost usage is only recorded with SUMMARY_HISTORY_ENABLED=true", kubernetes.ErrNotRecorded))
+			return
+		}
+
+		opts := kubernetes.CostOptions{
+			Since:        now.Add(-defaultCostWindow),
+			Until:        now,
+			Basis:        c.DefaultQuery("basis", "requests"),
+			AggregateBy:  c.DefaultQuery("aggregate", "namespace"),
+			Currency:     cfg.CostCurrency,
+			DefaultPrice: cfg.CostDefaultPrice,
+			PoolPrices:   cfg.CostPoolPrices,
+		}
+
+		var err error
+		if value := c.Query("since"); value != "" {
+			if opts.Since, err = params.ParseTime(value, now); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+		}
+		if value := c.Query("until"); value != "" {
+			if opts.Until, err = params.ParseTime(value, now); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+		}
+
+		if label, ok := strings.CutPrefix(opts.AggregateBy, "label:"); ok {
+			if !slices.Contains(cfg.CostLabels, label) {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error": fmt.Sprintf("label %q is not recorded for cost allocation, expected one of %v", label, cfg.CostLabels),
+				})
+				return
+			}
+		} else if opts.AggregateBy != "namespace" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("unknown aggregate %q, expected namespace or label:<key>", opts.AggregateBy),
+			})
+			return
+		}
+
+		report, err := kubernetes.GetCostReport(clusterID, opts, s)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+
+		if strings.EqualFold(c.Query("format"), "csv") {
+			data, err := kubernetes.CostReportCSV(report)
+			if err != nil {
+				respondError(c, err)
+				return
+			}
+			c.Header("Content-Disposition", `attachment; filename="cost-`+clusterID+`.csv"`)
+			c.Data(http.StatusOK, "text/csv; charset=utf-8", data)
+			return
+		}
+
+		c.JSON(http.StatusOK, report)
+	}
+}
@@ -0,0 +1,111 @@
+package clusters
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"kubey/api/internal/audit"
+	"kubey/api/internal/config"
+	"kubey/api/internal/models"
+	"kubey/api/internal/services/kubernetes"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DebugPod returns a handler that adds an ephemeral debug container to a pod
+func DebugPod(cfg *config.ApiConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		clusterID := c.Param("id")
+		namespace := c.Param("namespace")
+		podName := c.Param("pod")
+		target := fmt.Sprintf("%s/%s/%s", clusterID, namespace, podName)
+
+		req, ok := bindDebugRequest(c, cfg, "debug.pod", target)
+		if !ok {
+			return
+		}
+
+		session, err := kubernetes.DebugPod(clusterID, namespace, podName, c.GetString("Caller"), req, cfg.DebugStartTimeout)
+		audit.Log(c, "debug.pod", target, err)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, session)
+	}
+}
+
+// DebugNode returns a handler that schedules a privileged debug pod on a node
+func DebugNode(cfg *config.ApiConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		clusterID := c.Param("id")
+		nodeName := c.Param("node")
+		target := fmt.Sprintf("%s/%s", clusterID, nodeName)
+
+		req, ok := bindDebugRequest(c, cfg, "debug.node", target)
+		if !ok {
+			return
+		}
+		if req.Namespace != "" && req.Namespace != cfg.DebugNamespace {
+			err := fmt.Errorf("%w: node debug pods can only run in namespace %s", kubernetes.ErrForbidden, cfg.DebugNamespace)
+			audit.Log(c, "debug.node", target, err)
+			respondError(c, err)
+			return
+		}
+		req.Namespace = cfg.DebugNamespace
+
+		session, err := kubernetes.DebugNode(clusterID, nodeName, c.GetString("Caller"), req, cfg.DebugStartTimeout)
+		audit.Log(c, "debug.node", target, err)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, session)
+	}
+}
+
+// bindDebugRequest parses the debug payload and enforces the configured debug policy.
+// It writes the error response itself and returns false if the request must not proceed.
+func bindDebugRequest(c *gin.Context, cfg *config.ApiConfig, action, target string) (models.DebugRequest, bool) {
+	var req models.DebugRequest
+
+	if !cfg.DebugEnabled {
+		err := fmt.Errorf("debug sessions are disabled")
+		audit.Log(c, action, target, err)
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": err.Error(),
+		})
+		return req, false
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return req, false
+	}
+
+	if req.Image == "" {
+		req.Image = cfg.DebugDefaultImage
+	}
+
+	if err := kubernetes.CheckDebugImage(req.Image, cfg.DebugAllowedImages); err != nil {
+		audit.Log(c, action, target, err)
+		respondError(c, err)
+		return req, false
+	}
+
+	// Waiting for the debug container can take longer than the server's write timeout
+	deadline := time.Now().Add(cfg.DebugStartTimeout + cfg.HTTPWriteTimeout)
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(deadline); err != nil {
+		log.Printf("Failed to extend write deadline for %s: %v", action, err)
+	}
+
+	return req, true
+}
@@ -0,0 +1,29 @@
+package clusters
+
+import (
+	"errors"
+	"net/http"
+
+	"kubey/api/internal/services/kubernetes"
+	"kubey/api/internal/services/prometheus"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondError writes err as a JSON error, mapping service sentinel errors to HTTP status codes
+func respondError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, kubernetes.ErrClusterNotFound), errors.Is(err, kubernetes.ErrResourceNotFound),
+		errors.Is(err, prometheus.ErrNotConfigured), errors.Is(err, kubernetes.ErrNotRecorded):
+		status = http.StatusNotFound
+	case errors.Is(err, kubernetes.ErrForbidden):
+		status = http.StatusForbidden
+	case errors.Is(err, kubernetes.ErrInvalidArgument), errors.Is(err, prometheus.ErrInvalidQuery):
+		status = http.StatusBadRequest
+	}
+
+	c.JSON(status, gin.H{
+		"error": err.Error(),
+	})
+}
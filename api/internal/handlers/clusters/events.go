@@ -0,0 +1,72 @@
+package clusters
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"kubey/api/internal/handlers/params"
+	"kubey/api/internal/models"
+	"kubey/api/internal/services/kubernetes"
+	"kubey/api/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultEventLimit caps the number of events returned when no limit is given
+const defaultEventLimit = 500
+
+// GetEvents returns a handler that searches recorded events across all clusters.
+// The cluster can be narrowed with the "cluster" query parameter or the ":id" route parameter.
+func GetEvents(s *store.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query, err := parseEventQuery(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		events, err := kubernetes.QueryEvents(s, query)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, events)
+	}
+}
+
+func parseEventQuery(c *gin.Context) (models.EventQuery, error) {
+	now := time.Now()
+	query := models.EventQuery{
+		ClusterID: c.Param("id"),
+		Namespace: c.Query("namespace"),
+		Kind:      c.Query("kind"),
+		Name:      c.Query("name"),
+		Reason:    c.Query("reason"),
+		Type:      c.Query("type"),
+		Limit:     defaultEventLimit,
+	}
+	if query.ClusterID == "" {
+		query.ClusterID = c.Query("cluster")
+	}
+
+	var err error
+	if query.Since, err = params.ParseTime(c.Query("since"), now); err != nil {
+		return query, err
+	}
+	if query.Until, err = params.ParseTime(c.Query("until"), now); err != nil {
+		return query, err
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		if query.Limit, err = strconv.Atoi(limit); err != nil || query.Limit < 0 {
+			return query, fmt.Errorf("invalid limit %q", limit)
+		}
+	}
+
+	return query, nil
+}
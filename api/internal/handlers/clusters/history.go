@@ -0,0 +1,51 @@
+package clusters
+
+import (
+	"net/http"
+
+	"kubey/api/internal/handlers/params"
+	"kubey/api/internal/services/kubernetes"
+	"kubey/api/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxHistoryPoints bounds range/step so a single request cannot ask for an unbounded series
+const maxHistoryPoints = 10000
+
+// GetSummaryHistory returns a handler serving a cluster's recorded summary history.
+// "range" (default 24h) selects how far back to go and "step" (default 5m) the averaging window.
+func GetSummaryHistory(s *store.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		clusterID := c.Param("id")
+
+		window, err := params.ParseDuration(c.DefaultQuery("range", "24h"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		step, err := params.ParseDuration(c.DefaultQuery("step", "5m"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		if step > 0 && window/step > maxHistoryPoints {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "too many points for range, increase the step",
+			})
+			return
+		}
+
+		history, err := kubernetes.GetSummaryHistory(clusterID, window, step, s)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, history)
+	}
+}
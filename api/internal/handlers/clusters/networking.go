@@ -0,0 +1,86 @@
+package clusters
+
+import (
+	"net/http"
+
+	"kubey/api/internal/services/kubernetes"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetIngresses returns the ingresses of a cluster, optionally narrowed with "namespace"
+func GetIngresses(c *gin.Context) {
+	ingresses, err := kubernetes.GetIngresses(c.Param("id"), c.Query("namespace"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ingresses)
+}
+
+// GetIngress returns a single ingress
+func GetIngress(c *gin.Context) {
+	ingress, err := kubernetes.GetIngress(c.Param("id"), c.Param("namespace"), c.Param("name"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ingress)
+}
+
+// GetIngressClasses returns the ingress classes of a cluster
+func GetIngressClasses(c *gin.Context) {
+	classes, err := kubernetes.GetIngressClasses(c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, classes)
+}
+
+// GetGateways returns the Gateway API gateways of a cluster, optionally narrowed with "namespace"
+func GetGateways(c *gin.Context) {
+	gateways, err := kubernetes.GetGateways(c.Param("id"), c.Query("namespace"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gateways)
+}
+
+// GetGateway returns a single Gateway API gateway
+func GetGateway(c *gin.Context) {
+	gateway, err := kubernetes.GetGateway(c.Param("id"), c.Param("namespace"), c.Param("name"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gateway)
+}
+
+// GetHTTPRoutes returns the Gateway API HTTP routes of a cluster, optionally narrowed with "namespace"
+func GetHTTPRoutes(c *gin.Context) {
+	routes, err := kubernetes.GetHTTPRoutes(c.Param("id"), c.Query("namespace"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, routes)
+}
+
+// GetHTTPRoute returns a single Gateway API HTTP route
+func GetHTTPRoute(c *gin.Context) {
+	route, err := kubernetes.GetHTTPRoute(c.Param("id"), c.Param("namespace"), c.Param("name"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, route)
+}
@@ -0,0 +1,73 @@
+package clusters
+
+import (
+	"net/http"
+	"time"
+
+	"kubey/api/internal/config"
+	"kubey/api/internal/handlers/params"
+	"kubey/api/internal/services/prometheus"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetMetricSeries returns a handler that queries the cluster's Prometheus for a predefined
+// metric ("cpu", "memory", "network_receive", "network_transmit", "restarts") per pod,
+// container, node or namespace. The window is set with "range" (default 1h), "end" and "step" (default 1m).
+func GetMetricSeries(cfg *config.ApiConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		clusterID := c.Param("id")
+
+		baseURL, ok := cfg.PrometheusURLs[clusterID]
+		if !ok {
+			respondError(c, prometheus.ErrNotConfigured)
+			return
+		}
+
+		window, err := params.ParseDuration(c.DefaultQuery("range", "1h"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		step, err := params.ParseDuration(c.DefaultQuery("step", "1m"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		now := time.Now()
+		end := now
+		if value := c.Query("end"); value != "" {
+			if end, err = params.ParseTime(value, now); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+		}
+
+		query := prometheus.SeriesQuery{
+			Scope:     c.Param("scope"),
+			Metric:    c.Param("metric"),
+			Namespace: c.Query("namespace"),
+			Pod:       c.Query("pod"),
+			Container: c.Query("container"),
+			Node:      c.Query("node"),
+			Start:     end.Add(-window),
+			End:       end,
+			Step:      step,
+		}
+
+		client := prometheus.NewClient(baseURL, cfg.PrometheusTimeout)
+		series, err := prometheus.QuerySeries(c.Request.Context(), client, clusterID, query)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, series)
+	}
+}
@@ -0,0 +1,76 @@
+package clusters
+
+import (
+	"net/http"
+	"strconv"
+
+	"kubey/api/internal/services/kubernetes"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// defaultResourceLimit is the page size of resource tables when no limit is given
+	defaultResourceLimit = 500
+	// maxResourceLimit caps the page size a client can request
+	maxResourceLimit = 5000
+)
+
+// GetResourceTypes returns every listable resource type the cluster serves, including custom resources
+func GetResourceTypes(c *gin.Context) {
+	types, err := kubernetes.GetResourceTypes(c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types)
+}
+
+// GetResourceTable lists the objects of any resource as a table, optionally narrowed with "namespace".
+// Pages are requested with "limit" and the "continue" token of the previous page.
+func GetResourceTable(c *gin.Context) {
+	limit := int64(defaultResourceLimit)
+	if value := c.Query("limit"); value != "" {
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || n <= 0 || n > maxResourceLimit {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "limit must be between 1 and " + strconv.Itoa(maxResourceLimit),
+			})
+			return
+		}
+		limit = n
+	}
+
+	table, err := kubernetes.GetResourceTable(c.Param("id"), c.Param("group"), c.Param("version"), c.Param("resource"),
+		c.Query("namespace"), limit, c.Query("continue"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, table)
+}
+
+// GetResourceObject returns a single object of any resource; namespaced resources need "namespace"
+func GetResourceObject(c *gin.Context) {
+	object, err := kubernetes.GetResourceObject(c.Param("id"), c.Param("group"), c.Param("version"), c.Param("resource"),
+		c.Query("namespace"), c.Param("name"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, object)
+}
+
+// GetAPIResources returns the API groups, versions and resources the cluster serves
+func GetAPIResources(c *gin.Context) {
+	resources, err := kubernetes.GetAPIResources(c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resources)
+}
@@ -0,0 +1,89 @@
+package clusters
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"kubey/api/internal/config"
+	"kubey/api/internal/handlers/params"
+	"kubey/api/internal/services/kubernetes"
+	"kubey/api/internal/services/prometheus"
+	"kubey/api/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetRightSizing returns a handler recommending container requests and limits for Deployments
+// and StatefulSets. "source" selects the usage history: "prometheus", "kubey" (kubey's own
+// samples) or "auto" (default), which prefers Prometheus when one is configured for the cluster.
+func GetRightSizing(cfg *config.ApiConfig, s *store.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		clusterID := c.Param("id")
+
+		opts := kubernetes.RightSizingOptions{
+			Namespace:         c.Query("namespace"),
+			PrometheusTimeout: cfg.PrometheusTimeout,
+			UsageRetention:    cfg.SummaryHistoryRawRetention,
+		}
+
+		var err error
+		if opts.Window, err = params.ParseDuration(c.DefaultQuery("window", "7d")); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		parseFloat := func(name, defaultValue string, min, max float64) (float64, error) {
+			value, err := strconv.ParseFloat(c.DefaultQuery(name, defaultValue), 64)
+			if err != nil || value < min || value > max {
+				return 0, fmt.Errorf("%s must be a number between %g and %g", name, min, max)
+			}
+			return value, nil
+		}
+		if opts.CPUPercentile, err = parseFloat("cpuPercentile", "95", 1, 100); err == nil {
+			if opts.MemoryPercentile, err = parseFloat("memoryPercentile", "99", 1, 100); err == nil {
+				opts.Headroom, err = parseFloat("headroom", "0.15", 0, 10)
+			}
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		prometheusURL, hasPrometheus := cfg.PrometheusURLs[clusterID]
+		switch source := c.DefaultQuery("source", "auto"); source {
+		case "auto":
+			opts.PrometheusURL = prometheusURL
+		case "prometheus":
+			if !hasPrometheus {
+				respondError(c, prometheus.ErrNotConfigured)
+				return
+			}
+			opts.PrometheusURL = prometheusURL
+		case "kubey":
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("unknown source %q, expected auto, kubey or prometheus", source),
+			})
+			return
+		}
+
+		// kubey's own usage samples are recorded by the summary history sampler
+		if opts.PrometheusURL == "" && !cfg.SummaryHistoryEnabled {
+			respondError(c, fmt.Errorf("%w: workload usage is only recorded with SUMMARY_HISTORY_ENABLED=true", kubernetes.ErrNotRecorded))
+			return
+		}
+
+		report, err := kubernetes.GetRightSizing(clusterID, opts, s)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, report)
+	}
+}
@@ -0,0 +1,64 @@
+package clusters
+
+import (
+	"net/http"
+
+	"kubey/api/internal/services/kubernetes"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetPersistentVolumeClaims returns the persistent volume claims of a cluster, optionally narrowed with "namespace"
+func GetPersistentVolumeClaims(c *gin.Context) {
+	claims, err := kubernetes.GetPersistentVolumeClaims(c.Param("id"), c.Query("namespace"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, claims)
+}
+
+// GetPersistentVolumeClaim returns a single persistent volume claim
+func GetPersistentVolumeClaim(c *gin.Context) {
+	claim, err := kubernetes.GetPersistentVolumeClaim(c.Param("id"), c.Param("namespace"), c.Param("name"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, claim)
+}
+
+// GetPersistentVolumes returns the persistent volumes of a cluster
+func GetPersistentVolumes(c *gin.Context) {
+	volumes, err := kubernetes.GetPersistentVolumes(c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, volumes)
+}
+
+// GetPersistentVolume returns a single persistent volume
+func GetPersistentVolume(c *gin.Context) {
+	volume, err := kubernetes.GetPersistentVolume(c.Param("id"), c.Param("name"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, volume)
+}
+
+// GetStorageClasses returns the storage classes of a cluster
+func GetStorageClasses(c *gin.Context) {
+	classes, err := kubernetes.GetStorageClasses(c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, classes)
+}
@@ -0,0 +1,61 @@
+package clusters
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"kubey/api/internal/metrics"
+	"kubey/api/internal/services/kubernetes"
+
+	"github.com/gin-gonic/gin"
+)
+
+// summaryHeartbeatInterval keeps idle streams open through proxies
+const summaryHeartbeatInterval = 15 * time.Second
+
+// GetLiveSummaries returns the live summary of every watched cluster
+func GetLiveSummaries(c *gin.Context) {
+	c.JSON(http.StatusOK, kubernetes.GetLiveSummaries())
+}
+
+// StreamSummaries pushes live cluster summaries as Server-Sent Events.
+// A "snapshot" event with every cluster is sent first, followed by "update"
+// events containing only the clusters whose summary changed.
+func StreamSummaries(c *gin.Context) {
+	// Streams outlive the server's write timeout
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "streaming not supported",
+		})
+		return
+	}
+
+	updates, unsubscribe := kubernetes.SubscribeSummaries()
+	defer unsubscribe()
+	defer metrics.SummaryStreamConnected()()
+
+	heartbeat := time.NewTicker(summaryHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	c.SSEvent("snapshot", kubernetes.GetLiveSummaries())
+	c.Writer.Flush()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case batch, ok := <-updates:
+			if !ok {
+				return false
+			}
+			c.SSEvent("update", batch)
+			return true
+		case <-heartbeat.C:
+			_, err := io.WriteString(w, ": heartbeat\n\n")
+			return err == nil
+		}
+	})
+}
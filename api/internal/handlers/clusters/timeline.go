@@ -0,0 +1,71 @@
+package clusters
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"kubey/api/internal/handlers/params"
+	"kubey/api/internal/models"
+	"kubey/api/internal/services/kubernetes"
+	"kubey/api/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultTimelineWindow is used when no "since" parameter is given
+const defaultTimelineWindow = 24 * time.Hour
+
+// GetTimeline returns a handler that builds an incident timeline for a namespace or workload.
+// The workload is selected with the "kind" and "name" query parameters; "format=markdown"
+// returns a Markdown document instead of JSON.
+func GetTimeline(s *store.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		clusterID := c.Param("id")
+		namespace := c.Param("namespace")
+		now := time.Now()
+
+		since := now.Add(-defaultTimelineWindow)
+		until := now
+		var err error
+		if value := c.Query("since"); value != "" {
+			if since, err = params.ParseTime(value, now); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+		}
+		if value := c.Query("until"); value != "" {
+			if until, err = params.ParseTime(value, now); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+		}
+
+		var workload *models.ObjectReference
+		if name := c.Query("name"); name != "" {
+			workload = &models.ObjectReference{
+				Kind:      c.DefaultQuery("kind", "Deployment"),
+				Namespace: namespace,
+				Name:      name,
+			}
+		}
+
+		timeline, err := kubernetes.GetTimeline(clusterID, namespace, workload, since, until, s)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+
+		if strings.EqualFold(c.Query("format"), "markdown") {
+			c.Header("Content-Disposition", `attachment; filename="timeline-`+namespace+`.md"`)
+			c.Data(http.StatusOK, "text/markdown; charset=utf-8", []byte(kubernetes.TimelineMarkdown(timeline)))
+			return
+		}
+
+		c.JSON(http.StatusOK, timeline)
+	}
+}
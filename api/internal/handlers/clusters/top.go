@@ -0,0 +1,53 @@
+package clusters
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"kubey/api/internal/services/kubernetes"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// defaultTopLimit is the number of entries returned when no limit is given
+	defaultTopLimit = 10
+	// maxTopLimit caps the limit a client can request
+	maxTopLimit = 500
+)
+
+// GetTop ranks pods, containers, namespaces or nodes across clusters by CPU, memory, restarts or age.
+// Clusters can be narrowed with repeated or comma-separated "cluster" query parameters.
+func GetTop(c *gin.Context) {
+	opts := kubernetes.TopOptions{
+		Kind:   c.DefaultQuery("kind", "pod"),
+		SortBy: c.DefaultQuery("sort", "cpu"),
+		Limit:  defaultTopLimit,
+	}
+	for _, value := range c.QueryArray("cluster") {
+		for _, clusterID := range strings.Split(value, ",") {
+			if clusterID = strings.TrimSpace(clusterID); clusterID != "" {
+				opts.ClusterIDs = append(opts.ClusterIDs, clusterID)
+			}
+		}
+	}
+	if limit := c.Query("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 || n > maxTopLimit {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "limit must be between 1 and " + strconv.Itoa(maxTopLimit),
+			})
+			return
+		}
+		opts.Limit = n
+	}
+
+	report, err := kubernetes.GetTop(opts)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
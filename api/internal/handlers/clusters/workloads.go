@@ -0,0 +1,75 @@
+package clusters
+
+import (
+	"net/http"
+
+	"kubey/api/internal/services/kubernetes"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetStatefulSets returns the statefulsets of a cluster, optionally narrowed with "namespace"
+func GetStatefulSets(c *gin.Context) {
+	statefulSets, err := kubernetes.GetStatefulSets(c.Param("id"), c.Query("namespace"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, statefulSets)
+}
+
+// GetStatefulSet returns a single statefulset
+func GetStatefulSet(c *gin.Context) {
+	statefulSet, err := kubernetes.GetStatefulSet(c.Param("id"), c.Param("namespace"), c.Param("name"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, statefulSet)
+}
+
+// GetDaemonSets returns the daemonsets of a cluster, optionally narrowed with "namespace"
+func GetDaemonSets(c *gin.Context) {
+	daemonSets, err := kubernetes.GetDaemonSets(c.Param("id"), c.Query("namespace"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, daemonSets)
+}
+
+// GetDaemonSet returns a single daemonset
+func GetDaemonSet(c *gin.Context) {
+	daemonSet, err := kubernetes.GetDaemonSet(c.Param("id"), c.Param("namespace"), c.Param("name"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, daemonSet)
+}
+
+// GetReplicaSets returns the replicasets of a cluster, optionally narrowed with "namespace"
+func GetReplicaSets(c *gin.Context) {
+	replicaSets, err := kubernetes.GetReplicaSets(c.Param("id"), c.Query("namespace"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, replicaSets)
+}
+
+// GetReplicaSet returns a single replicaset
+func GetReplicaSet(c *gin.Context) {
+	replicaSet, err := kubernetes.GetReplicaSet(c.Param("id"), c.Param("namespace"), c.Param("name"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, replicaSet)
+}
@@ -0,0 +1,9 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Metrics serves kubey's own metrics in the Prometheus exposition format
+var Metrics = gin.WrapH(promhttp.Handler())
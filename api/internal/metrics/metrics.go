@@ -0,0 +1,193 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// namespace prefixes every metric exported by kubey
+const namespace = "kubey"
+
+var (
+	httpRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests served, by method, route and status code.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency, by method and route.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	apiserverRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "apiserver_requests_total",
+		Help:      "Requests to Kubernetes API servers, by cluster, verb and status code (\"error\" for transport failures).",
+	}, []string{"cluster", "verb", "code"})
+
+	apiserverDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "apiserver_request_duration_seconds",
+		Help:      "Kubernetes API server request latency until response headers, by cluster and verb.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"cluster", "verb"})
+
+	activeWatches = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "apiserver_active_watches",
+		Help:      "Open watch streams to Kubernetes API servers, by cluster.",
+	}, []string{"cluster"})
+
+	clusterOnline = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "cluster_online",
+		Help:      "1 if the cluster's API server answered the most recent request, 0 if it was unreachable or its health or version endpoint returned a server error.",
+	}, []string{"cluster"})
+
+	cacheLookups = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_lookups_total",
+		Help:      "Cache lookups, by cache and result (hit or miss).",
+	}, []string{"cache", "result"})
+
+	summaryStreamClients = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "summary_stream_clients",
+		Help:      "Clients connected to the live summary stream.",
+	})
+)
+
+// ObserveHTTPRequest records a served HTTP request
+func ObserveHTTPRequest(method, route string, status int, duration time.Duration) {
+	httpRequests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	httpDuration.WithLabelValues(method, route).Observe(duration.Seconds())
+}
+
+// CacheLookup records a cache hit or miss
+func CacheLookup(cache string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	cacheLookups.WithLabelValues(cache, result).Inc()
+}
+
+// SummaryStreamConnected tracks a live summary stream client; call the returned function on disconnect
+func SummaryStreamConnected() func() {
+	summaryStreamClients.Inc()
+	return summaryStreamClients.Dec
+}
+
+// InstrumentTransport returns a transport wrapper recording API server latency, status codes,
+// open watches and reachability for the given cluster
+func InstrumentTransport(cluster string) func(http.RoundTripper) http.RoundTripper {
+	return func(rt http.RoundTripper) http.RoundTripper {
+		return &instrumentedTransport{cluster: cluster, next: rt}
+	}
+}
+
+type instrumentedTransport struct {
+	cluster string
+	next    http.RoundTripper
+}
+
+func (t *instrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	verb := requestVerb(req)
+	start := time.Now()
+
+	resp, err := t.next.RoundTrip(req)
+
+	apiserverDuration.WithLabelValues(t.cluster, verb).Observe(time.Since(start).Seconds())
+	if err != nil {
+		apiserverRequests.WithLabelValues(t.cluster, verb, "error").Inc()
+		clusterOnline.WithLabelValues(t.cluster).Set(0)
+		return nil, err
+	}
+
+	apiserverRequests.WithLabelValues(t.cluster, verb, strconv.Itoa(resp.StatusCode)).Inc()
+	// Other server errors, such as a 503 from an unavailable aggregated API, still mean the API server answered
+	if resp.StatusCode >= http.StatusInternalServerError && isHealthPath(req.URL.Path) {
+		clusterOnline.WithLabelValues(t.cluster).Set(0)
+	} else {
+		clusterOnline.WithLabelValues(t.cluster).Set(1)
+	}
+
+	if verb == "watch" && resp.StatusCode == http.StatusOK {
+		gauge := activeWatches.WithLabelValues(t.cluster)
+		gauge.Inc()
+		resp.Body = &watchBody{ReadCloser: resp.Body, done: gauge.Dec}
+	}
+
+	return resp, nil
+}
+
+// isHealthPath reports whether path is an API server health or version endpoint
+func isHealthPath(path string) bool {
+	switch strings.TrimSuffix(path, "/") {
+	case "/healthz", "/livez", "/readyz", "/version":
+		return true
+	}
+	return false
+}
+
+// watchBody decrements the active watch gauge once when the stream is closed
+type watchBody struct {
+	io.ReadCloser
+	once sync.Once
+	done func()
+}
+
+func (b *watchBody) Close() error {
+	b.once.Do(b.done)
+	return b.ReadCloser.Close()
+}
+
+// requestVerb maps an API server request to a Kubernetes verb. GET requests are "list" when
+// they address a collection and "get" when they address a named object or subresource.
+func requestVerb(req *http.Request) string {
+	switch req.Method {
+	case http.MethodPost:
+		return "create"
+	case http.MethodPut:
+		return "update"
+	case http.MethodPatch:
+		return "patch"
+	case http.MethodDelete:
+		return "delete"
+	case http.MethodGet:
+	default:
+		return strings.ToLower(req.Method)
+	}
+
+	if watch := req.URL.Query().Get("watch"); watch == "true" || watch == "1" {
+		return "watch"
+	}
+
+	// Strip the group/version prefix: /api/v1/... or /apis/<group>/<version>/...
+	parts := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	switch {
+	case len(parts) >= 2 && parts[0] == "api":
+		parts = parts[2:]
+	case len(parts) >= 3 && parts[0] == "apis":
+		parts = parts[3:]
+	default:
+		return "get"
+	}
+	if len(parts) > 2 && parts[0] == "namespaces" {
+		parts = parts[2:]
+	}
+	if len(parts) == 1 {
+		return "list"
+	}
+	return "get"
+}
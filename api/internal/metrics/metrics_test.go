@@ -0,0 +1,94 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestRequestVerb(t *testing.T) {
+	cases := []struct {
+		method, url, want string
+	}{
+		{http.MethodGet, "/api/v1/pods", "list"},
+		{http.MethodGet, "/api/v1/namespaces", "list"},
+		{http.MethodGet, "/api/v1/namespaces/shop", "get"},
+		{http.MethodGet, "/api/v1/namespaces/shop/pods", "list"},
+		{http.MethodGet, "/api/v1/namespaces/shop/pods/web-1/log", "get"},
+		{http.MethodGet, "/apis/apps/v1/namespaces/shop/deployments/web", "get"},
+		{http.MethodGet, "/apis/metrics.k8s.io/v1beta1/nodes", "list"},
+		{http.MethodGet, "/api/v1/pods?watch=true&resourceVersion=1", "watch"},
+		{http.MethodGet, "/version", "get"},
+		{http.MethodPatch, "/api/v1/namespaces/shop/pods/web-1/ephemeralcontainers", "patch"},
+		{http.MethodPost, "/api/v1/namespaces/default/pods", "create"},
+	}
+
+	for _, tc := range cases {
+		req := httptest.NewRequest(tc.method, tc.url, nil)
+		if got := requestVerb(req); got != tc.want {
+			t.Errorf("requestVerb(%s %s) = %q, want %q", tc.method, tc.url, got, tc.want)
+		}
+	}
+}
+
+func TestInstrumentTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/healthz" || r.URL.Path == "/apis/metrics.k8s.io/v1beta1/nodes" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: InstrumentTransport("context-test")(http.DefaultTransport)}
+
+	resp, err := client.Get(server.URL + "/api/v1/pods?watch=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := testutil.ToFloat64(activeWatches.WithLabelValues("context-test")); got != 1 {
+		t.Errorf("active watches = %v, want 1", got)
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	resp.Body.Close()
+	if got := testutil.ToFloat64(activeWatches.WithLabelValues("context-test")); got != 0 {
+		t.Errorf("active watches after close = %v, want 0", got)
+	}
+	if got := testutil.ToFloat64(clusterOnline.WithLabelValues("context-test")); got != 1 {
+		t.Errorf("cluster online = %v, want 1", got)
+	}
+
+	// An unavailable aggregated API is a server error, but the API server itself answered
+	resp, err = client.Get(server.URL + "/apis/metrics.k8s.io/v1beta1/nodes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if got := testutil.ToFloat64(clusterOnline.WithLabelValues("context-test")); got != 1 {
+		t.Errorf("cluster online after aggregated API 503 = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(apiserverRequests.WithLabelValues("context-test", "list", "503")); got != 1 {
+		t.Errorf("503 count = %v, want 1", got)
+	}
+
+	resp, err = client.Get(server.URL + "/healthz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if got := testutil.ToFloat64(clusterOnline.WithLabelValues("context-test")); got != 0 {
+		t.Errorf("cluster online after /healthz 503 = %v, want 0", got)
+	}
+
+	if _, err := client.Get("http://127.0.0.1:1/version"); err == nil {
+		t.Fatal("expected a transport error")
+	}
+	if got := testutil.ToFloat64(apiserverRequests.WithLabelValues("context-test", "get", "error")); got != 1 {
+		t.Errorf("transport error count = %v, want 1", got)
+	}
+}
@@ -0,0 +1,26 @@
+package metrics
+
+import (
+	"time"
+
+	"kubey/api/internal/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics returns a Gin middleware recording request latency and status by route.
+// Requests that match no route are grouped under "unmatched" to keep label cardinality bounded.
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		metrics.ObserveHTTPRequest(c.Request.Method, route, c.Writer.Status(), time.Since(start))
+	}
+}
@@ -0,0 +1,18 @@
+package request
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// Caller returns a Gin middleware that stores the caller identity set by an authenticating proxy
+// in front of kubey. The header is only trustworthy when the proxy strips it from client requests.
+func Caller(header string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if header != "" {
+			if caller := c.GetHeader(header); caller != "" {
+				c.Set("Caller", caller)
+			}
+		}
+		c.Next()
+	}
+}
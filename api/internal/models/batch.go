@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// KubeJob represents a Kubernetes job
+type KubeJob struct {
+	Name           string            `json:"name"`
+	Namespace      string            `json:"namespace"`
+	Completions    *int32            `json:"completions,omitempty"` // Unset for work queue jobs
+	Parallelism    int32             `json:"parallelism"`
+	CompletionMode string            `json:"completionMode,omitempty"`
+	BackoffLimit   int32             `json:"backoffLimit"`
+	Active         int32             `json:"active"`
+	Ready          int32             `json:"ready"`
+	Succeeded      int32             `json:"succeeded"`
+	Failed         int32             `json:"failed"`
+	Suspended      bool              `json:"suspended"`
+	StartTime      *time.Time        `json:"startTime,omitempty"`
+	CompletionTime *time.Time        `json:"completionTime,omitempty"`
+	Duration       float64           `json:"duration,omitempty"` // Seconds from start to completion, or to now while running
+	CronJob        string            `json:"cronJob,omitempty"`  // Name of the cronjob that created the job
+	Role           string            `json:"role"`
+	Status         ResourceStatus    `json:"status"`
+	Labels         map[string]string `json:"labels"`
+	CreatedAt      time.Time         `json:"createdAt"`
+}
+
+// KubeCronJob represents a Kubernetes cronjob with the jobs it created
+type KubeCronJob struct {
+	Name                       string            `json:"name"`
+	Namespace                  string            `json:"namespace"`
+	Schedule                   string            `json:"schedule"`
+	TimeZone                   string            `json:"timeZone,omitempty"`
+	Suspended                  bool              `json:"suspended"`
+	ConcurrencyPolicy          string            `json:"concurrencyPolicy"`
+	Active                     int               `json:"active"`
+	LastScheduleTime           *time.Time        `json:"lastScheduleTime,omitempty"`
+	LastSuccessfulTime         *time.Time        `json:"lastSuccessfulTime,omitempty"`
+	NextScheduleTime           *time.Time        `json:"nextScheduleTime,omitempty"` // Unset while suspended or when the schedule is invalid
+	SuccessfulJobsHistoryLimit int32             `json:"successfulJobsHistoryLimit"`
+	FailedJobsHistoryLimit     int32             `json:"failedJobsHistoryLimit"`
+	Jobs                       []KubeJob         `json:"jobs"` // Newest first
+	Role                       string            `json:"role"`
+	Status                     ResourceStatus    `json:"status"`
+	Labels                     map[string]string `json:"labels"`
+	CreatedAt                  time.Time         `json:"createdAt"`
+}
@@ -0,0 +1,54 @@
+package models
+
+// ResourceAmounts is a parsed amount of node resources
+type ResourceAmounts struct {
+	CPU    float64 `json:"cpu"`    // In millicores
+	Memory int64   `json:"memory"` // In bytes
+	Pods   int64   `json:"pods"`
+}
+
+// PodFit describes the largest pod that still fits on a node
+type PodFit struct {
+	Node   string  `json:"node"`
+	CPU    float64 `json:"cpu"`    // In millicores
+	Memory int64   `json:"memory"` // In bytes
+}
+
+// NodeCapacityReport is the capacity and headroom of a single node
+type NodeCapacityReport struct {
+	Name             string          `json:"name"`
+	Pool             string          `json:"pool"`
+	Ready            bool            `json:"ready"`
+	Capacity         ResourceAmounts `json:"capacity"`
+	Allocatable      ResourceAmounts `json:"allocatable"`
+	Requested        ResourceAmounts `json:"requested"`
+	Limits           ResourceAmounts `json:"limits"`
+	Headroom         ResourceAmounts `json:"headroom"`         // Allocatable minus requested
+	CPUOvercommit    float64         `json:"cpuOvercommit"`    // CPU limits / allocatable
+	MemoryOvercommit float64         `json:"memoryOvercommit"` // Memory limits / allocatable
+}
+
+// NodePoolCapacity aggregates capacity for the nodes sharing a pool label value
+type NodePoolCapacity struct {
+	Name             string          `json:"name"`
+	Nodes            []string        `json:"nodes"`
+	Allocatable      ResourceAmounts `json:"allocatable"`
+	Requested        ResourceAmounts `json:"requested"`
+	Limits           ResourceAmounts `json:"limits"`
+	Headroom         ResourceAmounts `json:"headroom"`
+	CPUOvercommit    float64         `json:"cpuOvercommit"`
+	MemoryOvercommit float64         `json:"memoryOvercommit"`
+
+	// Largest pods that still fit on a single ready node: the one with the most free CPU
+	// and the one with the most free memory, each with the other resource free on that node
+	LargestCPUFit    *PodFit `json:"largestCpuFit,omitempty"`
+	LargestMemoryFit *PodFit `json:"largestMemoryFit,omitempty"`
+}
+
+// CapacityReport is the capacity and headroom of a cluster, per node and per node pool
+type CapacityReport struct {
+	ClusterID string               `json:"clusterId"`
+	PoolLabel string               `json:"poolLabel"`
+	Pools     []NodePoolCapacity   `json:"pools"`
+	Nodes     []NodeCapacityReport `json:"nodes"`
+}
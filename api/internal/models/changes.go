@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// FieldChange is a single field-level difference between two versions of an object
+type FieldChange struct {
+	Path string `json:"path"` // e.g. spec.template.spec.containers[app].image
+	Old  string `json:"old,omitempty"`
+	New  string `json:"new,omitempty"`
+}
+
+// ResourceChange is a recorded spec-level change to a watched object
+type ResourceChange struct {
+	ClusterID       string          `json:"clusterId"`
+	Object          ObjectReference `json:"object"`
+	Action          string          `json:"action"`     // Created, Updated, Deleted
+	Categories      []string        `json:"categories"` // image, replicas, env, config, spec
+	Fields          []FieldChange   `json:"fields"`
+	Manager         string          `json:"manager,omitempty"` // Field manager from managedFields, where known
+	Generation      int64           `json:"generation,omitempty"`
+	ResourceVersion string          `json:"resourceVersion"`
+	Timestamp       time.Time       `json:"timestamp"`
+}
+
+// ChangeQuery filters recorded changes; zero values match everything
+type ChangeQuery struct {
+	ClusterID string
+	Namespace string
+	Kind      string
+	Name      string
+	Since     time.Time
+	Until     time.Time
+	Limit     int
+}
@@ -0,0 +1,63 @@
+package models
+
+import "time"
+
+// ConfigKey is one key of a ConfigMap or Secret
+type ConfigKey struct {
+	Key    string `json:"key"`
+	Size   int    `json:"size"`             // Bytes
+	Binary bool   `json:"binary,omitempty"` // From the ConfigMap binaryData field
+
+	// Value is only set for ConfigMap text keys, cut at the configured size cap
+	Value     *string `json:"value,omitempty"`
+	Truncated bool    `json:"truncated,omitempty"`
+}
+
+// ConfigReference is a pod that mounts or references a ConfigMap or Secret
+type ConfigReference struct {
+	Pod       string `json:"pod"`
+	Container string `json:"container,omitempty"` // Empty for volumes and image pull secrets
+	Via       string `json:"via"`                 // volume, env, envFrom or imagePullSecret
+	Source    string `json:"source,omitempty"`    // Volume or environment variable name
+	Key       string `json:"key,omitempty"`       // Key read by an env variable
+}
+
+// KubeConfigMap represents a Kubernetes ConfigMap
+type KubeConfigMap struct {
+	Name      string            `json:"name"`
+	Namespace string            `json:"namespace"`
+	Keys      []ConfigKey       `json:"keys"`
+	TotalSize int               `json:"totalSize"`
+	Immutable bool              `json:"immutable"`
+	UsedBy    []ConfigReference `json:"usedBy"`
+	Labels    map[string]string `json:"labels"`
+	CreatedAt time.Time         `json:"createdAt"`
+}
+
+// KubeSecret represents a Kubernetes Secret. It never carries secret values.
+type KubeSecret struct {
+	Name      string            `json:"name"`
+	Namespace string            `json:"namespace"`
+	Type      string            `json:"type"`
+	Keys      []ConfigKey       `json:"keys"`
+	TotalSize int               `json:"totalSize"`
+	Immutable bool              `json:"immutable"`
+	UsedBy    []ConfigReference `json:"usedBy"`
+	Labels    map[string]string `json:"labels"`
+	CreatedAt time.Time         `json:"createdAt"`
+}
+
+// SecretValue is one revealed Secret value
+type SecretValue struct {
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	Encoding string `json:"encoding"` // "text", or "base64" for values that are not valid UTF-8
+}
+
+// RevealedSecret holds the values returned by the Secret reveal endpoint
+type RevealedSecret struct {
+	Name      string        `json:"name"`
+	Namespace string        `json:"namespace"`
+	Type      string        `json:"type"`
+	Values    []SecretValue `json:"values"`
+}
@@ -0,0 +1,92 @@
+package models
+
+import "time"
+
+// ResourcePrice is the hourly price of one CPU core and one GiB of memory
+type ResourcePrice struct {
+	CPUHour       float64 `json:"cpuHour"`
+	MemoryGiBHour float64 `json:"memoryGibHour"`
+}
+
+// ResourceHours is an amount of CPU and memory held over time
+type ResourceHours struct {
+	CPUCoreHours   float64 `json:"cpuCoreHours"`
+	MemoryGiBHours float64 `json:"memoryGibHours"`
+}
+
+// Add returns the sum of h and other
+func (h ResourceHours) Add(other ResourceHours) ResourceHours {
+	return ResourceHours{
+		CPUCoreHours:   h.CPUCoreHours + other.CPUCoreHours,
+		MemoryGiBHours: h.MemoryGiBHours + other.MemoryGiBHours,
+	}
+}
+
+// AllocationHours is what a group of pods requested, used and left idle (requested but unused).
+// Requests held while usage could not be measured are Unmeasured instead of Usage or Idle.
+type AllocationHours struct {
+	Requests   ResourceHours `json:"requests"`
+	Usage      ResourceHours `json:"usage"`
+	Idle       ResourceHours `json:"idle"`
+	Unmeasured ResourceHours `json:"unmeasured"`
+}
+
+// Add returns the sum of a and other
+func (a AllocationHours) Add(other AllocationHours) AllocationHours {
+	return AllocationHours{
+		Requests:   a.Requests.Add(other.Requests),
+		Usage:      a.Usage.Add(other.Usage),
+		Idle:       a.Idle.Add(other.Idle),
+		Unmeasured: a.Unmeasured.Add(other.Unmeasured),
+	}
+}
+
+// CostUsage is the resource-hours recorded in a cluster during one hour
+type CostUsage struct {
+	Hour         time.Time                `json:"hour"`
+	Samples      int                      `json:"samples"`
+	UsageSamples int                      `json:"usageSamples"` // Samples where metrics-server usage was available
+	Capacity     map[string]ResourceHours `json:"capacity"`     // By node pool
+	// Groups is keyed by "<pool>|<dimension>|<name>", where dimension is "namespace" or "label:<key>"
+	Groups map[string]AllocationHours `json:"groups"`
+}
+
+// CostAllocation is the cost charged to one namespace or label value
+type CostAllocation struct {
+	Name     string        `json:"name"`
+	Requests ResourceHours `json:"requests"`
+	Usage    ResourceHours `json:"usage"`
+	// Unmeasured is requests held while usage was not measured; basis=usage charges it at the requests
+	Unmeasured ResourceHours `json:"unmeasured"`
+	Cost       float64       `json:"cost"`
+	IdleCost   float64       `json:"idleCost"` // Cost of requested but unused resources
+}
+
+// PoolCost is the cost of a node pool and how it was allocated
+type PoolCost struct {
+	Pool            string        `json:"pool"`
+	Price           ResourcePrice `json:"price"`
+	Capacity        ResourceHours `json:"capacity"`
+	TotalCost       float64       `json:"totalCost"`
+	AllocatedCost   float64       `json:"allocatedCost"`
+	IdleCost        float64       `json:"idleCost"`
+	UnallocatedCost float64       `json:"unallocatedCost"`
+}
+
+// CostReport allocates node cost over a time range to namespaces or label values
+type CostReport struct {
+	ClusterID   string           `json:"clusterId"`
+	Start       time.Time        `json:"start"`
+	End         time.Time        `json:"end"`
+	Basis       string           `json:"basis"`     // requests or usage
+	AggregateBy string           `json:"aggregate"` // namespace or label:<key>
+	Currency    string           `json:"currency"`
+	Allocations []CostAllocation `json:"allocations"`
+	Pools       []PoolCost       `json:"pools"`
+	// UsageCoverage is the fraction of samples where usage was measured
+	UsageCoverage   float64 `json:"usageCoverage"`
+	TotalCost       float64 `json:"totalCost"`
+	AllocatedCost   float64 `json:"allocatedCost"`
+	IdleCost        float64 `json:"idleCost"`
+	UnallocatedCost float64 `json:"unallocatedCost"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// DebugRequest is the payload for starting a pod or node debug session
+type DebugRequest struct {
+	Image           string   `json:"image,omitempty"`
+	TargetContainer string   `json:"targetContainer,omitempty"` // Pod debug only: container whose process namespace is shared
+	Namespace       string   `json:"namespace,omitempty"`       // Node debug only: namespace for the debug pod
+	Command         []string `json:"command,omitempty"`
+}
+
+// DebugSession describes a started debug container. kubey has no exec endpoint: clients attach
+// to the container themselves, for example with kubectl attach.
+type DebugSession struct {
+	ClusterID string         `json:"clusterId"`
+	Namespace string         `json:"namespace"`
+	Pod       string         `json:"pod"`
+	Container string         `json:"container"`
+	Image     string         `json:"image"`
+	Target    string         `json:"target,omitempty"` // Target container (pod debug) or node name (node debug)
+	Kind      string         `json:"kind"`             // "pod" or "node"
+	Status    ResourceStatus `json:"status"`
+	StartedAt time.Time      `json:"startedAt"`
+}
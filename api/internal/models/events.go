@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// ObjectReference identifies the Kubernetes object an event or change refers to
+type ObjectReference struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	UID       string `json:"uid,omitempty"`
+}
+
+// KubeEvent represents a recorded Kubernetes event
+type KubeEvent struct {
+	ClusterID      string          `json:"clusterId"`
+	UID            string          `json:"uid"`
+	Namespace      string          `json:"namespace"`
+	Name           string          `json:"name"`
+	Type           string          `json:"type"` // Normal, Warning
+	Reason         string          `json:"reason"`
+	Message        string          `json:"message"`
+	Object         ObjectReference `json:"object"`
+	Source         string          `json:"source,omitempty"`
+	Count          int32           `json:"count"`
+	FirstTimestamp time.Time       `json:"firstTimestamp"`
+	LastTimestamp  time.Time       `json:"lastTimestamp"`
+}
+
+// EventQuery filters recorded events; zero values match everything
+type EventQuery struct {
+	ClusterID string
+	Namespace string
+	Kind      string
+	Name      string
+	Reason    string
+	Type      string
+	Since     time.Time
+	Until     time.Time
+	Limit     int
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// NamespaceUsage is pod counts and resource usage aggregated over a namespace's pods
+type NamespaceUsage struct {
+	Pods          int     `json:"pods"`
+	RunningPods   int     `json:"runningPods"`
+	CPUUsage      float64 `json:"cpuUsage"`      // In millicores
+	MemoryUsage   int64   `json:"memoryUsage"`   // In bytes
+	CPURequest    float64 `json:"cpuRequest"`    // In millicores
+	MemoryRequest int64   `json:"memoryRequest"` // In bytes
+}
+
+// SummarySample is one point of a cluster's summary history.
+// Downsampled points hold the average of the samples in their window.
+type SummarySample struct {
+	Time       time.Time                 `json:"time"`
+	Summary    ClusterSummary            `json:"summary"`
+	Namespaces map[string]NamespaceUsage `json:"namespaces,omitempty"`
+}
+
+// SummaryHistory is a cluster's summary history over a time range
+type SummaryHistory struct {
+	ClusterID string          `json:"clusterId"`
+	Start     time.Time       `json:"start"`
+	End       time.Time       `json:"end"`
+	Step      string          `json:"step"`
+	Samples   []SummarySample `json:"samples"`
+}
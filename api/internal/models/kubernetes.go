@@ -19,6 +19,10 @@ type ResourceMetrics struct {
 	MemoryRequest int64   `json:"memoryRequest"` // In bytes
 	CPULimit      float64 `json:"cpuLimit"`      // In millicores
 	MemoryLimit   int64   `json:"memoryLimit"`   // In bytes
+
+	// UsageAvailable is false when metrics-server is not installed or has no sample yet;
+	// requests and limits are always filled from the pod specs
+	UsageAvailable bool `json:"usageAvailable"`
 }
 
 // NodeCapacity represents node resource capacity
@@ -39,6 +43,14 @@ type NodeCondition struct {
 	LastTransitionTime time.Time `json:"lastTransitionTime"`
 }
 
+// StorageStats is filesystem usage reported by the kubelet stats summary
+type StorageStats struct {
+	UsedBytes      int64   `json:"usedBytes"`
+	CapacityBytes  int64   `json:"capacityBytes,omitempty"`
+	AvailableBytes int64   `json:"availableBytes,omitempty"`
+	UsedPercent    float64 `json:"usedPercent,omitempty"`
+}
+
 // KubeContainer represents a container within a pod
 type KubeContainer struct {
 	Name    string           `json:"name"`
@@ -46,13 +58,24 @@ type KubeContainer struct {
 	Ready   bool             `json:"ready"`
 	Metrics *ResourceMetrics `json:"metrics,omitempty"`
 	Status  ResourceStatus   `json:"status"`
+
+	// LogsBytes and RootfsBytes come from the kubelet stats summary and are 0 when it is unavailable
+	LogsBytes   int64 `json:"logsBytes,omitempty"`
+	RootfsBytes int64 `json:"rootfsBytes,omitempty"`
 }
 
 // KubeVolume represents a volume in a pod
 type KubeVolume struct {
-	Name string `json:"name"`
-	Type string `json:"type"`
-	Size string `json:"size,omitempty"`
+	Name      string        `json:"name"`
+	Type      string        `json:"type"`
+	Size      string        `json:"size,omitempty"`
+	ClaimName string        `json:"claimName,omitempty"`
+	Usage     *StorageStats `json:"usage,omitempty"`
+
+	// Set for claim-backed volumes from the claim and its persistent volume
+	PersistentVolume string          `json:"persistentVolume,omitempty"`
+	StorageClass     string          `json:"storageClass,omitempty"`
+	Status           *ResourceStatus `json:"status,omitempty"`
 }
 
 // KubePod represents a Kubernetes pod
@@ -69,6 +92,9 @@ type KubePod struct {
 	NodeName     string            `json:"nodeName"`
 	CreatedAt    time.Time         `json:"createdAt"`
 	RestartCount int32             `json:"restartCount"`
+
+	// EphemeralStorage is the pod's local disk usage (writable layers, logs and emptyDirs)
+	EphemeralStorage *StorageStats `json:"ephemeralStorage,omitempty"`
 }
 
 // KubeNode represents a Kubernetes node
@@ -86,6 +112,37 @@ type KubeNode struct {
 	Labels      map[string]string `json:"labels"`
 	Annotations map[string]string `json:"annotations,omitempty"`
 	CreatedAt   time.Time         `json:"createdAt"`
+
+	// Filesystem and ImageFilesystem are the kubelet's root and image filesystems
+	Filesystem      *StorageStats `json:"filesystem,omitempty"`
+	ImageFilesystem *StorageStats `json:"imageFilesystem,omitempty"`
+
+	// Scheduling
+	Unschedulable bool        `json:"unschedulable"` // Cordoned
+	Taints        []NodeTaint `json:"taints"`
+	Pressure      []string    `json:"pressure"` // Pressure conditions that are True, e.g. MemoryPressure
+
+	// Placement and system details
+	Addresses     []NodeAddress `json:"addresses"`
+	Zone          string        `json:"zone,omitempty"`
+	Region        string        `json:"region,omitempty"`
+	OS            string        `json:"os"`
+	Architecture  string        `json:"architecture"`
+	OSImage       string        `json:"osImage"`
+	KernelVersion string        `json:"kernelVersion"`
+}
+
+// NodeTaint is a taint that repels pods without a matching toleration
+type NodeTaint struct {
+	Key    string `json:"key"`
+	Value  string `json:"value,omitempty"`
+	Effect string `json:"effect"` // NoSchedule, PreferNoSchedule or NoExecute
+}
+
+// NodeAddress is an address of a node, such as its InternalIP or Hostname
+type NodeAddress struct {
+	Type    string `json:"type"`
+	Address string `json:"address"`
 }
 
 // KubeDeployment represents a Kubernetes deployment
@@ -104,6 +161,65 @@ type KubeDeployment struct {
 	CreatedAt         time.Time         `json:"createdAt"`
 }
 
+// KubeStatefulSet represents a Kubernetes statefulset
+type KubeStatefulSet struct {
+	Name                string            `json:"name"`
+	Namespace           string            `json:"namespace"`
+	Replicas            int32             `json:"replicas"`
+	ReadyReplicas       int32             `json:"readyReplicas"`
+	CurrentReplicas     int32             `json:"currentReplicas"`
+	UpdatedReplicas     int32             `json:"updatedReplicas"`
+	AvailableReplicas   int32             `json:"availableReplicas"`
+	ServiceName         string            `json:"serviceName"`
+	PodManagementPolicy string            `json:"podManagementPolicy"`
+	UpdateStrategy      string            `json:"updateStrategy"`
+	Partition           int32             `json:"partition,omitempty"` // Pods with a lower ordinal are not updated
+	CurrentRevision     string            `json:"currentRevision"`
+	UpdateRevision      string            `json:"updateRevision"`
+	Selector            map[string]string `json:"selector"`
+	Role                string            `json:"role"`
+	Status              ResourceStatus    `json:"status"`
+	Labels              map[string]string `json:"labels"`
+	CreatedAt           time.Time         `json:"createdAt"`
+}
+
+// KubeDaemonSet represents a Kubernetes daemonset
+type KubeDaemonSet struct {
+	Name                   string            `json:"name"`
+	Namespace              string            `json:"namespace"`
+	DesiredNumberScheduled int32             `json:"desiredNumberScheduled"`
+	CurrentNumberScheduled int32             `json:"currentNumberScheduled"`
+	UpdatedNumberScheduled int32             `json:"updatedNumberScheduled"`
+	NumberReady            int32             `json:"numberReady"`
+	NumberAvailable        int32             `json:"numberAvailable"`
+	NumberMisscheduled     int32             `json:"numberMisscheduled"` // Running on nodes they should not run on
+	UpdateStrategy         string            `json:"updateStrategy"`
+	MaxUnavailable         string            `json:"maxUnavailable,omitempty"`
+	NodeSelector           map[string]string `json:"nodeSelector,omitempty"`
+	Selector               map[string]string `json:"selector"`
+	Role                   string            `json:"role"`
+	Status                 ResourceStatus    `json:"status"`
+	Labels                 map[string]string `json:"labels"`
+	CreatedAt              time.Time         `json:"createdAt"`
+}
+
+// KubeReplicaSet represents a Kubernetes replicaset
+type KubeReplicaSet struct {
+	Name                 string            `json:"name"`
+	Namespace            string            `json:"namespace"`
+	Replicas             int32             `json:"replicas"`
+	ReadyReplicas        int32             `json:"readyReplicas"`
+	AvailableReplicas    int32             `json:"availableReplicas"`
+	FullyLabeledReplicas int32             `json:"fullyLabeledReplicas"`
+	Owner                string            `json:"owner,omitempty"`    // Name of the owning deployment
+	Revision             string            `json:"revision,omitempty"` // Deployment revision this replicaset belongs to
+	Selector             map[string]string `json:"selector"`
+	Role                 string            `json:"role"`
+	Status               ResourceStatus    `json:"status"`
+	Labels               map[string]string `json:"labels"`
+	CreatedAt            time.Time         `json:"createdAt"`
+}
+
 // KubeService represents a Kubernetes service
 type KubeService struct {
 	Name           string            `json:"name"`
@@ -114,7 +230,8 @@ type KubeService struct {
 	NodePort       *int32            `json:"nodePort,omitempty"`
 	LoadBalancerIP string            `json:"loadBalancerIP,omitempty"`
 	ExternalIPs    []string          `json:"externalIPs,omitempty"`
-	Selector       string            `json:"selector"`
+	Selector       map[string]string `json:"selector"`
+	Endpoints      []ServiceEndpoint `json:"endpoints"`
 	Ports          []ServicePort     `json:"ports"`
 	Role           string            `json:"role"`
 	Status         ResourceStatus    `json:"status"`
@@ -122,6 +239,17 @@ type KubeService struct {
 	CreatedAt      time.Time         `json:"createdAt"`
 }
 
+// ServiceEndpoint is an address a service sends traffic to, from its EndpointSlices
+type ServiceEndpoint struct {
+	Address     string `json:"address"`
+	Pod         string `json:"pod,omitempty"` // Backing pod, when the endpoint targets one
+	NodeName    string `json:"nodeName,omitempty"`
+	Zone        string `json:"zone,omitempty"`
+	Ready       bool   `json:"ready"`
+	Serving     bool   `json:"serving"` // Can receive traffic, even while terminating
+	Terminating bool   `json:"terminating"`
+}
+
 // ServicePort represents a service port
 type ServicePort struct {
 	Name       string `json:"name,omitempty"`
@@ -139,13 +267,16 @@ type KubeControlPlane struct {
 
 // KubeNamespace represents a Kubernetes namespace
 type KubeNamespace struct {
-	Name        string            `json:"name"`
-	Deployments []KubeDeployment  `json:"deployments"`
-	Services    []KubeService     `json:"services"`
-	PodCount    int               `json:"podCount"`
-	Status      ResourceStatus    `json:"status"`
-	Labels      map[string]string `json:"labels"`
-	CreatedAt   time.Time         `json:"createdAt"`
+	Name         string            `json:"name"`
+	Deployments  []KubeDeployment  `json:"deployments"`
+	StatefulSets []KubeStatefulSet `json:"statefulSets"`
+	DaemonSets   []KubeDaemonSet   `json:"daemonSets"`
+	ReplicaSets  []KubeReplicaSet  `json:"replicaSets"`
+	Services     []KubeService     `json:"services"`
+	PodCount     int               `json:"podCount"`
+	Status       ResourceStatus    `json:"status"`
+	Labels       map[string]string `json:"labels"`
+	CreatedAt    time.Time         `json:"createdAt"`
 }
 
 // KubeCluster represents a complete Kubernetes cluster
@@ -172,7 +303,16 @@ type ClusterSummary struct {
 	FailedPods        int     `json:"failedPods"`
 	TotalNamespaces   int     `json:"totalNamespaces"`
 	TotalDeployments  int     `json:"totalDeployments"`
+	TotalStatefulSets int     `json:"totalStatefulSets"`
+	TotalDaemonSets   int     `json:"totalDaemonSets"`
+	TotalReplicaSets  int     `json:"totalReplicaSets"`
 	TotalServices     int     `json:"totalServices"`
-	CPUUtilization    float64 `json:"cpuUtilization"`    // percentage
-	MemoryUtilization float64 `json:"memoryUtilization"` // percentage
+	FailedJobs        int     `json:"failedJobs"`
+	CPUUtilization    float64 `json:"cpuUtilization"`    // used vs allocatable, percentage
+	MemoryUtilization float64 `json:"memoryUtilization"` // used vs allocatable, percentage
+	CPURequested      float64 `json:"cpuRequested"`      // requested vs allocatable, percentage
+	MemoryRequested   float64 `json:"memoryRequested"`   // requested vs allocatable, percentage
+
+	// UtilizationAvailable is false when metrics-server is unavailable and usage could not be measured
+	UtilizationAvailable bool `json:"utilizationAvailable"`
 }
@@ -0,0 +1,113 @@
+package models
+
+import "time"
+
+// BackendRef is a service that an ingress path or route rule sends traffic to
+type BackendRef struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Port      string `json:"port,omitempty"` // Port number or name
+	Weight    *int32 `json:"weight,omitempty"`
+
+	// Service is the matching service, or nil when it does not exist
+	Service *KubeService `json:"service,omitempty"`
+}
+
+// IngressPath is one path of an ingress rule
+type IngressPath struct {
+	Path     string     `json:"path"`
+	PathType string     `json:"pathType"`
+	URL      string     `json:"url"`
+	Backend  BackendRef `json:"backend"`
+}
+
+// IngressRule routes the paths of one host, or of any host when Host is empty
+type IngressRule struct {
+	Host  string        `json:"host,omitempty"`
+	Paths []IngressPath `json:"paths"`
+}
+
+// IngressTLS is a TLS certificate secret and the hosts it covers
+type IngressTLS struct {
+	Hosts      []string `json:"hosts"`
+	SecretName string   `json:"secretName"`
+}
+
+// KubeIngress represents a Kubernetes ingress
+type KubeIngress struct {
+	Name           string            `json:"name"`
+	Namespace      string            `json:"namespace"`
+	ClassName      string            `json:"className,omitempty"`
+	Rules          []IngressRule     `json:"rules"`
+	DefaultBackend *BackendRef       `json:"defaultBackend,omitempty"`
+	TLS            []IngressTLS      `json:"tls"`
+	Addresses      []string          `json:"addresses"`
+	Status         ResourceStatus    `json:"status"`
+	Labels         map[string]string `json:"labels"`
+	CreatedAt      time.Time         `json:"createdAt"`
+}
+
+// KubeIngressClass represents a Kubernetes ingress class
+type KubeIngressClass struct {
+	Name       string            `json:"name"`
+	Controller string            `json:"controller"`
+	Default    bool              `json:"default"`
+	Parameters string            `json:"parameters,omitempty"` // Kind/name of the controller parameters object
+	Labels     map[string]string `json:"labels"`
+	CreatedAt  time.Time         `json:"createdAt"`
+}
+
+// GatewayListener is a port, protocol and hostname a gateway accepts traffic on
+type GatewayListener struct {
+	Name           string   `json:"name"`
+	Hostname       string   `json:"hostname,omitempty"`
+	Port           int32    `json:"port"`
+	Protocol       string   `json:"protocol"`
+	TLSSecrets     []string `json:"tlsSecrets,omitempty"` // namespace/name of the certificate secrets
+	AttachedRoutes int32    `json:"attachedRoutes"`
+}
+
+// KubeGateway represents a Gateway API gateway
+type KubeGateway struct {
+	Name      string            `json:"name"`
+	Namespace string            `json:"namespace"`
+	ClassName string            `json:"className"`
+	Listeners []GatewayListener `json:"listeners"`
+	Addresses []string          `json:"addresses"`
+	Status    ResourceStatus    `json:"status"`
+	Labels    map[string]string `json:"labels"`
+	CreatedAt time.Time         `json:"createdAt"`
+}
+
+// RouteParentRef is a gateway, and optionally one of its listeners, that a route attaches to
+type RouteParentRef struct {
+	Name        string `json:"name"`
+	Namespace   string `json:"namespace"`
+	SectionName string `json:"sectionName,omitempty"`
+	Accepted    bool   `json:"accepted"`
+}
+
+// HTTPRouteMatch is one request match of an HTTP route rule
+type HTTPRouteMatch struct {
+	Path     string `json:"path,omitempty"`
+	PathType string `json:"pathType,omitempty"`
+	Method   string `json:"method,omitempty"`
+}
+
+// HTTPRouteRule sends requests matching any of Matches to Backends
+type HTTPRouteRule struct {
+	Matches  []HTTPRouteMatch `json:"matches"`
+	Backends []BackendRef     `json:"backends"`
+}
+
+// KubeHTTPRoute represents a Gateway API HTTP route
+type KubeHTTPRoute struct {
+	Name       string            `json:"name"`
+	Namespace  string            `json:"namespace"`
+	Hostnames  []string          `json:"hostnames"`
+	ParentRefs []RouteParentRef  `json:"parentRefs"`
+	Rules      []HTTPRouteRule   `json:"rules"`
+	Status     ResourceStatus    `json:"status"`
+	Labels     map[string]string `json:"labels"`
+	CreatedAt  time.Time         `json:"createdAt"`
+}
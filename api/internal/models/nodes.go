@@ -0,0 +1,28 @@
+package models
+
+// NodePod is a pod scheduled on a node with its share of the node's allocatable resources
+type NodePod struct {
+	Name          string         `json:"name"`
+	Namespace     string         `json:"namespace"`
+	Status        ResourceStatus `json:"status"`
+	QOSClass      string         `json:"qosClass"`
+	CPURequest    float64        `json:"cpuRequest"`    // In millicores
+	MemoryRequest int64          `json:"memoryRequest"` // In bytes
+	CPULimit      float64        `json:"cpuLimit"`      // In millicores
+	MemoryLimit   int64          `json:"memoryLimit"`   // In bytes
+
+	// Requests as a percentage of the node's allocatable resources
+	CPURequestPercent    float64 `json:"cpuRequestPercent"`
+	MemoryRequestPercent float64 `json:"memoryRequestPercent"`
+
+	// Terminal pods no longer hold their requests and are left out of the node totals
+	Terminal bool `json:"terminal"`
+}
+
+// NodePods lists the pods on a node, largest CPU request first, with the node's totals
+type NodePods struct {
+	Node        string          `json:"node"`
+	Allocatable NodeCapacity    `json:"allocatable"`
+	Requested   ResourceMetrics `json:"requested"` // Requests, limits and usage of the running pods
+	Pods        []NodePod       `json:"pods"`
+}
@@ -0,0 +1,89 @@
+package models
+
+import "time"
+
+// APIResourceType is a kind of object a cluster serves, as found by discovery
+type APIResourceType struct {
+	Group      string `json:"group"` // Empty for the core group
+	Version    string `json:"version"`
+	Resource   string `json:"resource"` // Plural name used in URLs
+	Kind       string `json:"kind"`
+	Namespaced bool   `json:"namespaced"`
+	Custom     bool   `json:"custom"` // Defined by a CustomResourceDefinition
+}
+
+// ResourceColumn is a column of a resource table, from the CRD printer columns
+type ResourceColumn struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"` // string, integer, number, boolean or date
+	Format      string `json:"format,omitempty"`
+	Description string `json:"description,omitempty"`
+	Priority    int32  `json:"priority"` // Columns above 0 are only shown in wide views
+}
+
+// ResourceRow is one object of a resource table
+type ResourceRow struct {
+	Name      string         `json:"name"`
+	Namespace string         `json:"namespace,omitempty"`
+	Cells     []interface{}  `json:"cells"` // One value per column, nil when the field is not set
+	Status    ResourceStatus `json:"status"`
+	CreatedAt time.Time      `json:"createdAt"`
+}
+
+// ResourceTable lists the objects of any resource type with their printer columns
+type ResourceTable struct {
+	Resource APIResourceType  `json:"resource"`
+	Columns  []ResourceColumn `json:"columns"`
+	Rows     []ResourceRow    `json:"rows"`
+	Continue string           `json:"continue,omitempty"` // Token for the next page
+}
+
+// ResourceCondition is a status condition of any object
+type ResourceCondition struct {
+	Type               string    `json:"type"`
+	Status             string    `json:"status"`
+	Reason             string    `json:"reason,omitempty"`
+	Message            string    `json:"message,omitempty"`
+	LastTransitionTime time.Time `json:"lastTransitionTime"`
+}
+
+// ResourceObject is a single object of any resource type
+type ResourceObject struct {
+	Resource   APIResourceType        `json:"resource"`
+	Name       string                 `json:"name"`
+	Namespace  string                 `json:"namespace,omitempty"`
+	Status     ResourceStatus         `json:"status"`
+	Conditions []ResourceCondition    `json:"conditions"`
+	Object     map[string]interface{} `json:"object"`
+}
+
+// APIResource is a resource served in one version of an API group
+type APIResource struct {
+	Name         string   `json:"name"`
+	Kind         string   `json:"kind"`
+	Namespaced   bool     `json:"namespaced"`
+	Verbs        []string `json:"verbs"`
+	ShortNames   []string `json:"shortNames"`
+	Categories   []string `json:"categories"`
+	Subresources []string `json:"subresources"` // e.g. status, scale or log
+}
+
+// APIGroupVersion is one version of an API group and the resources it serves
+type APIGroupVersion struct {
+	Version   string        `json:"version"`
+	Resources []APIResource `json:"resources"`
+}
+
+// APIGroup is an API group and the versions a cluster serves it in
+type APIGroup struct {
+	Name             string            `json:"name"` // Empty for the core group
+	PreferredVersion string            `json:"preferredVersion"`
+	Versions         []APIGroupVersion `json:"versions"`
+}
+
+// APIResources is everything a cluster serves, as found by discovery
+type APIResources struct {
+	ServerVersion string     `json:"serverVersion"`
+	Groups        []APIGroup `json:"groups"`
+	Failed        []string   `json:"failed"` // Group versions whose discovery failed
+}
@@ -0,0 +1,54 @@
+package models
+
+import "encoding/json"
+
+// ContainerKey identifies a container of a workload across pod restarts and rollouts
+type ContainerKey struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Container string `json:"container"`
+}
+
+// UsageSample is a container's CPU and memory usage at one point in time
+type UsageSample struct {
+	CPU    float64 `json:"cpu"`    // In millicores
+	Memory int64   `json:"memory"` // In bytes
+}
+
+// ContainerRecommendation suggests requests and limits for one container of a workload
+type ContainerRecommendation struct {
+	Container string `json:"container"`
+	// Current holds the pod template's requests and limits; its usage fields are the percentile usage
+	Current     ResourceMetrics `json:"current"`
+	Recommended ResourceMetrics `json:"recommended"`
+	DataPoints  int             `json:"dataPoints,omitempty"` // Usage samples behind the percentiles (kubey source only)
+}
+
+// WorkloadRecommendation holds the container recommendations of a Deployment or StatefulSet
+type WorkloadRecommendation struct {
+	Workload   ObjectReference           `json:"workload"`
+	Replicas   int32                     `json:"replicas"`
+	Containers []ContainerRecommendation `json:"containers"`
+	// Savings are request reductions across all replicas; negative values mean more is needed
+	CPUSavings    float64         `json:"cpuSavings"`    // In millicores
+	MemorySavings int64           `json:"memorySavings"` // In bytes
+	Patch         json.RawMessage `json:"patch"`         // Strategic merge patch applying the recommendation
+	PatchCommand  string          `json:"patchCommand"`
+}
+
+// RightSizingReport is the set of recommendations for a cluster or namespace
+type RightSizingReport struct {
+	ClusterID string `json:"clusterId"`
+	Namespace string `json:"namespace,omitempty"`
+	Source    string `json:"source"` // kubey or prometheus
+	Window    string `json:"window"`
+	// RequestedWindow is set when the window was clamped to how long kubey keeps its usage samples
+	RequestedWindow    string                   `json:"requestedWindow,omitempty"`
+	CPUPercentile      float64                  `json:"cpuPercentile"`
+	MemoryPercentile   float64                  `json:"memoryPercentile"`
+	Headroom           float64                  `json:"headroom"` // Fraction added on top of the percentile, e.g. 0.15
+	Workloads          []WorkloadRecommendation `json:"workloads"`
+	TotalCPUSavings    float64                  `json:"totalCpuSavings"`
+	TotalMemorySavings int64                    `json:"totalMemorySavings"`
+}
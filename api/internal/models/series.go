@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// MetricSample is a single point of a time series
+type MetricSample struct {
+	Time  time.Time `json:"time"`
+	Value float64   `json:"value"`
+}
+
+// MetricSeries is a time series for one object, keyed to the object's API identity
+type MetricSeries struct {
+	Object    ObjectReference   `json:"object"`              // Pod, Node or Namespace
+	Container string            `json:"container,omitempty"` // Set for container series
+	Labels    map[string]string `json:"labels,omitempty"`
+	Samples   []MetricSample    `json:"samples"`
+}
+
+// MetricSeriesResponse is the result of a historical metrics query
+type MetricSeriesResponse struct {
+	ClusterID string         `json:"clusterId"`
+	Scope     string         `json:"scope"`  // pod, container, node, namespace
+	Metric    string         `json:"metric"` // cpu, memory, network_receive, network_transmit, restarts
+	Unit      string         `json:"unit"`
+	Start     time.Time      `json:"start"`
+	End       time.Time      `json:"end"`
+	Step      string         `json:"step"` // e.g. "1m0s"
+	Series    []MetricSeries `json:"series"`
+}
@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// KubePersistentVolumeClaim represents a Kubernetes persistent volume claim
+type KubePersistentVolumeClaim struct {
+	Name         string            `json:"name"`
+	Namespace    string            `json:"namespace"`
+	VolumeName   string            `json:"volumeName,omitempty"` // Bound persistent volume
+	StorageClass string            `json:"storageClass,omitempty"`
+	Requested    string            `json:"requested"`          // Requested storage
+	Capacity     string            `json:"capacity,omitempty"` // Capacity of the bound volume
+	AccessModes  []string          `json:"accessModes"`
+	VolumeMode   string            `json:"volumeMode"`
+	UsedBy       []string          `json:"usedBy"` // Pods that mount the claim
+	Status       ResourceStatus    `json:"status"`
+	Labels       map[string]string `json:"labels"`
+	CreatedAt    time.Time         `json:"createdAt"`
+}
+
+// KubePersistentVolume represents a Kubernetes persistent volume
+type KubePersistentVolume struct {
+	Name          string            `json:"name"`
+	Capacity      string            `json:"capacity"`
+	AccessModes   []string          `json:"accessModes"`
+	VolumeMode    string            `json:"volumeMode"`
+	ReclaimPolicy string            `json:"reclaimPolicy"`
+	StorageClass  string            `json:"storageClass,omitempty"`
+	Source        string            `json:"source"` // Volume plugin, e.g. csi, nfs or local
+	CSIDriver     string            `json:"csiDriver,omitempty"`
+	VolumeHandle  string            `json:"volumeHandle,omitempty"`
+	Claim         string            `json:"claim,omitempty"` // namespace/name of the bound claim
+	Status        ResourceStatus    `json:"status"`
+	Labels        map[string]string `json:"labels"`
+	CreatedAt     time.Time         `json:"createdAt"`
+}
+
+// KubeStorageClass represents a Kubernetes storage class
+type KubeStorageClass struct {
+	Name                 string            `json:"name"`
+	Provisioner          string            `json:"provisioner"` // CSI driver or in-tree provisioner
+	ReclaimPolicy        string            `json:"reclaimPolicy"`
+	VolumeBindingMode    string            `json:"volumeBindingMode"`
+	AllowVolumeExpansion bool              `json:"allowVolumeExpansion"`
+	Default              bool              `json:"default"`
+	Parameters           map[string]string `json:"parameters"`
+	Labels               map[string]string `json:"labels"`
+	CreatedAt            time.Time         `json:"createdAt"`
+}
@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// SummaryUpdate carries the latest live summary for one cluster
+type SummaryUpdate struct {
+	ClusterID string         `json:"clusterId"`
+	Summary   ClusterSummary `json:"summary"`
+	Synced    bool           `json:"synced"` // False until the initial watch lists have completed
+	UpdatedAt time.Time      `json:"updatedAt"`
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// TimelineEntry is a single point in an incident timeline
+type TimelineEntry struct {
+	Time    time.Time       `json:"time"`
+	Type    string          `json:"type"` // rollout, restart, termination, warning, node-condition, scaling
+	Object  ObjectReference `json:"object"`
+	Summary string          `json:"summary"`
+	Details string          `json:"details,omitempty"`
+}
+
+// Timeline is a chronological view of what happened to a namespace or workload
+type Timeline struct {
+	ClusterID string           `json:"clusterId"`
+	Namespace string           `json:"namespace"`
+	Workload  *ObjectReference `json:"workload,omitempty"`
+	Since     time.Time        `json:"since"`
+	Until     time.Time        `json:"until"`
+	Entries   []TimelineEntry  `json:"entries"`
+}
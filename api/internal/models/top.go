@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// TopEntry is one ranked pod, container, namespace or node
+type TopEntry struct {
+	ClusterID string    `json:"clusterId"`
+	Kind      string    `json:"kind"` // pod, container, namespace or node
+	Name      string    `json:"name"`
+	Namespace string    `json:"namespace,omitempty"`
+	Pod       string    `json:"pod,omitempty"`
+	Node      string    `json:"node,omitempty"`
+	Pods      int       `json:"pods,omitempty"` // Pods in the namespace or on the node
+	CPU       float64   `json:"cpu"`            // In millicores
+	Memory    int64     `json:"memory"`         // In bytes
+	Restarts  int32     `json:"restarts"`
+	CreatedAt time.Time `json:"createdAt"`
+
+	// UsageAvailable is false when the cluster has no metrics-server sample for the entry
+	UsageAvailable bool `json:"usageAvailable"`
+}
+
+// TopClusterError records a cluster that could not be queried for a top report
+type TopClusterError struct {
+	ClusterID string `json:"clusterId"`
+	Error     string `json:"error"`
+}
+
+// TopReport ranks the largest resource consumers across clusters
+type TopReport struct {
+	Kind     string            `json:"kind"`
+	SortBy   string            `json:"sortBy"`
+	Limit    int               `json:"limit"`
+	Clusters []string          `json:"clusters"`
+	Items    []TopEntry        `json:"items"`
+	Failed   []TopClusterError `json:"failed,omitempty"`
+}
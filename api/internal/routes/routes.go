@@ -4,23 +4,83 @@ import (
 	"kubey/api/internal/config"
 	"kubey/api/internal/handlers"
 	"kubey/api/internal/handlers/clusters"
+	"kubey/api/internal/store"
 
 	"github.com/gin-gonic/gin"
 )
 
-func Setup(router *gin.Engine, cfg *config.ApiConfig) {
+func Setup(router *gin.Engine, cfg *config.ApiConfig, db *store.Store) {
 	// API routes
 	api := router.Group("/api")
 	{
 		api.GET("/clusters", clusters.GetClusters)
+		api.GET("/summaries", clusters.GetLiveSummaries)
+		api.GET("/summaries/stream", clusters.StreamSummaries)
+		api.GET("/top", clusters.GetTop)
 		api.GET("/clusters/:id", clusters.GetCluster)
 		api.GET("/clusters/:id/nodes", clusters.GetClusterNodes)
+		api.GET("/clusters/:id/nodes/:node/pods", clusters.GetNodePods)
 		api.GET("/clusters/:id/pods", clusters.GetClusterPods)
 		api.GET("/clusters/:id/services", clusters.GetClusterServices)
 		api.GET("/clusters/:id/deployments", clusters.GetClusterDeployments)
 		api.GET("/clusters/:id/namespaces", clusters.GetClusterNamespaces)
+		api.GET("/clusters/:id/statefulsets", clusters.GetStatefulSets)
+		api.GET("/clusters/:id/namespaces/:namespace/statefulsets/:name", clusters.GetStatefulSet)
+		api.GET("/clusters/:id/daemonsets", clusters.GetDaemonSets)
+		api.GET("/clusters/:id/namespaces/:namespace/daemonsets/:name", clusters.GetDaemonSet)
+		api.GET("/clusters/:id/replicasets", clusters.GetReplicaSets)
+		api.GET("/clusters/:id/namespaces/:namespace/replicasets/:name", clusters.GetReplicaSet)
+		api.GET("/clusters/:id/jobs", clusters.GetJobs)
+		api.GET("/clusters/:id/namespaces/:namespace/jobs/:name", clusters.GetJob)
+		api.GET("/clusters/:id/cronjobs", clusters.GetCronJobs)
+		api.GET("/clusters/:id/namespaces/:namespace/cronjobs/:name", clusters.GetCronJob)
+		api.GET("/clusters/:id/ingresses", clusters.GetIngresses)
+		api.GET("/clusters/:id/namespaces/:namespace/ingresses/:name", clusters.GetIngress)
+		api.GET("/clusters/:id/ingressclasses", clusters.GetIngressClasses)
+		api.GET("/clusters/:id/gateways", clusters.GetGateways)
+		api.GET("/clusters/:id/namespaces/:namespace/gateways/:name", clusters.GetGateway)
+		api.GET("/clusters/:id/httproutes", clusters.GetHTTPRoutes)
+		api.GET("/clusters/:id/namespaces/:namespace/httproutes/:name", clusters.GetHTTPRoute)
+		api.GET("/clusters/:id/configmaps", clusters.GetConfigMaps(cfg))
+		api.GET("/clusters/:id/namespaces/:namespace/configmaps/:name", clusters.GetConfigMap(cfg))
+		api.GET("/clusters/:id/secrets", clusters.GetSecrets)
+		api.GET("/clusters/:id/namespaces/:namespace/secrets/:name", clusters.GetSecret)
+		api.GET("/clusters/:id/persistentvolumeclaims", clusters.GetPersistentVolumeClaims)
+		api.GET("/clusters/:id/namespaces/:namespace/persistentvolumeclaims/:name", clusters.GetPersistentVolumeClaim)
+		api.GET("/clusters/:id/persistentvolumes", clusters.GetPersistentVolumes)
+		api.GET("/clusters/:id/persistentvolumes/:name", clusters.GetPersistentVolume)
+		api.GET("/clusters/:id/storageclasses", clusters.GetStorageClasses)
+		api.GET("/clusters/:id/api-resources", clusters.GetAPIResources)
+		api.GET("/clusters/:id/resources", clusters.GetResourceTypes)
+		api.GET("/clusters/:id/resources/:group/:version/:resource", clusters.GetResourceTable)
+		api.GET("/clusters/:id/resources/:group/:version/:resource/:name", clusters.GetResourceObject)
+		api.GET("/clusters/:id/capacity", clusters.GetCapacity(cfg))
+		api.GET("/clusters/:id/summary/history", clusters.GetSummaryHistory(db))
+		api.GET("/clusters/:id/prometheus/:scope/:metric", clusters.GetMetricSeries(cfg))
+		api.GET("/clusters/:id/rightsizing", clusters.GetRightSizing(cfg, db))
+		api.GET("/clusters/:id/cost", clusters.GetCost(cfg, db))
+
+		// Recorded event history
+		api.GET("/events", clusters.GetEvents(db))
+		api.GET("/clusters/:id/events", clusters.GetEvents(db))
+
+		// Recorded spec changes
+		api.GET("/clusters/:id/changes", clusters.GetClusterChanges(db))
+
+		// Incident timeline
+		api.GET("/clusters/:id/namespaces/:namespace/timeline", clusters.GetTimeline(db))
+
+		// Debug sessions
+		api.POST("/clusters/:id/namespaces/:namespace/pods/:pod/debug", clusters.DebugPod(cfg))
+		api.POST("/clusters/:id/nodes/:node/debug", clusters.DebugNode(cfg))
+
+		// Secret values
+		api.POST("/clusters/:id/namespaces/:namespace/secrets/:name/reveal", clusters.RevealSecret(cfg))
 	}
 
 	// Health check
 	router.GET("/health", handlers.Health)
+
+	// Prometheus metrics for kubey itself
+	router.GET("/metrics", handlers.Metrics)
 }
@@ -0,0 +1,342 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+	_ "time/tzdata" // CronJob time zones must resolve in images without a zoneinfo database
+
+	"kubey/api/internal/models"
+
+	"github.com/robfig/cron/v3"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// GetJobs returns the jobs of a cluster. An empty namespace lists all namespaces.
+func GetJobs(clusterID, namespace string) ([]models.KubeJob, error) {
+	cs, err := getClientsetForCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := cs.BatchV1().Jobs(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %v", err)
+	}
+
+	now := time.Now()
+	jobs := []models.KubeJob{}
+	for i := range list.Items {
+		jobs = append(jobs, toKubeJob(&list.Items[i], now))
+	}
+	return jobs, nil
+}
+
+// GetJob returns a single job
+func GetJob(clusterID, namespace, name string) (*models.KubeJob, error) {
+	cs, err := getClientsetForCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := cs.BatchV1().Jobs(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, getError(err, "job", namespace, name)
+	}
+
+	kubeJob := toKubeJob(job, time.Now())
+	return &kubeJob, nil
+}
+
+// GetCronJobs returns the cronjobs of a cluster with the jobs each created.
+// An empty namespace lists all namespaces.
+func GetCronJobs(clusterID, namespace string) ([]models.KubeCronJob, error) {
+	cs, err := getClientsetForCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := cs.BatchV1().CronJobs(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cronjobs: %v", err)
+	}
+	jobs, err := cs.BatchV1().Jobs(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %v", err)
+	}
+
+	now := time.Now()
+	jobsByCronJob := groupJobsByCronJob(jobs.Items, now)
+	cronJobs := []models.KubeCronJob{}
+	for i := range list.Items {
+		cronJob := &list.Items[i]
+		cronJobs = append(cronJobs, toKubeCronJob(cronJob, jobsByCronJob[cronJob.Namespace+"/"+cronJob.Name], now))
+	}
+	return cronJobs, nil
+}
+
+// GetCronJob returns a single cronjob with the jobs it created
+func GetCronJob(clusterID, namespace, name string) (*models.KubeCronJob, error) {
+	cs, err := getClientsetForCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	cronJob, err := cs.BatchV1().CronJobs(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, getError(err, "cronjob", namespace, name)
+	}
+	jobs, err := cs.BatchV1().Jobs(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %v", err)
+	}
+
+	now := time.Now()
+	kubeCronJob := toKubeCronJob(cronJob, groupJobsByCronJob(jobs.Items, now)[namespace+"/"+name], now)
+	return &kubeCronJob, nil
+}
+
+// groupJobsByCronJob converts jobs and groups those created by a cronjob under "namespace/cronjob"
+func groupJobsByCronJob(jobs []batchv1.Job, now time.Time) map[string][]models.KubeJob {
+	grouped := map[string][]models.KubeJob{}
+	for i := range jobs {
+		kubeJob := toKubeJob(&jobs[i], now)
+		if kubeJob.CronJob != "" {
+			key := kubeJob.Namespace + "/" + kubeJob.CronJob
+			grouped[key] = append(grouped[key], kubeJob)
+		}
+	}
+	return grouped
+}
+
+func toKubeJob(job *batchv1.Job, now time.Time) models.KubeJob {
+	kubeJob := models.KubeJob{
+		Name:        job.Name,
+		Namespace:   job.Namespace,
+		Completions: job.Spec.Completions,
+		Parallelism: desiredReplicas(job.Spec.Parallelism),
+		Active:      job.Status.Active,
+		Succeeded:   job.Status.Succeeded,
+		Failed:      job.Status.Failed,
+		Suspended:   job.Spec.Suspend != nil && *job.Spec.Suspend,
+		Role:        getWorkloadRole(job.Labels),
+		Labels:      job.Labels,
+		CreatedAt:   job.CreationTimestamp.Time,
+		Status:      getJobStatus(job),
+	}
+	if job.Spec.CompletionMode != nil {
+		kubeJob.CompletionMode = string(*job.Spec.CompletionMode)
+	}
+	if job.Spec.BackoffLimit != nil {
+		kubeJob.BackoffLimit = *job.Spec.BackoffLimit
+	}
+	if job.Status.Ready != nil {
+		kubeJob.Ready = *job.Status.Ready
+	}
+	for _, owner := range job.OwnerReferences {
+		if owner.Kind == "CronJob" {
+			kubeJob.CronJob = owner.Name
+		}
+	}
+
+	if job.Status.StartTime != nil {
+		start := job.Status.StartTime.Time
+		kubeJob.StartTime = &start
+		end := now
+		if job.Status.CompletionTime != nil {
+			end = job.Status.CompletionTime.Time
+			kubeJob.CompletionTime = &end
+		} else if failed := jobCondition(job, batchv1.JobFailed); failed != nil {
+			end = failed.LastTransitionTime.Time
+		}
+		kubeJob.Duration = end.Sub(start).Round(time.Second).Seconds()
+	}
+	return kubeJob
+}
+
+func toKubeCronJob(cronJob *batchv1.CronJob, jobs []models.KubeJob, now time.Time) models.KubeCronJob {
+	kubeCronJob := models.KubeCronJob{
+		Name:              cronJob.Name,
+		Namespace:         cronJob.Namespace,
+		Schedule:          cronJob.Spec.Schedule,
+		Suspended:         cronJob.Spec.Suspend != nil && *cronJob.Spec.Suspend,
+		ConcurrencyPolicy: string(cronJob.Spec.ConcurrencyPolicy),
+		Active:            len(cronJob.Status.Active),
+		Jobs:              jobs,
+		Role:              getWorkloadRole(cronJob.Labels),
+		Labels:            cronJob.Labels,
+		CreatedAt:         cronJob.CreationTimestamp.Time,
+	}
+	if kubeCronJob.Jobs == nil {
+		kubeCronJob.Jobs = []models.KubeJob{}
+	}
+	sort.Slice(kubeCronJob.Jobs, func(i, j int) bool {
+		return kubeCronJob.Jobs[i].CreatedAt.After(kubeCronJob.Jobs[j].CreatedAt)
+	})
+	if cronJob.Spec.TimeZone != nil {
+		kubeCronJob.TimeZone = *cronJob.Spec.TimeZone
+	}
+	if cronJob.Spec.SuccessfulJobsHistoryLimit != nil {
+		kubeCronJob.SuccessfulJobsHistoryLimit = *cronJob.Spec.SuccessfulJobsHistoryLimit
+	}
+	if cronJob.Spec.FailedJobsHistoryLimit != nil {
+		kubeCronJob.FailedJobsHistoryLimit = *cronJob.Spec.FailedJobsHistoryLimit
+	}
+	if t := cronJob.Status.LastScheduleTime; t != nil {
+		last := t.Time
+		kubeCronJob.LastScheduleTime = &last
+	}
+	if t := cronJob.Status.LastSuccessfulTime; t != nil {
+		last := t.Time
+		kubeCronJob.LastSuccessfulTime = &last
+	}
+
+	next, scheduleErr := nextScheduleTime(cronJob.Spec.Schedule, kubeCronJob.TimeZone, now)
+	if scheduleErr == nil && !kubeCronJob.Suspended {
+		kubeCronJob.NextScheduleTime = &next
+	}
+	kubeCronJob.Status = getCronJobStatus(&kubeCronJob, scheduleErr)
+	return kubeCronJob
+}
+
+// nextScheduleTime returns the first run of a cron schedule after now.
+// Like the cronjob controller, schedules without a time zone are evaluated in UTC.
+func nextScheduleTime(schedule, timeZone string, now time.Time) (time.Time, error) {
+	location := time.UTC
+	if timeZone != "" {
+		loc, err := time.LoadLocation(timeZone)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("unknown time zone %q: %v", timeZone, err)
+		}
+		location = loc
+	}
+
+	// The API server rejects time zones in the schedule itself, which the cron parser would accept
+	if strings.Contains(schedule, "TZ") {
+		return time.Time{}, fmt.Errorf("invalid schedule %q: TZ and CRON_TZ are not supported, use spec.timeZone", schedule)
+	}
+	sched, err := cron.ParseStandard(schedule)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid schedule %q: %v", schedule, err)
+	}
+	return sched.Next(now.In(location)), nil
+}
+
+// jobCondition returns the job's condition of the given type if it is true
+func jobCondition(job *batchv1.Job, conditionType batchv1.JobConditionType) *batchv1.JobCondition {
+	for i := range job.Status.Conditions {
+		condition := &job.Status.Conditions[i]
+		if condition.Type == conditionType && condition.Status == v1.ConditionTrue {
+			return condition
+		}
+	}
+	return nil
+}
+
+// isJobFailed reports whether a job has given up after exhausting its retries or deadline
+func isJobFailed(job *batchv1.Job) bool {
+	return jobCondition(job, batchv1.JobFailed) != nil
+}
+
+// countFailedJobs returns how many of the jobs have failed
+func countFailedJobs(jobs []*batchv1.Job) int {
+	failed := 0
+	for _, job := range jobs {
+		if isJobFailed(job) {
+			failed++
+		}
+	}
+	return failed
+}
+
+// getFailedJobCount lists up to limit jobs of all namespaces and counts the failed ones,
+// or returns 0 if they cannot be listed
+func getFailedJobCount(ctx context.Context, cs kubernetes.Interface, limit int64) int {
+	list, err := cs.BatchV1().Jobs("").List(ctx, metav1.ListOptions{Limit: limit})
+	if err != nil {
+		log.Printf("Failed to list jobs: %v", err)
+		return 0
+	}
+	jobs := make([]*batchv1.Job, len(list.Items))
+	for i := range list.Items {
+		jobs[i] = &list.Items[i]
+	}
+	return countFailedJobs(jobs)
+}
+
+// getJobStatus derives a job's status from its Complete, Failed and Suspended conditions and active pods
+func getJobStatus(job *batchv1.Job) models.ResourceStatus {
+	status := models.ResourceStatus{LastUpdated: time.Now()}
+
+	if condition := jobCondition(job, batchv1.JobComplete); condition != nil {
+		status.Phase = "Succeeded"
+		status.Ready = true
+		return status
+	}
+	if condition := jobCondition(job, batchv1.JobFailed); condition != nil {
+		status.Phase = "Failed"
+		status.Reason = condition.Reason
+		status.Message = condition.Message
+		return status
+	}
+	if condition := jobCondition(job, batchv1.JobSuspended); condition != nil {
+		status.Phase = "Suspended"
+		status.Ready = true
+		status.Reason = condition.Reason
+		status.Message = condition.Message
+		return status
+	}
+
+	if job.Status.Active > 0 {
+		status.Phase = "Running"
+		status.Ready = true
+		if job.Status.Failed > 0 {
+			status.Reason = "Retrying"
+			status.Message = fmt.Sprintf("%d failed attempts", job.Status.Failed)
+		}
+		return status
+	}
+	status.Phase = "Pending"
+	return status
+}
+
+// getCronJobStatus derives a cronjob's status from its schedule, active jobs and most recent finished job
+func getCronJobStatus(cronJob *models.KubeCronJob, scheduleErr error) models.ResourceStatus {
+	status := models.ResourceStatus{Phase: "Scheduled", Ready: true, LastUpdated: time.Now()}
+
+	switch {
+	case scheduleErr != nil:
+		status.Phase = "Invalid"
+		status.Ready = false
+		status.Reason = "InvalidSchedule"
+		status.Message = scheduleErr.Error()
+		return status
+	case cronJob.Suspended:
+		status.Phase = "Suspended"
+	case cronJob.Active > 0:
+		status.Phase = "Active"
+	}
+
+	// Jobs are sorted newest first; the latest finished job decides whether runs are failing
+	for _, job := range cronJob.Jobs {
+		if job.Status.Phase == "Failed" {
+			status.Ready = false
+			status.Reason = "LastJobFailed"
+			status.Message = "job " + job.Name + " failed"
+			if job.Status.Reason != "" {
+				status.Message += ": " + job.Status.Reason
+			}
+			break
+		}
+		if job.Status.Phase == "Succeeded" {
+			break
+		}
+	}
+	return status
+}
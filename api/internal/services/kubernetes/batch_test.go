@@ -0,0 +1,95 @@
+package kubernetes
+
+import (
+	"testing"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNextScheduleTime(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 30, 0, 0, time.UTC)
+
+	next, err := nextScheduleTime("0 2 * * *", "", now)
+	if err != nil || !next.Equal(time.Date(2024, 3, 11, 2, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected UTC run: %v (%v)", next, err)
+	}
+
+	// 02:00 in New York (EDT, UTC-4) on the 11th is 06:00 UTC
+	next, err = nextScheduleTime("0 2 * * *", "America/New_York", now)
+	if err != nil || !next.UTC().Equal(time.Date(2024, 3, 11, 6, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected zoned run: %v (%v)", next.UTC(), err)
+	}
+
+	if _, err := nextScheduleTime("not a schedule", "", now); err == nil {
+		t.Fatalf("expected an error for an invalid schedule")
+	}
+	if _, err := nextScheduleTime("@hourly", "Mars/Olympus", now); err == nil {
+		t.Fatalf("expected an error for an unknown time zone")
+	}
+	for _, schedule := range []string{"TZ=UTC 0 2 * * *", "CRON_TZ=Europe/Berlin 0 2 * * *"} {
+		if _, err := nextScheduleTime(schedule, "", now); err == nil {
+			t.Fatalf("expected an error for the time zone prefix in %q, as the API server rejects it", schedule)
+		}
+	}
+}
+
+func TestCronJobHistoryAndStatus(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 30, 0, 0, time.UTC)
+	job := func(name string, age time.Duration, condition batchv1.JobConditionType, reason string) batchv1.Job {
+		start := metav1.NewTime(now.Add(-age))
+		j := batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "batch",
+				CreationTimestamp: start,
+				OwnerReferences:   []metav1.OwnerReference{{Kind: "CronJob", Name: "report"}},
+			},
+			Status: batchv1.JobStatus{StartTime: &start},
+		}
+		if condition != "" {
+			j.Status.Conditions = []batchv1.JobCondition{{
+				Type:               condition,
+				Status:             v1.ConditionTrue,
+				Reason:             reason,
+				LastTransitionTime: metav1.NewTime(start.Add(90 * time.Second)),
+			}}
+		}
+		if condition == batchv1.JobComplete {
+			end := metav1.NewTime(start.Add(90 * time.Second))
+			j.Status.CompletionTime = &end
+		}
+		return j
+	}
+	jobs := []batchv1.Job{
+		job("report-1", 3*time.Hour, batchv1.JobComplete, ""),
+		job("report-2", 2*time.Hour, batchv1.JobFailed, "BackoffLimitExceeded"),
+	}
+
+	cronJob := &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "report", Namespace: "batch"},
+		Spec:       batchv1.CronJobSpec{Schedule: "0 * * * *"},
+	}
+	kubeCronJob := toKubeCronJob(cronJob, groupJobsByCronJob(jobs, now)["batch/report"], now)
+
+	if len(kubeCronJob.Jobs) != 2 || kubeCronJob.Jobs[0].Name != "report-2" {
+		t.Fatalf("expected jobs newest first, got %+v", kubeCronJob.Jobs)
+	}
+	if kubeCronJob.Jobs[1].Duration != 90 || kubeCronJob.Jobs[0].Duration != 90 {
+		t.Fatalf("unexpected job durations: %v, %v", kubeCronJob.Jobs[0].Duration, kubeCronJob.Jobs[1].Duration)
+	}
+	if kubeCronJob.NextScheduleTime == nil || !kubeCronJob.NextScheduleTime.Equal(time.Date(2024, 3, 10, 13, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected next schedule: %v", kubeCronJob.NextScheduleTime)
+	}
+	if s := kubeCronJob.Status; s.Ready || s.Reason != "LastJobFailed" {
+		t.Fatalf("expected the cronjob to report its failed job, got %+v", s)
+	}
+
+	suspend := true
+	cronJob.Spec.Suspend = &suspend
+	if suspended := toKubeCronJob(cronJob, nil, now); suspended.NextScheduleTime != nil || suspended.Status.Phase != "Suspended" {
+		t.Fatalf("expected no next run while suspended, got %+v", suspended)
+	}
+}
@@ -0,0 +1,165 @@
+package kubernetes
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"kubey/api/internal/models"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// unlabeledPool is the pool name for nodes without the pool label
+const unlabeledPool = "(none)"
+
+// GetCapacity returns per-node and per-pool capacity, headroom and overcommit for a cluster.
+// Nodes are grouped into pools by the value of poolLabel.
+func GetCapacity(clusterID, poolLabel string) (*models.CapacityReport, error) {
+	cs, err := getClientsetForCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes, err := getClusterNodes(context.TODO(), cs, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildCapacityReport(clusterID, poolLabel, nodes), nil
+}
+
+// buildCapacityReport parses node capacity strings and sums pod requests and limits per node and pool
+func buildCapacityReport(clusterID, poolLabel string, nodes []models.KubeNode) *models.CapacityReport {
+	report := &models.CapacityReport{
+		ClusterID: clusterID,
+		PoolLabel: poolLabel,
+		Pools:     []models.NodePoolCapacity{},
+		Nodes:     []models.NodeCapacityReport{},
+	}
+
+	pools := map[string]*models.NodePoolCapacity{}
+	for _, node := range nodes {
+		nodeReport := getNodeCapacityReport(&node, poolLabel)
+		report.Nodes = append(report.Nodes, nodeReport)
+
+		pool, ok := pools[nodeReport.Pool]
+		if !ok {
+			pool = &models.NodePoolCapacity{Name: nodeReport.Pool}
+			pools[nodeReport.Pool] = pool
+		}
+		pool.Nodes = append(pool.Nodes, node.Name)
+		addAmounts(&pool.Allocatable, nodeReport.Allocatable)
+		addAmounts(&pool.Requested, nodeReport.Requested)
+		addAmounts(&pool.Limits, nodeReport.Limits)
+		addAmounts(&pool.Headroom, nodeReport.Headroom)
+
+		// Only ready nodes with a free pod slot can take a new pod
+		if !nodeReport.Ready || nodeReport.Headroom.Pods <= 0 {
+			continue
+		}
+		free := models.PodFit{
+			Node:   node.Name,
+			CPU:    math.Max(nodeReport.Headroom.CPU, 0),
+			Memory: max(nodeReport.Headroom.Memory, 0),
+		}
+		if pool.LargestCPUFit == nil || free.CPU > pool.LargestCPUFit.CPU {
+			fit := free
+			pool.LargestCPUFit = &fit
+		}
+		if pool.LargestMemoryFit == nil || free.Memory > pool.LargestMemoryFit.Memory {
+			fit := free
+			pool.LargestMemoryFit = &fit
+		}
+	}
+
+	for _, pool := range pools {
+		pool.CPUOvercommit = ratio(pool.Limits.CPU, pool.Allocatable.CPU)
+		pool.MemoryOvercommit = ratio(float64(pool.Limits.Memory), float64(pool.Allocatable.Memory))
+		report.Pools = append(report.Pools, *pool)
+	}
+
+	sort.Slice(report.Pools, func(i, j int) bool {
+		return report.Pools[i].Name < report.Pools[j].Name
+	})
+	sort.Slice(report.Nodes, func(i, j int) bool {
+		return report.Nodes[i].Name < report.Nodes[j].Name
+	})
+
+	return report
+}
+
+// getNodeCapacityReport computes capacity, requests, limits and headroom for one node
+func getNodeCapacityReport(node *models.KubeNode, poolLabel string) models.NodeCapacityReport {
+	pool := node.Labels[poolLabel]
+	if pool == "" {
+		pool = unlabeledPool
+	}
+
+	nodeReport := models.NodeCapacityReport{
+		Name:        node.Name,
+		Pool:        pool,
+		Ready:       node.Status.Ready,
+		Capacity:    parseNodeCapacity(node.Capacity),
+		Allocatable: parseNodeCapacity(node.Allocatable),
+	}
+
+	// Requests and limits come from the node's metrics, which add up its non-terminal pods
+	if metrics := node.Metrics; metrics != nil {
+		nodeReport.Requested.CPU = metrics.CPURequest
+		nodeReport.Requested.Memory = metrics.MemoryRequest
+		nodeReport.Limits.CPU = metrics.CPULimit
+		nodeReport.Limits.Memory = metrics.MemoryLimit
+	}
+	for i := range node.Pods {
+		if !isKubePodTerminal(&node.Pods[i]) {
+			nodeReport.Requested.Pods++
+			nodeReport.Limits.Pods++
+		}
+	}
+
+	nodeReport.Headroom = models.ResourceAmounts{
+		CPU:    nodeReport.Allocatable.CPU - nodeReport.Requested.CPU,
+		Memory: nodeReport.Allocatable.Memory - nodeReport.Requested.Memory,
+		Pods:   nodeReport.Allocatable.Pods - nodeReport.Requested.Pods,
+	}
+	nodeReport.CPUOvercommit = ratio(nodeReport.Limits.CPU, nodeReport.Allocatable.CPU)
+	nodeReport.MemoryOvercommit = ratio(float64(nodeReport.Limits.Memory), float64(nodeReport.Allocatable.Memory))
+
+	return nodeReport
+}
+
+// parseNodeCapacity parses the quantity strings of a NodeCapacity; unparsable values count as zero
+func parseNodeCapacity(capacity models.NodeCapacity) models.ResourceAmounts {
+	parse := func(value string) resource.Quantity {
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			return resource.Quantity{}
+		}
+		return quantity
+	}
+
+	cpu := parse(capacity.CPU)
+	memory := parse(capacity.Memory)
+	pods := parse(capacity.Pods)
+
+	return models.ResourceAmounts{
+		CPU:    float64(cpu.MilliValue()),
+		Memory: memory.Value(),
+		Pods:   pods.Value(),
+	}
+}
+
+func addAmounts(total *models.ResourceAmounts, amounts models.ResourceAmounts) {
+	total.CPU += amounts.CPU
+	total.Memory += amounts.Memory
+	total.Pods += amounts.Pods
+}
+
+// ratio returns part/total rounded to two decimal places
+func ratio(part, total float64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return math.Round(part/total*100) / 100
+}
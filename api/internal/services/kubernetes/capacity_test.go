@@ -0,0 +1,91 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"kubey/api/internal/models"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestBuildCapacityReport(t *testing.T) {
+	pod := func(phase string, cpuRequest, cpuLimit float64, memoryRequest int64) models.KubePod {
+		return models.KubePod{
+			Status: models.ResourceStatus{Phase: phase},
+			Metrics: &models.ResourceMetrics{
+				CPURequest:    cpuRequest,
+				CPULimit:      cpuLimit,
+				MemoryRequest: memoryRequest,
+			},
+		}
+	}
+	// Node metrics hold the totals of the non-terminal pods, as getClusterNodes builds them
+	node := func(name, pool string, ready bool, pods ...models.KubePod) models.KubeNode {
+		metrics := &models.ResourceMetrics{}
+		for i := range pods {
+			if !isKubePodTerminal(&pods[i]) {
+				metrics.CPURequest += pods[i].Metrics.CPURequest
+				metrics.MemoryRequest += pods[i].Metrics.MemoryRequest
+				metrics.CPULimit += pods[i].Metrics.CPULimit
+				metrics.MemoryLimit += pods[i].Metrics.MemoryLimit
+			}
+		}
+		return models.KubeNode{
+			Name:        name,
+			Labels:      map[string]string{"pool": pool},
+			Status:      models.ResourceStatus{Ready: ready},
+			Allocatable: models.NodeCapacity{CPU: "4", Memory: "8Gi", Pods: "110"},
+			Pods:        pods,
+			Metrics:     metrics,
+		}
+	}
+
+	report := buildCapacityReport("context-test", "pool", []models.KubeNode{
+		node("a", "general", true, pod("Running", 1000, 3000, 2<<30), pod("Succeeded", 2000, 0, 0)),
+		node("b", "general", true, pod("Running", 3000, 6000, 1<<30)),
+		node("c", "general", false),
+		{Name: "d", Allocatable: models.NodeCapacity{CPU: "2", Memory: "4Gi", Pods: "10"}, Status: models.ResourceStatus{Ready: true}},
+	})
+
+	if len(report.Pools) != 2 || report.Pools[0].Name != unlabeledPool || report.Pools[1].Name != "general" {
+		t.Fatalf("unexpected pools: %+v", report.Pools)
+	}
+
+	nodeA := report.Nodes[0]
+	if nodeA.Requested.CPU != 1000 || nodeA.Requested.Pods != 1 || nodeA.Headroom.CPU != 3000 || nodeA.Headroom.Memory != 6<<30 {
+		t.Fatalf("unexpected node a report: %+v", nodeA)
+	}
+	if nodeA.CPUOvercommit != 0.75 {
+		t.Fatalf("expected node a CPU overcommit 0.75, got %v", nodeA.CPUOvercommit)
+	}
+
+	general := report.Pools[1]
+	if general.Allocatable.CPU != 12000 || general.Requested.CPU != 4000 || general.CPUOvercommit != 0.75 {
+		t.Fatalf("unexpected pool totals: %+v", general)
+	}
+	// Node c has the most free resources but is not ready, so it must not be offered as a fit.
+	if general.LargestCPUFit == nil || general.LargestCPUFit.Node != "a" || general.LargestCPUFit.CPU != 3000 {
+		t.Fatalf("unexpected largest CPU fit: %+v", general.LargestCPUFit)
+	}
+	if general.LargestMemoryFit == nil || general.LargestMemoryFit.Node != "b" || general.LargestMemoryFit.Memory != 7<<30 {
+		t.Fatalf("unexpected largest memory fit: %+v", general.LargestMemoryFit)
+	}
+}
+
+func TestGetClusterNodesPodListFailure(t *testing.T) {
+	cs := fake.NewSimpleClientset(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}})
+	cs.PrependReactor("list", "pods", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("etcdserver: request timed out")
+	})
+
+	// Reporting the node without its pods would show full headroom, so the listing must fail
+	if nodes, err := getClusterNodes(context.Background(), cs, false); err == nil {
+		t.Fatalf("expected an error when pods cannot be listed, got %d nodes", len(nodes))
+	}
+}
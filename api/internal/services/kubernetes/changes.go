@@ -0,0 +1,156 @@
+package kubernetes
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"kubey/api/internal/models"
+	"kubey/api/internal/store"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+)
+
+// changePruneInterval is how often expired changes are removed from the store
+const changePruneInterval = 10 * time.Minute
+
+// RecordChanges records spec-level changes to Deployments, StatefulSets, DaemonSets,
+// ConfigMaps and Services in every watched cluster, keeping them for the retention period.
+func RecordChanges(ctx context.Context, s *store.Store, retention time.Duration) {
+	registerInformers(func(clusterID string, factory informers.SharedInformerFactory) {
+		apps := factory.Apps().V1()
+		core := factory.Core().V1()
+
+		apps.Deployments().Informer().AddEventHandler(changeHandler(s, clusterID, "Deployment", "spec"))
+		apps.StatefulSets().Informer().AddEventHandler(changeHandler(s, clusterID, "StatefulSet", "spec"))
+		apps.DaemonSets().Informer().AddEventHandler(changeHandler(s, clusterID, "DaemonSet", "spec"))
+		core.ConfigMaps().Informer().AddEventHandler(changeHandler(s, clusterID, "ConfigMap", "data", "binaryData"))
+		core.Services().Informer().AddEventHandler(changeHandler(s, clusterID, "Service", "spec"))
+	})
+
+	go func() {
+		ticker := time.NewTicker(changePruneInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				removed, err := s.PruneChanges(time.Now().Add(-retention))
+				if err != nil {
+					log.Printf("Failed to prune recorded changes: %v", err)
+				} else if removed > 0 {
+					log.Printf("Pruned %d recorded changes older than %v", removed, retention)
+				}
+			}
+		}
+	}()
+}
+
+// QueryChanges searches recorded changes. The cluster must be in the kubeconfig.
+func QueryChanges(s *store.Store, query models.ChangeQuery) ([]models.ResourceChange, error) {
+	if err := checkRecordQuery(query.ClusterID, query.Since, query.Until); err != nil {
+		return nil, err
+	}
+	return s.QueryChanges(query)
+}
+
+// changeHandler returns informer handlers that diff the given top-level fields of an object
+func changeHandler(s *store.Store, clusterID, kind string, roots ...string) cache.ResourceEventHandler {
+	record := func(action string, obj interface{}, fields []models.FieldChange) {
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			return
+		}
+		if fields == nil {
+			fields = []models.FieldChange{}
+		}
+
+		change := models.ResourceChange{
+			ClusterID: clusterID,
+			Object: models.ObjectReference{
+				Kind:      kind,
+				Namespace: accessor.GetNamespace(),
+				Name:      accessor.GetName(),
+				UID:       string(accessor.GetUID()),
+			},
+			Action:          action,
+			Categories:      categorizeChanges(kind, fields),
+			Fields:          fields,
+			Generation:      accessor.GetGeneration(),
+			ResourceVersion: accessor.GetResourceVersion(),
+			Timestamp:       time.Now(),
+		}
+		if action != "Deleted" {
+			change.Manager = latestManager(accessor.GetManagedFields())
+		}
+
+		if err := s.SaveChange(change); err != nil {
+			log.Printf("Failed to record change to %s %s/%s for %s: %v", kind, change.Object.Namespace, change.Object.Name, clusterID, err)
+		}
+	}
+
+	return cache.ResourceEventHandlerDetailedFuncs{
+		AddFunc: func(obj interface{}, isInInitialList bool) {
+			// Objects listed when the watch starts already existed; only new objects are changes
+			if isInInitialList {
+				return
+			}
+			record("Created", obj, nil)
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			fields := diffRoots(oldObj, newObj, roots)
+			if len(fields) == 0 {
+				return
+			}
+			record("Updated", newObj, fields)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			record("Deleted", obj, nil)
+		},
+	}
+}
+
+// diffRoots diffs the named top-level fields of two typed objects
+func diffRoots(oldObj, newObj interface{}, roots []string) []models.FieldChange {
+	oldMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(oldObj)
+	if err != nil {
+		return nil
+	}
+	newMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(newObj)
+	if err != nil {
+		return nil
+	}
+
+	var fields []models.FieldChange
+	for _, root := range roots {
+		fields = append(fields, diffFields(root, oldMap[root], newMap[root])...)
+	}
+	return fields
+}
+
+// latestManager returns the field manager of the most recent non-status write
+func latestManager(entries []metav1.ManagedFieldsEntry) string {
+	var manager string
+	var latest time.Time
+
+	for _, entry := range entries {
+		if entry.Subresource != "" || entry.Time == nil {
+			continue
+		}
+		if !entry.Time.Time.Before(latest) {
+			manager = entry.Manager
+			latest = entry.Time.Time
+		}
+	}
+
+	return manager
+}
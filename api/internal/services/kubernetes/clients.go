@@ -0,0 +1,242 @@
+package kubernetes
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"kubey/api/internal/metrics"
+
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// ErrClusterNotFound is returned when a cluster ID does not match any kubeconfig context
+var ErrClusterNotFound = errors.New("cluster not found")
+
+// ErrResourceNotFound is returned when a requested Kubernetes object does not exist
+var ErrResourceNotFound = errors.New("resource not found")
+
+// ErrForbidden is returned when kubey's credentials are not allowed to perform an action
+var ErrForbidden = errors.New("forbidden")
+
+// ErrInvalidArgument is returned when a request parameter is not supported
+var ErrInvalidArgument = errors.New("invalid argument")
+
+// ErrNotRecorded is returned when a report needs history that kubey is configured not to record
+var ErrNotRecorded = errors.New("not recorded")
+
+// clusterIDPrefix is prepended to kubeconfig context names to build cluster IDs
+const clusterIDPrefix = "context-"
+
+// clusterIDForContext returns the cluster ID used by the API for a kubeconfig context
+func clusterIDForContext(contextName string) string {
+	return clusterIDPrefix + contextName
+}
+
+// contextNameForCluster resolves a cluster ID to its kubeconfig context name
+func contextNameForCluster(clusterID string) (string, error) {
+	if kubeconfigPath == "" {
+		return "", fmt.Errorf("kubernetes client not initialized")
+	}
+
+	config, err := clientcmd.LoadFromFile(kubeconfigPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to load kubeconfig: %v", err)
+	}
+
+	contextName := strings.TrimPrefix(clusterID, clusterIDPrefix)
+	if _, ok := config.Contexts[contextName]; !ok {
+		return "", ErrClusterNotFound
+	}
+
+	return contextName, nil
+}
+
+// listContextNames returns the names of all contexts in the kubeconfig
+func listContextNames() ([]string, error) {
+	if kubeconfigPath == "" {
+		return nil, fmt.Errorf("kubernetes client not initialized")
+	}
+
+	config, err := clientcmd.LoadFromFile(kubeconfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %v", err)
+	}
+
+	var contextNames []string
+	for contextName := range config.Contexts {
+		contextNames = append(contextNames, contextName)
+	}
+	if len(contextNames) == 0 {
+		return nil, fmt.Errorf("no contexts found in kubeconfig")
+	}
+
+	return contextNames, nil
+}
+
+// restConfigForContext builds an instrumented REST config for the given kubeconfig context
+func restConfigForContext(contextName string, timeout time.Duration) (*rest.Config, error) {
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		&clientcmd.ClientConfigLoadingRules{ExplicitPath: kubeconfigPath},
+		&clientcmd.ConfigOverrides{CurrentContext: contextName},
+	).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build config for context %s: %v", contextName, err)
+	}
+
+	config.Timeout = timeout
+	config.Wrap(metrics.InstrumentTransport(clusterIDForContext(contextName)))
+	return config, nil
+}
+
+// newClientsetForContext creates a clientset for the given kubeconfig context
+func newClientsetForContext(contextName string, timeout time.Duration) (*kubernetes.Clientset, error) {
+	config, err := restConfigForContext(contextName, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	cs, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create clientset for context %s: %v", contextName, err)
+	}
+
+	return cs, nil
+}
+
+const (
+	// discoveryCacheTTL is how long discovery results are reused before a cluster is asked again,
+	// so that newly installed CRDs show up
+	discoveryCacheTTL = 10 * time.Minute
+	// clientCacheTTL is how long a cluster's clients are reused before they are rebuilt from the
+	// kubeconfig, so that rotated credentials referenced by path are picked up
+	clientCacheTTL = 30 * time.Minute
+)
+
+// cachedDiscovery is a discovery client and when its cache was last invalidated
+type cachedDiscovery struct {
+	client    discovery.CachedDiscoveryInterface
+	created   time.Time
+	refreshed time.Time
+}
+
+// cachedClient is a client and when it was built from the kubeconfig
+type cachedClient[T any] struct {
+	client  T
+	created time.Time
+}
+
+var (
+	clientsetsMu     sync.Mutex
+	clientsets       = map[string]cachedClient[*kubernetes.Clientset]{}
+	dynamicClients   = map[string]cachedClient[dynamic.Interface]{}
+	discoveryClients = map[string]*cachedDiscovery{}
+)
+
+// clientExpired reports whether a client built at created must be rebuilt, either because it
+// is older than clientCacheTTL or because the kubeconfig changed since
+func clientExpired(created time.Time) bool {
+	if time.Since(created) >= clientCacheTTL {
+		return true
+	}
+	info, err := os.Stat(kubeconfigPath)
+	return err == nil && info.ModTime().After(created)
+}
+
+// getClientsetForCluster returns a clientset for the cluster with the given ID.
+// Clientsets are cached per cluster so that repeated requests skip reloading the kubeconfig.
+func getClientsetForCluster(clusterID string) (*kubernetes.Clientset, error) {
+	clientsetsMu.Lock()
+	cached, ok := clientsets[clusterID]
+	clientsetsMu.Unlock()
+	ok = ok && !clientExpired(cached.created)
+	metrics.CacheLookup("clientset", ok)
+	if ok {
+		return cached.client, nil
+	}
+
+	contextName, err := contextNameForCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	created := time.Now()
+	cs, err := newClientsetForContext(contextName, 30*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	clientsetsMu.Lock()
+	clientsets[clusterID] = cachedClient[*kubernetes.Clientset]{client: cs, created: created}
+	clientsetsMu.Unlock()
+	return cs, nil
+}
+
+// getDynamicClientForCluster returns a dynamic client for the cluster with the given ID, for resources
+// without typed clients such as Gateway API objects and custom resources. Clients are cached per cluster.
+func getDynamicClientForCluster(clusterID string) (dynamic.Interface, error) {
+	clientsetsMu.Lock()
+	cached, ok := dynamicClients[clusterID]
+	clientsetsMu.Unlock()
+	ok = ok && !clientExpired(cached.created)
+	metrics.CacheLookup("dynamic_client", ok)
+	if ok {
+		return cached.client, nil
+	}
+
+	contextName, err := contextNameForCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	created := time.Now()
+	config, err := restConfigForContext(contextName, 30*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	client, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dynamic client for context %s: %v", contextName, err)
+	}
+
+	clientsetsMu.Lock()
+	dynamicClients[clusterID] = cachedClient[dynamic.Interface]{client: client, created: created}
+	clientsetsMu.Unlock()
+	return client, nil
+}
+
+// getDiscoveryForCluster returns a discovery client for the cluster with the given ID whose
+// results are kept in memory for discoveryCacheTTL. The client is rebuilt with its clientset.
+func getDiscoveryForCluster(clusterID string) (discovery.CachedDiscoveryInterface, error) {
+	clientsetsMu.Lock()
+	cached, ok := discoveryClients[clusterID]
+	ok = ok && !clientExpired(cached.created)
+	fresh := ok && time.Since(cached.refreshed) < discoveryCacheTTL
+	if ok && !fresh {
+		cached.client.Invalidate()
+		cached.refreshed = time.Now()
+	}
+	clientsetsMu.Unlock()
+	metrics.CacheLookup("discovery", fresh)
+	if ok {
+		return cached.client, nil
+	}
+
+	cs, err := getClientsetForCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	client := memory.NewMemCacheClient(cs.Discovery())
+
+	now := time.Now()
+	clientsetsMu.Lock()
+	discoveryClients[clusterID] = &cachedDiscovery{client: client, created: now, refreshed: now}
+	clientsetsMu.Unlock()
+	return client, nil
+}
@@ -0,0 +1,32 @@
+package kubernetes
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestClientExpired(t *testing.T) {
+	previous := kubeconfigPath
+	defer func() { kubeconfigPath = previous }()
+
+	kubeconfigPath = filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(kubeconfigPath, []byte("apiVersion: v1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	modified := time.Now().Add(-time.Minute)
+	if err := os.Chtimes(kubeconfigPath, modified, modified); err != nil {
+		t.Fatal(err)
+	}
+
+	if clientExpired(time.Now()) {
+		t.Fatalf("expected a fresh client to be reused")
+	}
+	if !clientExpired(time.Now().Add(-clientCacheTTL)) {
+		t.Fatalf("expected a client older than the TTL to expire")
+	}
+	if !clientExpired(modified.Add(-time.Second)) {
+		t.Fatalf("expected a client built before the kubeconfig changed to expire")
+	}
+}
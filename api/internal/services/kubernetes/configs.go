@@ -0,0 +1,328 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"slices"
+	"sort"
+	"unicode/utf8"
+
+	"kubey/api/internal/models"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// GetConfigMaps returns the ConfigMaps of a cluster with the pods that use them.
+// Values longer than maxValueBytes are truncated; an empty namespace lists all namespaces.
+func GetConfigMaps(clusterID, namespace string, maxValueBytes int) ([]models.KubeConfigMap, error) {
+	cs, err := getClientsetForCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := cs.CoreV1().ConfigMaps(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list configmaps: %v", err)
+	}
+	references, err := getConfigReferenceIndex(context.TODO(), cs, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	configMaps := []models.KubeConfigMap{}
+	for i := range list.Items {
+		configMap := &list.Items[i]
+		configMaps = append(configMaps, toKubeConfigMap(configMap, maxValueBytes, references[configKey("ConfigMap", configMap.Namespace, configMap.Name)]))
+	}
+	return configMaps, nil
+}
+
+// GetConfigMap returns a single ConfigMap with the pods that use it
+func GetConfigMap(clusterID, namespace, name string, maxValueBytes int) (*models.KubeConfigMap, error) {
+	cs, err := getClientsetForCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	configMap, err := cs.CoreV1().ConfigMaps(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, getError(err, "configmap", namespace, name)
+	}
+	references, err := getConfigReferenceIndex(context.TODO(), cs, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	kubeConfigMap := toKubeConfigMap(configMap, maxValueBytes, references[configKey("ConfigMap", namespace, name)])
+	return &kubeConfigMap, nil
+}
+
+// GetSecrets returns the Secrets of a cluster with the pods that use them.
+// Only key names and value sizes are returned. An empty namespace lists all namespaces.
+func GetSecrets(clusterID, namespace string) ([]models.KubeSecret, error) {
+	cs, err := getClientsetForCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := cs.CoreV1().Secrets(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list secrets: %v", err)
+	}
+	references, err := getConfigReferenceIndex(context.TODO(), cs, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	secrets := []models.KubeSecret{}
+	for i := range list.Items {
+		secret := &list.Items[i]
+		secrets = append(secrets, toKubeSecret(secret, references[configKey("Secret", secret.Namespace, secret.Name)]))
+	}
+	return secrets, nil
+}
+
+// GetSecret returns a single Secret, without its values, and the pods that use it
+func GetSecret(clusterID, namespace, name string) (*models.KubeSecret, error) {
+	cs, err := getClientsetForCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := cs.CoreV1().Secrets(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, getError(err, "secret", namespace, name)
+	}
+	references, err := getConfigReferenceIndex(context.TODO(), cs, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	kubeSecret := toKubeSecret(secret, references[configKey("Secret", namespace, name)])
+	return &kubeSecret, nil
+}
+
+// RevealSecret returns the values of a Secret, or only the value of key when it is set.
+// Secrets of the denied types, such as service account tokens, are never revealed.
+func RevealSecret(clusterID, namespace, name, key string, deniedTypes []string) (*models.RevealedSecret, error) {
+	cs, err := getClientsetForCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := cs.CoreV1().Secrets(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, getError(err, "secret", namespace, name)
+	}
+	return revealSecret(secret, key, deniedTypes)
+}
+
+// revealSecret returns the values of a Secret, or only the value of key when it is set
+func revealSecret(secret *v1.Secret, key string, deniedTypes []string) (*models.RevealedSecret, error) {
+	if slices.Contains(deniedTypes, string(secret.Type)) {
+		return nil, fmt.Errorf("%w: secrets of type %s cannot be revealed", ErrForbidden, secret.Type)
+	}
+
+	revealed := &models.RevealedSecret{
+		Name:      secret.Name,
+		Namespace: secret.Namespace,
+		Type:      string(secret.Type),
+		Values:    []models.SecretValue{},
+	}
+	for _, k := range sortedKeys(secret.Data) {
+		if key != "" && k != key {
+			continue
+		}
+		revealed.Values = append(revealed.Values, toSecretValue(k, secret.Data[k]))
+	}
+	if key != "" && len(revealed.Values) == 0 {
+		return nil, fmt.Errorf("%w: key %s in secret %s/%s", ErrResourceNotFound, key, secret.Namespace, secret.Name)
+	}
+
+	return revealed, nil
+}
+
+func toKubeConfigMap(configMap *v1.ConfigMap, maxValueBytes int, usedBy []models.ConfigReference) models.KubeConfigMap {
+	kubeConfigMap := models.KubeConfigMap{
+		Name:      configMap.Name,
+		Namespace: configMap.Namespace,
+		Keys:      []models.ConfigKey{},
+		Immutable: configMap.Immutable != nil && *configMap.Immutable,
+		UsedBy:    usedBy,
+		Labels:    configMap.Labels,
+		CreatedAt: configMap.CreationTimestamp.Time,
+	}
+	if kubeConfigMap.UsedBy == nil {
+		kubeConfigMap.UsedBy = []models.ConfigReference{}
+	}
+
+	for _, key := range sortedKeys(configMap.Data) {
+		value := configMap.Data[key]
+		entry := models.ConfigKey{Key: key, Size: len(value)}
+		if maxValueBytes > 0 {
+			shown, truncated := truncateConfigValue(value, maxValueBytes)
+			entry.Value = &shown
+			entry.Truncated = truncated
+		}
+		kubeConfigMap.Keys = append(kubeConfigMap.Keys, entry)
+		kubeConfigMap.TotalSize += entry.Size
+	}
+	for _, key := range sortedKeys(configMap.BinaryData) {
+		size := len(configMap.BinaryData[key])
+		kubeConfigMap.Keys = append(kubeConfigMap.Keys, models.ConfigKey{Key: key, Size: size, Binary: true})
+		kubeConfigMap.TotalSize += size
+	}
+
+	return kubeConfigMap
+}
+
+// toKubeSecret converts a Secret into the API model. Values and annotations are left out:
+// the last-applied-configuration annotation can hold the Secret data.
+func toKubeSecret(secret *v1.Secret, usedBy []models.ConfigReference) models.KubeSecret {
+	kubeSecret := models.KubeSecret{
+		Name:      secret.Name,
+		Namespace: secret.Namespace,
+		Type:      string(secret.Type),
+		Keys:      []models.ConfigKey{},
+		Immutable: secret.Immutable != nil && *secret.Immutable,
+		UsedBy:    usedBy,
+		Labels:    secret.Labels,
+		CreatedAt: secret.CreationTimestamp.Time,
+	}
+	if kubeSecret.UsedBy == nil {
+		kubeSecret.UsedBy = []models.ConfigReference{}
+	}
+
+	for _, key := range sortedKeys(secret.Data) {
+		size := len(secret.Data[key])
+		kubeSecret.Keys = append(kubeSecret.Keys, models.ConfigKey{Key: key, Size: size})
+		kubeSecret.TotalSize += size
+	}
+
+	return kubeSecret
+}
+
+func toSecretValue(key string, data []byte) models.SecretValue {
+	if utf8.Valid(data) {
+		return models.SecretValue{Key: key, Value: string(data), Encoding: "text"}
+	}
+	return models.SecretValue{Key: key, Value: base64.StdEncoding.EncodeToString(data), Encoding: "base64"}
+}
+
+// truncateConfigValue cuts value to at most maxBytes without splitting a UTF-8 character
+func truncateConfigValue(value string, maxBytes int) (string, bool) {
+	if len(value) <= maxBytes {
+		return value, false
+	}
+	cut := maxBytes
+	for cut > 0 && !utf8.RuneStart(value[cut]) {
+		cut--
+	}
+	return value[:cut], true
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// configKey identifies a ConfigMap or Secret in a reference index
+func configKey(kind, namespace, name string) string {
+	return kind + "/" + namespace + "/" + name
+}
+
+// getConfigReferenceIndex maps each ConfigMap and Secret used by the pods in a namespace
+// (all namespaces when empty) to those uses, keyed by configKey
+func getConfigReferenceIndex(ctx context.Context, cs kubernetes.Interface, namespace string) (map[string][]models.ConfigReference, error) {
+	pods, err := cs.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %v", err)
+	}
+
+	index := map[string][]models.ConfigReference{}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		for _, ref := range podConfigReferences(pod) {
+			key := configKey(ref.kind, pod.Namespace, ref.name)
+			index[key] = append(index[key], ref.ConfigReference)
+		}
+	}
+	return index, nil
+}
+
+// podConfigReference is a use of a ConfigMap or Secret by a pod
+type podConfigReference struct {
+	models.ConfigReference
+	kind string // ConfigMap or Secret
+	name string
+}
+
+// podConfigReferences lists the ConfigMaps and Secrets a pod mounts, reads into its
+// environment or pulls images with
+func podConfigReferences(pod *v1.Pod) []podConfigReference {
+	var refs []podConfigReference
+	add := func(kind, name string, ref models.ConfigReference) {
+		ref.Pod = pod.Name
+		refs = append(refs, podConfigReference{ConfigReference: ref, kind: kind, name: name})
+	}
+
+	for i := range pod.Spec.Volumes {
+		volume := &pod.Spec.Volumes[i]
+		ref := models.ConfigReference{Via: "volume", Source: volume.Name}
+		switch getVolumeType(volume) {
+		case "configMap":
+			add("ConfigMap", volume.ConfigMap.Name, ref)
+		case "secret":
+			add("Secret", volume.Secret.SecretName, ref)
+		case "projected":
+			for _, source := range volume.Projected.Sources {
+				if source.ConfigMap != nil {
+					add("ConfigMap", source.ConfigMap.Name, ref)
+				}
+				if source.Secret != nil {
+					add("Secret", source.Secret.Name, ref)
+				}
+			}
+		}
+	}
+
+	containers := append(append([]v1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...)
+	for _, container := range containers {
+		for _, env := range container.Env {
+			if env.ValueFrom == nil {
+				continue
+			}
+			ref := models.ConfigReference{Container: container.Name, Via: "env", Source: env.Name}
+			if selector := env.ValueFrom.ConfigMapKeyRef; selector != nil {
+				ref.Key = selector.Key
+				add("ConfigMap", selector.Name, ref)
+			}
+			if selector := env.ValueFrom.SecretKeyRef; selector != nil {
+				ref.Key = selector.Key
+				add("Secret", selector.Name, ref)
+			}
+		}
+		for _, envFrom := range container.EnvFrom {
+			ref := models.ConfigReference{Container: container.Name, Via: "envFrom"}
+			if envFrom.ConfigMapRef != nil {
+				add("ConfigMap", envFrom.ConfigMapRef.Name, ref)
+			}
+			if envFrom.SecretRef != nil {
+				add("Secret", envFrom.SecretRef.Name, ref)
+			}
+		}
+	}
+
+	for _, pullSecret := range pod.Spec.ImagePullSecrets {
+		add("Secret", pullSecret.Name, models.ConfigReference{Via: "imagePullSecret"})
+	}
+
+	return refs
+}
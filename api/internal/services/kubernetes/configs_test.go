@@ -0,0 +1,97 @@
+package kubernetes
+
+import (
+	"errors"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestConfigReferences(t *testing.T) {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "shop"},
+		Spec: v1.PodSpec{
+			Volumes: []v1.Volume{
+				{Name: "config", VolumeSource: v1.VolumeSource{ConfigMap: &v1.ConfigMapVolumeSource{
+					LocalObjectReference: v1.LocalObjectReference{Name: "web-config"},
+				}}},
+				{Name: "bundle", VolumeSource: v1.VolumeSource{Projected: &v1.ProjectedVolumeSource{Sources: []v1.VolumeProjection{
+					{Secret: &v1.SecretProjection{LocalObjectReference: v1.LocalObjectReference{Name: "web-tls"}}},
+				}}}},
+			},
+			Containers: []v1.Container{{
+				Name: "web",
+				Env: []v1.EnvVar{{Name: "DB_PASSWORD", ValueFrom: &v1.EnvVarSource{SecretKeyRef: &v1.SecretKeySelector{
+					LocalObjectReference: v1.LocalObjectReference{Name: "db"}, Key: "password",
+				}}}},
+				EnvFrom: []v1.EnvFromSource{{ConfigMapRef: &v1.ConfigMapEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "web-config"}}}},
+			}},
+			ImagePullSecrets: []v1.LocalObjectReference{{Name: "registry"}},
+		},
+	}
+
+	index := map[string]int{}
+	for _, ref := range podConfigReferences(pod) {
+		index[ref.kind+"/"+ref.name+"/"+ref.Via]++
+		if ref.Pod != "web-1" {
+			t.Fatalf("expected the pod name on every reference, got %+v", ref)
+		}
+		if ref.Via == "env" && (ref.Key != "password" || ref.Source != "DB_PASSWORD" || ref.Container != "web") {
+			t.Fatalf("unexpected env reference: %+v", ref)
+		}
+	}
+	for _, want := range []string{"ConfigMap/web-config/volume", "ConfigMap/web-config/envFrom", "Secret/web-tls/volume", "Secret/db/env", "Secret/registry/imagePullSecret"} {
+		if index[want] != 1 {
+			t.Fatalf("expected one %s reference, got %v", want, index)
+		}
+	}
+}
+
+func TestConfigMapAndSecretRedaction(t *testing.T) {
+	configMap := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-config", Namespace: "shop"},
+		Data:       map[string]string{"short": "ok", "long": "héllo world"},
+		BinaryData: map[string][]byte{"logo.png": {0x89, 0x50, 0x4e, 0x47}},
+	}
+	kubeConfigMap := toKubeConfigMap(configMap, 2, nil)
+	if len(kubeConfigMap.Keys) != 3 || kubeConfigMap.TotalSize != 2+12+4 || kubeConfigMap.UsedBy == nil {
+		t.Fatalf("unexpected configmap: %+v", kubeConfigMap)
+	}
+	// Keys are sorted, so "long" comes first; the cap must not split the two-byte "é"
+	if long := kubeConfigMap.Keys[0]; *long.Value != "h" || !long.Truncated || long.Size != 12 {
+		t.Fatalf("unexpected truncated value: %+v", long)
+	}
+	if short := kubeConfigMap.Keys[1]; *short.Value != "ok" || short.Truncated {
+		t.Fatalf("unexpected short value: %+v", short)
+	}
+	if binary := kubeConfigMap.Keys[2]; !binary.Binary || binary.Value != nil {
+		t.Fatalf("expected binary data without a value, got %+v", binary)
+	}
+	if keys := toKubeConfigMap(configMap, 0, nil).Keys; keys[0].Value != nil {
+		t.Fatalf("expected no values with a zero cap, got %+v", keys[0])
+	}
+
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "shop"},
+		Type:       v1.SecretTypeOpaque,
+		Data:       map[string][]byte{"password": []byte("hunter2")},
+	}
+	kubeSecret := toKubeSecret(secret, nil)
+	if len(kubeSecret.Keys) != 1 || kubeSecret.Keys[0].Size != 7 || kubeSecret.Keys[0].Value != nil {
+		t.Fatalf("expected only key sizes for a secret, got %+v", kubeSecret.Keys)
+	}
+
+	deny := []string{string(v1.SecretTypeServiceAccountToken), string(v1.SecretTypeDockerConfigJson)}
+	if revealed, err := revealSecret(secret, "password", deny); err != nil || revealed.Values[0].Value != "hunter2" {
+		t.Fatalf("expected the opaque secret to be revealed, got %+v, %v", revealed, err)
+	}
+	secret.Type = v1.SecretTypeServiceAccountToken
+	if _, err := revealSecret(secret, "", deny); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("expected a service account token to be forbidden, got %v", err)
+	}
+
+	if value := toSecretValue("cert", []byte{0xff, 0xfe}); value.Encoding != "base64" || value.Value != "//4=" {
+		t.Fatalf("expected binary values to be base64-encoded, got %+v", value)
+	}
+}
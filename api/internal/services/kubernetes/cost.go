@@ -0,0 +1,313 @@
+package kubernetes
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"kubey/api/internal/models"
+	"kubey/api/internal/store"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// bytesPerGiB converts bytes to the GiB used for memory pricing
+const bytesPerGiB = 1 << 30
+
+// namespaceDimension groups cost by namespace; label dimensions are "label:<key>"
+const namespaceDimension = "namespace"
+
+// unlabeledCostGroup collects pods without the label being aggregated by
+const unlabeledCostGroup = "(none)"
+
+// CostOptions selects the time range, allocation basis and prices for a cost report
+type CostOptions struct {
+	Since        time.Time
+	Until        time.Time
+	Basis        string // requests or usage
+	AggregateBy  string // namespace or label:<key>
+	Currency     string
+	DefaultPrice models.ResourcePrice
+	PoolPrices   map[string]models.ResourcePrice // By node pool label value
+}
+
+// GetCostReport allocates recorded node cost over the time range to namespaces or label values
+func GetCostReport(clusterID string, opts CostOptions, s *store.Store) (*models.CostReport, error) {
+	if opts.Basis != "requests" && opts.Basis != "usage" {
+		return nil, fmt.Errorf("%w: unknown basis %q, expected requests or usage", ErrInvalidArgument, opts.Basis)
+	}
+	if _, err := contextNameForCluster(clusterID); err != nil {
+		return nil, err
+	}
+
+	records, err := s.QueryCostUsage(clusterID, opts.Since.Truncate(time.Hour), opts.Until)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildCostReport(clusterID, opts, records), nil
+}
+
+// buildCostReport prices the recorded resource-hours. Allocations are charged for requests or
+// usage; idle cost is requested but unused resources and unallocated cost is capacity nobody requested.
+func buildCostReport(clusterID string, opts CostOptions, records []models.CostUsage) *models.CostReport {
+	report := &models.CostReport{
+		ClusterID:   clusterID,
+		Start:       opts.Since,
+		End:         opts.Until,
+		Basis:       opts.Basis,
+		AggregateBy: opts.AggregateBy,
+		Currency:    opts.Currency,
+		Allocations: []models.CostAllocation{},
+		Pools:       []models.PoolCost{},
+	}
+
+	allocations := map[string]*models.CostAllocation{}
+	pools := map[string]*models.PoolCost{}
+	requestedCost := map[string]float64{}
+	pool := func(name string) *models.PoolCost {
+		if p, ok := pools[name]; ok {
+			return p
+		}
+		price, ok := opts.PoolPrices[name]
+		if !ok {
+			price = opts.DefaultPrice
+		}
+		p := &models.PoolCost{Pool: name, Price: price}
+		pools[name] = p
+		return p
+	}
+
+	samples, usageSamples := 0, 0
+	for _, record := range records {
+		samples += record.Samples
+		usageSamples += record.UsageSamples
+
+		for name, hours := range record.Capacity {
+			p := pool(name)
+			p.Capacity = p.Capacity.Add(hours)
+		}
+
+		for key, hours := range record.Groups {
+			poolName, dimension, name, ok := splitCostGroup(key)
+			if !ok {
+				continue
+			}
+			p := pool(poolName)
+			idleCost := priceHours(p.Price, hours.Idle)
+
+			// Every pod appears once in the namespace dimension, so pool totals are taken from it
+			if dimension == namespaceDimension {
+				requestedCost[poolName] += priceHours(p.Price, hours.Requests)
+				p.IdleCost += idleCost
+			}
+			if dimension != opts.AggregateBy {
+				continue
+			}
+
+			allocation, ok := allocations[name]
+			if !ok {
+				allocation = &models.CostAllocation{Name: name}
+				allocations[name] = allocation
+			}
+			allocation.Requests = allocation.Requests.Add(hours.Requests)
+			allocation.Usage = allocation.Usage.Add(hours.Usage)
+			allocation.Unmeasured = allocation.Unmeasured.Add(hours.Unmeasured)
+			allocation.IdleCost += idleCost
+
+			// Without a usage measurement, a pod is charged for what it requested
+			cost := priceHours(p.Price, hours.Usage.Add(hours.Unmeasured))
+			if opts.Basis == "requests" {
+				cost = priceHours(p.Price, hours.Requests)
+			}
+			allocation.Cost += cost
+			p.AllocatedCost += cost
+		}
+	}
+
+	for name, p := range pools {
+		p.TotalCost = roundCost(priceHours(p.Price, p.Capacity))
+		p.UnallocatedCost = roundCost(math.Max(priceHours(p.Price, p.Capacity)-requestedCost[name], 0))
+		p.AllocatedCost = roundCost(p.AllocatedCost)
+		p.IdleCost = roundCost(p.IdleCost)
+		p.Capacity = roundResourceHours(p.Capacity)
+
+		report.TotalCost += p.TotalCost
+		report.AllocatedCost += p.AllocatedCost
+		report.IdleCost += p.IdleCost
+		report.UnallocatedCost += p.UnallocatedCost
+		report.Pools = append(report.Pools, *p)
+	}
+	for _, allocation := range allocations {
+		allocation.Cost = roundCost(allocation.Cost)
+		allocation.IdleCost = roundCost(allocation.IdleCost)
+		allocation.Requests = roundResourceHours(allocation.Requests)
+		allocation.Usage = roundResourceHours(allocation.Usage)
+		allocation.Unmeasured = roundResourceHours(allocation.Unmeasured)
+		report.Allocations = append(report.Allocations, *allocation)
+	}
+
+	report.TotalCost = roundCost(report.TotalCost)
+	report.AllocatedCost = roundCost(report.AllocatedCost)
+	report.IdleCost = roundCost(report.IdleCost)
+	report.UnallocatedCost = roundCost(report.UnallocatedCost)
+	if samples > 0 {
+		report.UsageCoverage = math.Round(float64(usageSamples)/float64(samples)*1000) / 1000
+	}
+
+	sort.Slice(report.Allocations, func(i, j int) bool {
+		if report.Allocations[i].Cost != report.Allocations[j].Cost {
+			return report.Allocations[i].Cost > report.Allocations[j].Cost
+		}
+		return report.Allocations[i].Name < report.Allocations[j].Name
+	})
+	sort.Slice(report.Pools, func(i, j int) bool {
+		return report.Pools[i].Pool < report.Pools[j].Pool
+	})
+
+	return report
+}
+
+// CostReportCSV renders a cost report as CSV, one row per allocation followed by idle (when
+// charging by usage, since idle cost is otherwise part of the allocations) and unallocated rows
+func CostReportCSV(report *models.CostReport) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	format := func(v float64) string {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+
+	rows := [][]string{{
+		strings.TrimPrefix(report.AggregateBy, "label:"),
+		"cpu_core_hours_requested", "memory_gib_hours_requested",
+		"cpu_core_hours_used", "memory_gib_hours_used",
+		"cost", "idle_cost", "currency",
+	}}
+	for _, a := range report.Allocations {
+		rows = append(rows, []string{
+			a.Name,
+			format(a.Requests.CPUCoreHours), format(a.Requests.MemoryGiBHours),
+			format(a.Usage.CPUCoreHours), format(a.Usage.MemoryGiBHours),
+			format(a.Cost), format(a.IdleCost), report.Currency,
+		})
+	}
+	if report.Basis == "usage" {
+		rows = append(rows, []string{"(idle)", "", "", "", "", format(report.IdleCost), "", report.Currency})
+	}
+	rows = append(rows, []string{"(unallocated)", "", "", "", "", format(report.UnallocatedCost), "", report.Currency})
+
+	if err := w.WriteAll(rows); err != nil {
+		return nil, fmt.Errorf("failed to write CSV: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// buildCostUsage converts one sample of node capacity and pod requests and usage into
+// resource-hours for the sampling interval, grouped by node pool, namespace and cost labels
+func buildCostUsage(now time.Time, interval time.Duration, nodes []*v1.Node, pods []*v1.Pod, podUsage map[string]map[string]resourceUsage, poolLabel string, costLabels []string) models.CostUsage {
+	hours := interval.Hours()
+	usage := models.CostUsage{
+		Hour:     now.Truncate(time.Hour),
+		Samples:  1,
+		Capacity: map[string]models.ResourceHours{},
+		Groups:   map[string]models.AllocationHours{},
+	}
+	if podUsage != nil {
+		usage.UsageSamples = 1
+	}
+
+	nodePools := map[string]string{}
+	for _, node := range nodes {
+		pool := node.Labels[poolLabel]
+		if pool == "" {
+			pool = unlabeledPool
+		}
+		nodePools[node.Name] = pool
+		usage.Capacity[pool] = usage.Capacity[pool].Add(models.ResourceHours{
+			CPUCoreHours:   float64(node.Status.Capacity.Cpu().MilliValue()) / 1000 * hours,
+			MemoryGiBHours: float64(node.Status.Capacity.Memory().Value()) / bytesPerGiB * hours,
+		})
+	}
+
+	for _, pod := range pods {
+		pool, scheduled := nodePools[pod.Spec.NodeName]
+		if !scheduled || isPodTerminal(pod) {
+			continue
+		}
+
+		var containerUsage map[string]resourceUsage
+		if podUsage != nil {
+			containerUsage = podUsage[pod.Namespace+"/"+pod.Name]
+		}
+		metrics := getPodMetrics(pod, containerUsage)
+
+		allocation := models.AllocationHours{
+			Requests: models.ResourceHours{
+				CPUCoreHours:   metrics.CPURequest / 1000 * hours,
+				MemoryGiBHours: float64(metrics.MemoryRequest) / bytesPerGiB * hours,
+			},
+		}
+		if containerUsage != nil {
+			allocation.Usage = models.ResourceHours{
+				CPUCoreHours:   metrics.CPUUsage / 1000 * hours,
+				MemoryGiBHours: float64(metrics.MemoryUsage) / bytesPerGiB * hours,
+			}
+			allocation.Idle = models.ResourceHours{
+				CPUCoreHours:   math.Max(metrics.CPURequest-metrics.CPUUsage, 0) / 1000 * hours,
+				MemoryGiBHours: math.Max(float64(metrics.MemoryRequest-metrics.MemoryUsage), 0) / bytesPerGiB * hours,
+			}
+		} else {
+			allocation.Unmeasured = allocation.Requests
+		}
+
+		addAllocation(usage.Groups, costGroupKey(pool, namespaceDimension, pod.Namespace), allocation)
+		for _, label := range costLabels {
+			value := pod.Labels[label]
+			if value == "" {
+				value = unlabeledCostGroup
+			}
+			addAllocation(usage.Groups, costGroupKey(pool, "label:"+label, value), allocation)
+		}
+	}
+
+	return usage
+}
+
+func addAllocation(groups map[string]models.AllocationHours, key string, allocation models.AllocationHours) {
+	groups[key] = groups[key].Add(allocation)
+}
+
+// Label values and namespace names cannot contain "|"
+func costGroupKey(pool, dimension, name string) string {
+	return pool + "|" + dimension + "|" + name
+}
+
+func splitCostGroup(key string) (pool, dimension, name string, ok bool) {
+	parts := strings.SplitN(key, "|", 3)
+	if len(parts) != 3 {
+		return "", "", "", false
+	}
+	return parts[0], parts[1], parts[2], true
+}
+
+func priceHours(price models.ResourcePrice, hours models.ResourceHours) float64 {
+	return hours.CPUCoreHours*price.CPUHour + hours.MemoryGiBHours*price.MemoryGiBHour
+}
+
+func roundResourceHours(hours models.ResourceHours) models.ResourceHours {
+	return models.ResourceHours{
+		CPUCoreHours:   math.Round(hours.CPUCoreHours*1000) / 1000,
+		MemoryGiBHours: math.Round(hours.MemoryGiBHours*1000) / 1000,
+	}
+}
+
+// roundCost rounds to 1/10000 of the currency unit so small allocations stay visible
+func roundCost(cost float64) float64 {
+	return math.Round(cost*10000) / 10000
+}
@@ -0,0 +1,109 @@
+package kubernetes
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"kubey/api/internal/models"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCostAllocation(t *testing.T) {
+	poolLabel := "pool"
+	nodes := []*v1.Node{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "general-1", Labels: map[string]string{poolLabel: "general"}},
+			Status:     v1.NodeStatus{Capacity: v1.ResourceList{v1.ResourceCPU: resource.MustParse("4"), v1.ResourceMemory: resource.MustParse("16Gi")}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "gpu-1", Labels: map[string]string{poolLabel: "gpu"}},
+			Status:     v1.NodeStatus{Capacity: v1.ResourceList{v1.ResourceCPU: resource.MustParse("2"), v1.ResourceMemory: resource.MustParse("8Gi")}},
+		},
+	}
+	pod := func(namespace, name, node, team, cpu, memory string) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: map[string]string{"team": team}},
+			Spec: v1.PodSpec{NodeName: node, Containers: []v1.Container{{
+				Name: "app",
+				Resources: v1.ResourceRequirements{Requests: v1.ResourceList{
+					v1.ResourceCPU:    resource.MustParse(cpu),
+					v1.ResourceMemory: resource.MustParse(memory),
+				}},
+			}}},
+			Status: v1.PodStatus{Phase: v1.PodRunning},
+		}
+	}
+	pods := []*v1.Pod{
+		pod("shop", "web", "general-1", "storefront", "2", "4Gi"),
+		pod("ml", "train", "gpu-1", "", "1", "4Gi"),
+	}
+	podUsage := map[string]map[string]resourceUsage{
+		"shop/web": {"app": {CPU: 500, Memory: 4 << 30}},
+		"ml/train": {"app": {CPU: 1000, Memory: 2 << 30}},
+	}
+
+	// Two half-hour samples make one hour of resource-hours
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	first := buildCostUsage(start, 30*time.Minute, nodes, pods, podUsage, poolLabel, []string{"team"})
+	second := buildCostUsage(start.Add(30*time.Minute), 30*time.Minute, nodes, pods, nil, poolLabel, []string{"team"})
+
+	if got := first.Groups["general|namespace|shop"].Requests.CPUCoreHours; got != 1 {
+		t.Fatalf("shop CPU request hours = %v, want 1", got)
+	}
+	if got := first.Groups["gpu|label:team|(none)"].Requests.MemoryGiBHours; got != 2 {
+		t.Fatalf("unlabeled memory hours = %v, want 2", got)
+	}
+
+	opts := CostOptions{
+		Basis:        "requests",
+		AggregateBy:  "namespace",
+		DefaultPrice: models.ResourcePrice{CPUHour: 1, MemoryGiBHour: 0.1},
+		PoolPrices:   map[string]models.ResourcePrice{"gpu": {CPUHour: 10, MemoryGiBHour: 1}},
+	}
+	records := []models.CostUsage{first, second}
+	report := buildCostReport("context-test", opts, records)
+
+	// general: 4 cores + 16 GiB at 1 / 0.1 = 5.6; gpu: 2 cores + 8 GiB at 10 / 1 = 28
+	if report.TotalCost != 33.6 {
+		t.Errorf("TotalCost = %v, want 33.6", report.TotalCost)
+	}
+	// ml requests 1 core + 4 GiB on gpu = 14; shop requests 2 cores + 4 GiB on general = 2.4
+	if len(report.Allocations) != 2 || report.Allocations[0].Name != "ml" || report.Allocations[0].Cost != 14 || report.Allocations[1].Cost != 2.4 {
+		t.Fatalf("unexpected allocations: %+v", report.Allocations)
+	}
+	if report.UnallocatedCost != 33.6-14-2.4 {
+		t.Errorf("UnallocatedCost = %v", report.UnallocatedCost)
+	}
+	// Only the first half hour had usage: shop idles 1.5 cores for 0.5h = 0.75, ml idles 2 GiB for 0.5h = 1
+	if report.IdleCost != 1.75 || report.UsageCoverage != 0.5 {
+		t.Errorf("IdleCost = %v, UsageCoverage = %v", report.IdleCost, report.UsageCoverage)
+	}
+
+	opts.AggregateBy = "label:team"
+	opts.Basis = "usage"
+	report = buildCostReport("context-test", opts, records)
+	if len(report.Allocations) != 2 || report.Allocations[0].Name != "(none)" {
+		t.Fatalf("unexpected label allocations: %+v", report.Allocations)
+	}
+	// The second half hour had no usage, so it is charged at the requests and no cost goes missing
+	if report.Allocations[0].Unmeasured.CPUCoreHours != 0.5 {
+		t.Errorf("unmeasured CPU hours = %v, want 0.5", report.Allocations[0].Unmeasured.CPUCoreHours)
+	}
+	if sum := report.AllocatedCost + report.IdleCost + report.UnallocatedCost; math.Abs(sum-report.TotalCost) > 0.001 {
+		t.Errorf("allocated + idle + unallocated = %v, want total %v", sum, report.TotalCost)
+	}
+
+	csv, err := CostReportCSV(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(csv)), "\n")
+	if len(lines) != 5 || !strings.HasPrefix(lines[0], "team,") || !strings.HasPrefix(lines[3], "(idle),") {
+		t.Errorf("unexpected CSV:\n%s", csv)
+	}
+}
@@ -0,0 +1,297 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"kubey/api/internal/models"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+)
+
+// debugCleanupTimeout bounds the delete of a node debug pod that failed to start
+const debugCleanupTimeout = 10 * time.Second
+
+// DebugPod adds an ephemeral debug container to a pod on behalf of caller and waits for it to start
+func DebugPod(clusterID, namespace, podName, caller string, req models.DebugRequest, timeout time.Duration) (*models.DebugSession, error) {
+	cs, err := getClientsetForCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := checkAccess(ctx, cs, caller, authorizationv1.ResourceAttributes{
+		Namespace:   namespace,
+		Verb:        "patch",
+		Resource:    "pods",
+		Subresource: "ephemeralcontainers",
+		Name:        podName,
+	}); err != nil {
+		return nil, err
+	}
+
+	pod, err := cs.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("%w: pod %s/%s", ErrResourceNotFound, namespace, podName)
+		}
+		return nil, fmt.Errorf("failed to get pod: %v", err)
+	}
+
+	if req.TargetContainer != "" && !hasContainer(pod, req.TargetContainer) {
+		return nil, fmt.Errorf("%w: container %s in pod %s/%s", ErrResourceNotFound, req.TargetContainer, namespace, podName)
+	}
+
+	containerName := "debugger-" + utilrand.String(5)
+	pod.Spec.EphemeralContainers = append(pod.Spec.EphemeralContainers, v1.EphemeralContainer{
+		EphemeralContainerCommon: v1.EphemeralContainerCommon{
+			Name:                     containerName,
+			Image:                    req.Image,
+			Command:                  req.Command,
+			Stdin:                    true,
+			TTY:                      true,
+			ImagePullPolicy:          v1.PullIfNotPresent,
+			TerminationMessagePolicy: v1.TerminationMessageReadFile,
+		},
+		TargetContainerName: req.TargetContainer,
+	})
+
+	if _, err := cs.CoreV1().Pods(namespace).UpdateEphemeralContainers(ctx, podName, pod, metav1.UpdateOptions{}); err != nil {
+		return nil, fmt.Errorf("failed to add ephemeral container: %v", err)
+	}
+
+	log.Printf("Added ephemeral container %s to pod %s/%s, waiting for it to start", containerName, namespace, podName)
+
+	status, err := waitForDebugContainer(ctx, cs, namespace, podName, func(p *v1.Pod) *v1.ContainerStatus {
+		for i := range p.Status.EphemeralContainerStatuses {
+			if p.Status.EphemeralContainerStatuses[i].Name == containerName {
+				return &p.Status.EphemeralContainerStatuses[i]
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.DebugSession{
+		ClusterID: clusterID,
+		Namespace: namespace,
+		Pod:       podName,
+		Container: containerName,
+		Image:     req.Image,
+		Target:    req.TargetContainer,
+		Kind:      "pod",
+		Status:    status,
+		StartedAt: time.Now(),
+	}, nil
+}
+
+// DebugNode schedules a privileged pod on the given node with the host filesystem mounted at /host,
+// on behalf of caller
+func DebugNode(clusterID, nodeName, caller string, req models.DebugRequest, timeout time.Duration) (*models.DebugSession, error) {
+	cs, err := getClientsetForCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := checkAccess(ctx, cs, caller, authorizationv1.ResourceAttributes{
+		Namespace: req.Namespace,
+		Verb:      "create",
+		Resource:  "pods",
+	}); err != nil {
+		return nil, err
+	}
+
+	if _, err := cs.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{}); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("%w: node %s", ErrResourceNotFound, nodeName)
+		}
+		return nil, fmt.Errorf("failed to get node: %v", err)
+	}
+
+	const containerName = "debugger"
+	privileged := true
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "node-debugger-" + nodeName + "-",
+			Namespace:    req.Namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/managed-by": "kubey",
+				"kubey.io/debug-node":          nodeName,
+			},
+		},
+		Spec: v1.PodSpec{
+			NodeName:      nodeName,
+			HostPID:       true,
+			HostNetwork:   true,
+			HostIPC:       true,
+			RestartPolicy: v1.RestartPolicyNever,
+			Tolerations:   []v1.Toleration{{Operator: v1.TolerationOpExists}},
+			Containers: []v1.Container{{
+				Name:                     containerName,
+				Image:                    req.Image,
+				Command:                  req.Command,
+				Stdin:                    true,
+				TTY:                      true,
+				ImagePullPolicy:          v1.PullIfNotPresent,
+				TerminationMessagePolicy: v1.TerminationMessageReadFile,
+				SecurityContext:          &v1.SecurityContext{Privileged: &privileged},
+				VolumeMounts:             []v1.VolumeMount{{Name: "host-root", MountPath: "/host"}},
+			}},
+			Volumes: []v1.Volume{{
+				Name:         "host-root",
+				VolumeSource: v1.VolumeSource{HostPath: &v1.HostPathVolumeSource{Path: "/"}},
+			}},
+		},
+	}
+
+	created, err := cs.CoreV1().Pods(req.Namespace).Create(ctx, pod, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create node debug pod: %v", err)
+	}
+
+	log.Printf("Created node debug pod %s/%s on node %s, waiting for it to start", created.Namespace, created.Name, nodeName)
+
+	status, err := waitForDebugContainer(ctx, cs, created.Namespace, created.Name, func(p *v1.Pod) *v1.ContainerStatus {
+		for i := range p.Status.ContainerStatuses {
+			if p.Status.ContainerStatuses[i].Name == containerName {
+				return &p.Status.ContainerStatuses[i]
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		deleteDebugPod(cs, created)
+		return nil, err
+	}
+
+	return &models.DebugSession{
+		ClusterID: clusterID,
+		Namespace: created.Namespace,
+		Pod:       created.Name,
+		Container: containerName,
+		Image:     req.Image,
+		Target:    nodeName,
+		Kind:      "node",
+		Status:    status,
+		StartedAt: time.Now(),
+	}, nil
+}
+
+// CheckDebugImage returns ErrForbidden unless image is in the allowed debug images
+func CheckDebugImage(image string, allowed []string) error {
+	if !slices.Contains(allowed, image) {
+		return fmt.Errorf("%w: image %s is not in the allowed debug images", ErrForbidden, image)
+	}
+	return nil
+}
+
+// deleteDebugPod removes a node debug pod that failed to start, so no privileged pod is left behind.
+// It uses its own context because the caller's has usually expired by then.
+func deleteDebugPod(cs kubernetes.Interface, pod *v1.Pod) {
+	ctx, cancel := context.WithTimeout(context.Background(), debugCleanupTimeout)
+	defer cancel()
+
+	if err := cs.CoreV1().Pods(pod.Namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		log.Printf("Failed to delete node debug pod %s/%s: %v", pod.Namespace, pod.Name, err)
+		return
+	}
+	log.Printf("Deleted node debug pod %s/%s after it failed to start", pod.Namespace, pod.Name)
+}
+
+// checkAccess verifies via a SubjectAccessReview that caller may perform the given action.
+// kubey acts with its own credentials, so without a caller identity nothing is allowed.
+func checkAccess(ctx context.Context, cs kubernetes.Interface, caller string, attrs authorizationv1.ResourceAttributes) error {
+	if caller == "" {
+		return fmt.Errorf("%w: debug sessions require an authenticated caller", ErrForbidden)
+	}
+
+	review := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{User: caller, ResourceAttributes: &attrs},
+	}
+
+	result, err := cs.AuthorizationV1().SubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to check permissions: %v", err)
+	}
+
+	if !result.Status.Allowed {
+		resource := attrs.Resource
+		if attrs.Subresource != "" {
+			resource += "/" + attrs.Subresource
+		}
+		return fmt.Errorf("%w: %s cannot %s %s in namespace %s", ErrForbidden, caller, attrs.Verb, resource, attrs.Namespace)
+	}
+
+	return nil
+}
+
+// waitForDebugContainer polls the pod until the selected container is running or has failed to start
+func waitForDebugContainer(ctx context.Context, cs kubernetes.Interface, namespace, podName string, find func(*v1.Pod) *v1.ContainerStatus) (models.ResourceStatus, error) {
+	var status models.ResourceStatus
+
+	err := wait.PollUntilContextCancel(ctx, time.Second, true, func(ctx context.Context) (bool, error) {
+		pod, err := cs.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+
+		containerStatus := find(pod)
+		if containerStatus == nil {
+			return false, nil
+		}
+
+		switch {
+		case containerStatus.State.Running != nil:
+			status = models.ResourceStatus{
+				Phase:       "Running",
+				Ready:       true,
+				LastUpdated: time.Now(),
+			}
+			return true, nil
+		case containerStatus.State.Terminated != nil:
+			return false, fmt.Errorf("debug container terminated: %s", containerStatus.State.Terminated.Reason)
+		case containerStatus.State.Waiting != nil && isImagePullFailure(containerStatus.State.Waiting.Reason):
+			return false, fmt.Errorf("debug container failed to start: %s", containerStatus.State.Waiting.Reason)
+		}
+
+		return false, nil
+	})
+	if err != nil {
+		return status, fmt.Errorf("failed waiting for debug container: %v", err)
+	}
+
+	return status, nil
+}
+
+func hasContainer(pod *v1.Pod, name string) bool {
+	for _, container := range pod.Spec.Containers {
+		if container.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func isImagePullFailure(reason string) bool {
+	switch reason {
+	case "ErrImagePull", "ImagePullBackOff", "InvalidImageName", "ErrImageNeverPull":
+		return true
+	}
+	return false
+}
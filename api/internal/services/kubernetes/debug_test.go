@@ -0,0 +1,67 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestCheckDebugImage(t *testing.T) {
+	allowed := []string{"busybox:1.36", "nicolaka/netshoot:v0.13"}
+
+	if err := CheckDebugImage("nicolaka/netshoot:v0.13", allowed); err != nil {
+		t.Fatalf("expected an allowed image to pass, got %v", err)
+	}
+	for _, image := range []string{"busybox", "busybox:latest", "evil.io/busybox:1.36", ""} {
+		if err := CheckDebugImage(image, allowed); !errors.Is(err, ErrForbidden) {
+			t.Fatalf("expected %q to be forbidden, got %v", image, err)
+		}
+	}
+	if err := CheckDebugImage("busybox:1.36", nil); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("expected an empty allowlist to allow nothing, got %v", err)
+	}
+}
+
+func TestCheckAccess(t *testing.T) {
+	cs := fake.NewSimpleClientset()
+	var reviewed authorizationv1.ResourceAttributes
+	var user string
+	cs.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		reviewed, user = *review.Spec.ResourceAttributes, review.Spec.User
+		review.Status.Allowed = reviewed.Namespace == "debug"
+		return true, review, nil
+	})
+
+	attrs := authorizationv1.ResourceAttributes{Namespace: "debug", Verb: "patch", Resource: "pods", Subresource: "ephemeralcontainers", Name: "web-1"}
+	if err := checkAccess(context.Background(), cs, "alice", attrs); err != nil {
+		t.Fatalf("expected access to be allowed, got %v", err)
+	}
+	if reviewed != attrs || user != "alice" {
+		t.Fatalf("expected the review to carry the caller and requested attributes, got %q %+v", user, reviewed)
+	}
+	if err := checkAccess(context.Background(), cs, "", attrs); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("expected a request without a caller to be forbidden, got %v", err)
+	}
+
+	attrs.Namespace = "kube-system"
+	err := checkAccess(context.Background(), cs, "alice", attrs)
+	if !errors.Is(err, ErrForbidden) {
+		t.Fatalf("expected a denied review to be forbidden, got %v", err)
+	}
+	if err.Error() != "forbidden: alice cannot patch pods/ephemeralcontainers in namespace kube-system" {
+		t.Fatalf("unexpected error message: %v", err)
+	}
+
+	cs.PrependReactor("create", "subjectaccessreviews", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("connection refused")
+	})
+	if err := checkAccess(context.Background(), cs, "alice", attrs); err == nil || errors.Is(err, ErrForbidden) {
+		t.Fatalf("expected a failed review to be reported as an error, got %v", err)
+	}
+}
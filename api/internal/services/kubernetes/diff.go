@@ -0,0 +1,130 @@
+package kubernetes
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"kubey/api/internal/models"
+)
+
+// maxDiffValueLength truncates long values (e.g. ConfigMap data) in field diffs
+const maxDiffValueLength = 512
+
+// diffFields compares two unstructured objects and returns the changed leaf fields, sorted by path.
+// Lists of objects that carry a "name" key (containers, env, ports, volumes) are keyed by name
+// so that reordering or inserting an element does not show up as a change to every later element.
+func diffFields(prefix string, oldObj, newObj interface{}) []models.FieldChange {
+	oldFields := map[string]string{}
+	newFields := map[string]string{}
+	flattenFields(prefix, oldObj, oldFields)
+	flattenFields(prefix, newObj, newFields)
+
+	var changes []models.FieldChange
+	for path, newValue := range newFields {
+		if oldValue, ok := oldFields[path]; !ok || oldValue != newValue {
+			changes = append(changes, models.FieldChange{
+				Path: path,
+				Old:  truncateValue(oldFields[path]),
+				New:  truncateValue(newValue),
+			})
+		}
+	}
+	for path, oldValue := range oldFields {
+		if _, ok := newFields[path]; !ok {
+			changes = append(changes, models.FieldChange{
+				Path: path,
+				Old:  truncateValue(oldValue),
+			})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes
+}
+
+// flattenFields walks an unstructured value and records every leaf as path -> JSON value
+func flattenFields(path string, value interface{}, fields map[string]string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 {
+			fields[path] = "{}"
+			return
+		}
+		for key, child := range v {
+			flattenFields(joinPath(path, key), child, fields)
+		}
+	case []interface{}:
+		if len(v) == 0 {
+			fields[path] = "[]"
+			return
+		}
+		for i, child := range v {
+			key := fmt.Sprintf("%d", i)
+			if m, ok := child.(map[string]interface{}); ok {
+				if name, ok := m["name"].(string); ok && name != "" {
+					key = name
+				}
+			}
+			flattenFields(fmt.Sprintf("%s[%s]", path, key), child, fields)
+		}
+	case nil:
+		// Absent and null fields are treated the same
+	case string:
+		fields[path] = v
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			encoded = []byte(fmt.Sprintf("%v", v))
+		}
+		fields[path] = string(encoded)
+	}
+}
+
+// categorizeChanges classifies field changes into the categories shown in the change feed
+func categorizeChanges(kind string, fields []models.FieldChange) []string {
+	seen := map[string]bool{}
+	categories := []string{}
+	add := func(category string) {
+		if !seen[category] {
+			seen[category] = true
+			categories = append(categories, category)
+		}
+	}
+
+	for _, field := range fields {
+		switch {
+		case kind == "ConfigMap":
+			add("config")
+		case strings.HasSuffix(field.Path, ".image"):
+			add("image")
+		case field.Path == "spec.replicas":
+			add("replicas")
+		case strings.Contains(field.Path, ".env[") || strings.Contains(field.Path, ".envFrom["):
+			add("env")
+		case strings.Contains(field.Path, ".configMap.") || strings.Contains(field.Path, ".secret."):
+			add("config")
+		default:
+			add("spec")
+		}
+	}
+
+	return categories
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func truncateValue(value string) string {
+	if len(value) <= maxDiffValueLength {
+		return value
+	}
+	return value[:maxDiffValueLength] + "…"
+}
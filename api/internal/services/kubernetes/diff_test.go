@@ -0,0 +1,69 @@
+package kubernetes
+
+import (
+	"reflect"
+	"testing"
+
+	"kubey/api/internal/models"
+)
+
+func TestDiffFieldsKeysListsByName(t *testing.T) {
+	oldSpec := map[string]interface{}{
+		"replicas": int64(2),
+		"template": map[string]interface{}{
+			"spec": map[string]interface{}{
+				"containers": []interface{}{
+					map[string]interface{}{
+						"name":  "app",
+						"image": "app:1.0",
+						"env": []interface{}{
+							map[string]interface{}{"name": "LOG_LEVEL", "value": "info"},
+						},
+					},
+				},
+			},
+		},
+	}
+	newSpec := map[string]interface{}{
+		"replicas": int64(3),
+		"template": map[string]interface{}{
+			"spec": map[string]interface{}{
+				"containers": []interface{}{
+					map[string]interface{}{"name": "sidecar", "image": "proxy:2.0"},
+					map[string]interface{}{
+						"name":  "app",
+						"image": "app:1.1",
+						"env": []interface{}{
+							map[string]interface{}{"name": "LOG_LEVEL", "value": "debug"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	got := diffFields("spec", oldSpec, newSpec)
+	want := []models.FieldChange{
+		{Path: "spec.replicas", Old: "2", New: "3"},
+		{Path: "spec.template.spec.containers[app].env[LOG_LEVEL].value", Old: "info", New: "debug"},
+		{Path: "spec.template.spec.containers[app].image", Old: "app:1.0", New: "app:1.1"},
+		{Path: "spec.template.spec.containers[sidecar].image", New: "proxy:2.0"},
+		{Path: "spec.template.spec.containers[sidecar].name", New: "sidecar"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected diff:\n got: %+v\nwant: %+v", got, want)
+	}
+
+	categories := categorizeChanges("Deployment", got)
+	wantCategories := []string{"replicas", "env", "image", "spec"}
+	if !reflect.DeepEqual(categories, wantCategories) {
+		t.Fatalf("expected categories %v, got %v", wantCategories, categories)
+	}
+}
+
+func TestDiffFieldsNoChange(t *testing.T) {
+	data := map[string]interface{}{"key": "value"}
+	if got := diffFields("data", data, map[string]interface{}{"key": "value"}); len(got) != 0 {
+		t.Fatalf("expected no changes, got %+v", got)
+	}
+}
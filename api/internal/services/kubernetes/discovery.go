@@ -0,0 +1,124 @@
+package kubernetes
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"kubey/api/internal/models"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery"
+)
+
+// GetAPIResources returns the groups, versions and resources a cluster serves.
+// Discovery results are cached for discoveryCacheTTL. Group versions that fail discovery,
+// such as an unavailable aggregated API, are listed under Failed.
+func GetAPIResources(clusterID string) (*models.APIResources, error) {
+	dc, err := getDiscoveryForCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	groups, lists, err := dc.ServerGroupsAndResources()
+	failed := []string{}
+	if err != nil {
+		var groupErr *discovery.ErrGroupDiscoveryFailed
+		if !errors.As(err, &groupErr) {
+			return nil, fmt.Errorf("failed to discover API resources: %v", err)
+		}
+		for gv := range groupErr.Groups {
+			failed = append(failed, gv.String())
+		}
+		sort.Strings(failed)
+	}
+
+	resources := &models.APIResources{
+		Groups: buildAPIGroups(groups, lists),
+		Failed: failed,
+	}
+	if version, err := dc.ServerVersion(); err == nil {
+		resources.ServerVersion = version.GitVersion
+	} else {
+		log.Printf("Server version unavailable for %s: %v", clusterID, err)
+	}
+	return resources, nil
+}
+
+// buildAPIGroups combines discovered groups with their resource lists. Subresources are listed
+// on their parent resource instead of as resources of their own.
+func buildAPIGroups(groups []*metav1.APIGroup, lists []*metav1.APIResourceList) []models.APIGroup {
+	byGroupVersion := make(map[string]*metav1.APIResourceList, len(lists))
+	for _, list := range lists {
+		byGroupVersion[list.GroupVersion] = list
+	}
+
+	apiGroups := []models.APIGroup{}
+	for _, group := range groups {
+		apiGroup := models.APIGroup{
+			Name:             group.Name,
+			PreferredVersion: group.PreferredVersion.Version,
+			Versions:         []models.APIGroupVersion{},
+		}
+		for _, version := range group.Versions {
+			list, ok := byGroupVersion[version.GroupVersion]
+			if !ok {
+				continue
+			}
+			apiGroup.Versions = append(apiGroup.Versions, models.APIGroupVersion{
+				Version:   version.Version,
+				Resources: toAPIResources(list.APIResources),
+			})
+		}
+		apiGroups = append(apiGroups, apiGroup)
+	}
+
+	sort.Slice(apiGroups, func(i, j int) bool {
+		return apiGroups[i].Name < apiGroups[j].Name
+	})
+	return apiGroups
+}
+
+func toAPIResources(discovered []metav1.APIResource) []models.APIResource {
+	resources := []models.APIResource{}
+	index := map[string]int{}
+	for _, r := range discovered {
+		if strings.Contains(r.Name, "/") {
+			continue
+		}
+		index[r.Name] = len(resources)
+		resources = append(resources, models.APIResource{
+			Name:         r.Name,
+			Kind:         r.Kind,
+			Namespaced:   r.Namespaced,
+			Verbs:        nonNilStrings(r.Verbs),
+			ShortNames:   nonNilStrings(r.ShortNames),
+			Categories:   nonNilStrings(r.Categories),
+			Subresources: []string{},
+		})
+	}
+
+	for _, r := range discovered {
+		parent, subresource, ok := strings.Cut(r.Name, "/")
+		if !ok {
+			continue
+		}
+		if i, found := index[parent]; found {
+			resources[i].Subresources = append(resources[i].Subresources, subresource)
+		}
+	}
+
+	sort.Slice(resources, func(i, j int) bool {
+		return resources[i].Name < resources[j].Name
+	})
+	return resources
+}
+
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
@@ -0,0 +1,51 @@
+package kubernetes
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestBuildAPIGroups(t *testing.T) {
+	groups := []*metav1.APIGroup{
+		{
+			Name: "apps",
+			Versions: []metav1.GroupVersionForDiscovery{
+				{GroupVersion: "apps/v1", Version: "v1"},
+				{GroupVersion: "apps/v1beta1", Version: "v1beta1"},
+			},
+			PreferredVersion: metav1.GroupVersionForDiscovery{GroupVersion: "apps/v1", Version: "v1"},
+		},
+		{
+			Versions:         []metav1.GroupVersionForDiscovery{{GroupVersion: "v1", Version: "v1"}},
+			PreferredVersion: metav1.GroupVersionForDiscovery{GroupVersion: "v1", Version: "v1"},
+		},
+	}
+	lists := []*metav1.APIResourceList{
+		{GroupVersion: "apps/v1", APIResources: []metav1.APIResource{
+			{Name: "deployments/scale", Kind: "Scale", Namespaced: true},
+			{Name: "deployments", Kind: "Deployment", Namespaced: true, Verbs: []string{"get", "list"}, ShortNames: []string{"deploy"}},
+			{Name: "deployments/status", Kind: "Deployment", Namespaced: true},
+		}},
+		{GroupVersion: "v1", APIResources: []metav1.APIResource{
+			{Name: "nodes", Kind: "Node", Verbs: []string{"get", "list"}},
+		}},
+	}
+
+	apiGroups := buildAPIGroups(groups, lists)
+	if len(apiGroups) != 2 || apiGroups[0].Name != "" || apiGroups[1].Name != "apps" {
+		t.Fatalf("expected the core group first, got %+v", apiGroups)
+	}
+
+	apps := apiGroups[1]
+	if apps.PreferredVersion != "v1" || len(apps.Versions) != 1 {
+		t.Fatalf("expected only the discovered version of apps, got %+v", apps)
+	}
+	resources := apps.Versions[0].Resources
+	if len(resources) != 1 || resources[0].ShortNames[0] != "deploy" || len(resources[0].Subresources) != 2 {
+		t.Fatalf("expected subresources on their parent, got %+v", resources)
+	}
+	if nodes := apiGroups[0].Versions[0].Resources[0]; nodes.Namespaced || nodes.ShortNames == nil || nodes.Subresources == nil {
+		t.Fatalf("unexpected core resource: %+v", nodes)
+	}
+}
@@ -0,0 +1,175 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"kubey/api/internal/models"
+
+	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+// serviceBackends indexes the EndpointSlices and pods of a namespace, or of all namespaces,
+// to resolve services to the pods behind them
+type serviceBackends struct {
+	slices     map[string][]*discoveryv1.EndpointSlice // By "namespace/service"
+	pods       map[string]*v1.Pod                      // By "namespace/name"
+	namespaces map[string][]*v1.Pod                    // By namespace
+}
+
+// getServiceBackends lists the EndpointSlices and pods of a namespace, or all namespaces when empty
+func getServiceBackends(ctx context.Context, cs kubernetes.Interface, namespace string) (*serviceBackends, error) {
+	slices, err := cs.DiscoveryV1().EndpointSlices(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list endpoint slices: %v", err)
+	}
+	pods, err := cs.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %v", err)
+	}
+	return newServiceBackends(slices.Items, pods.Items), nil
+}
+
+func newServiceBackends(slices []discoveryv1.EndpointSlice, pods []v1.Pod) *serviceBackends {
+	backends := &serviceBackends{
+		slices:     map[string][]*discoveryv1.EndpointSlice{},
+		pods:       make(map[string]*v1.Pod, len(pods)),
+		namespaces: map[string][]*v1.Pod{},
+	}
+	for i := range slices {
+		slice := &slices[i]
+		if serviceName := slice.Labels[discoveryv1.LabelServiceName]; serviceName != "" {
+			key := slice.Namespace + "/" + serviceName
+			backends.slices[key] = append(backends.slices[key], slice)
+		}
+	}
+	for i := range pods {
+		pod := &pods[i]
+		backends.pods[pod.Namespace+"/"+pod.Name] = pod
+		backends.namespaces[pod.Namespace] = append(backends.namespaces[pod.Namespace], pod)
+	}
+	return backends
+}
+
+// applyServiceBackends fills a service's endpoints from its EndpointSlices, and its pods from
+// the endpoints that target a pod, then derives its status from them. Nil backends leave the
+// service unchanged.
+func applyServiceBackends(kubeService *models.KubeService, backends *serviceBackends) {
+	if backends == nil {
+		return
+	}
+
+	seen := map[string]bool{}
+	for _, slice := range backends.slices[kubeService.Namespace+"/"+kubeService.Name] {
+		for _, endpoint := range slice.Endpoints {
+			// Unset conditions mean ready, serving when ready, and not terminating
+			ready := endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready
+			serving := ready
+			if endpoint.Conditions.Serving != nil {
+				serving = *endpoint.Conditions.Serving
+			}
+			terminating := endpoint.Conditions.Terminating != nil && *endpoint.Conditions.Terminating
+
+			podName := ""
+			if ref := endpoint.TargetRef; ref != nil && ref.Kind == "Pod" {
+				podName = ref.Name
+			}
+			for _, address := range endpoint.Addresses {
+				serviceEndpoint := models.ServiceEndpoint{
+					Address:     address,
+					Pod:         podName,
+					Ready:       ready,
+					Serving:     serving,
+					Terminating: terminating,
+				}
+				if endpoint.NodeName != nil {
+					serviceEndpoint.NodeName = *endpoint.NodeName
+				}
+				if endpoint.Zone != nil {
+					serviceEndpoint.Zone = *endpoint.Zone
+				}
+				kubeService.Endpoints = append(kubeService.Endpoints, serviceEndpoint)
+			}
+
+			// Dual-stack services list each pod once per address family
+			if podName == "" || seen[podName] {
+				continue
+			}
+			seen[podName] = true
+			if pod, ok := backends.pods[kubeService.Namespace+"/"+podName]; ok {
+				kubeService.Pods = append(kubeService.Pods, toKubePod(pod, nil))
+			}
+		}
+	}
+
+	sort.Slice(kubeService.Endpoints, func(i, j int) bool {
+		a, b := kubeService.Endpoints[i], kubeService.Endpoints[j]
+		if a.Pod != b.Pod {
+			return a.Pod < b.Pod
+		}
+		return a.Address < b.Address
+	})
+	sort.Slice(kubeService.Pods, func(i, j int) bool {
+		return kubeService.Pods[i].Name < kubeService.Pods[j].Name
+	})
+
+	// A pending load balancer is reported first; external names have no endpoints
+	if kubeService.Status.Ready && kubeService.Type != string(v1.ServiceTypeExternalName) {
+		kubeService.Status = getServiceEndpointStatus(kubeService, backends)
+	}
+}
+
+// getServiceEndpointStatus flags services whose selector matches no pods, that have no endpoints,
+// or whose endpoints are not all ready. Terminating endpoints are not counted.
+func getServiceEndpointStatus(kubeService *models.KubeService, backends *serviceBackends) models.ResourceStatus {
+	status := models.ResourceStatus{Phase: "Active", Ready: true, LastUpdated: time.Now()}
+
+	ready, total := 0, 0
+	for _, endpoint := range kubeService.Endpoints {
+		if endpoint.Terminating {
+			continue
+		}
+		total++
+		if endpoint.Ready {
+			ready++
+		}
+	}
+
+	switch {
+	case len(kubeService.Selector) > 0 && !selectorMatchesPods(kubeService.Selector, backends.namespaces[kubeService.Namespace]):
+		status.Phase = "Unavailable"
+		status.Ready = false
+		status.Reason = "SelectorMatchesNoPods"
+		status.Message = fmt.Sprintf("no pods match selector %s", labels.SelectorFromSet(kubeService.Selector))
+	case total == 0:
+		status.Phase = "Unavailable"
+		status.Ready = false
+		status.Reason = "NoEndpoints"
+		status.Message = "the service has no endpoints"
+	case ready == 0:
+		status.Phase = "Unavailable"
+		status.Ready = false
+		status.Reason = "NoReadyEndpoints"
+		status.Message = fmt.Sprintf("none of the %d endpoints are ready", total)
+	case ready < total:
+		status.Reason = "EndpointsNotReady"
+		status.Message = fmt.Sprintf("%d of %d endpoints are ready", ready, total)
+	}
+	return status
+}
+
+func selectorMatchesPods(selector map[string]string, pods []*v1.Pod) bool {
+	set := labels.SelectorFromSet(selector)
+	for _, pod := range pods {
+		if set.Matches(labels.Set(pod.Labels)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+package kubernetes
+
+import (
+	"fmt"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestApplyServiceBackends(t *testing.T) {
+	yes, no := true, false
+	node := "node-a"
+	podRef := func(name string) *v1.ObjectReference {
+		return &v1.ObjectReference{Kind: "Pod", Namespace: "shop", Name: name}
+	}
+	slices := []discoveryv1.EndpointSlice{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-ipv4", Namespace: "shop", Labels: map[string]string{discoveryv1.LabelServiceName: "web"}},
+			Endpoints: []discoveryv1.Endpoint{
+				{Addresses: []string{"10.0.0.2"}, TargetRef: podRef("web-b"), NodeName: &node},
+				{Addresses: []string{"10.0.0.1"}, TargetRef: podRef("web-a"), Conditions: discoveryv1.EndpointConditions{
+					Ready: &no, Serving: &yes, Terminating: &yes,
+				}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-ipv6", Namespace: "shop", Labels: map[string]string{discoveryv1.LabelServiceName: "web"}},
+			Endpoints:  []discoveryv1.Endpoint{{Addresses: []string{"fd00::2"}, TargetRef: podRef("web-b")}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "shop", Labels: map[string]string{discoveryv1.LabelServiceName: "api"}},
+			Endpoints:  []discoveryv1.Endpoint{{Addresses: []string{"10.0.0.9"}}},
+		},
+	}
+	pods := []v1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "web-a", Namespace: "shop"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "web-b", Namespace: "shop"}},
+	}
+	backends := newServiceBackends(slices, pods)
+
+	web := toKubeService(&v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+		Spec:       v1.ServiceSpec{Selector: map[string]string{"app": "web"}},
+	})
+	applyServiceBackends(&web, backends)
+
+	if web.Selector["app"] != "web" {
+		t.Fatalf("expected a structured selector, got %v", web.Selector)
+	}
+	if len(web.Pods) != 2 || web.Pods[0].Name != "web-a" || web.Pods[1].Name != "web-b" {
+		t.Fatalf("expected each backing pod once, got %+v", web.Pods)
+	}
+	if len(web.Endpoints) != 3 {
+		t.Fatalf("expected one endpoint per address, got %+v", web.Endpoints)
+	}
+	if e := web.Endpoints[0]; e.Pod != "web-a" || e.Ready || !e.Serving || !e.Terminating {
+		t.Fatalf("unexpected terminating endpoint: %+v", e)
+	}
+	if e := web.Endpoints[1]; e.Pod != "web-b" || !e.Ready || !e.Serving || e.Terminating || e.NodeName != "node-a" {
+		t.Fatalf("unexpected ready endpoint: %+v", e)
+	}
+
+	api := toKubeService(&v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "shop"}})
+	applyServiceBackends(&api, backends)
+	if len(api.Endpoints) != 1 || len(api.Pods) != 0 || api.Selector == nil {
+		t.Fatalf("expected an endpoint without a pod, got %+v", api)
+	}
+}
+
+func TestServiceStatus(t *testing.T) {
+	yes, no := true, false
+	slice := func(service string, ready ...bool) discoveryv1.EndpointSlice {
+		s := discoveryv1.EndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{Name: service, Namespace: "shop", Labels: map[string]string{discoveryv1.LabelServiceName: service}},
+		}
+		for i := range ready {
+			s.Endpoints = append(s.Endpoints, discoveryv1.Endpoint{
+				Addresses:  []string{fmt.Sprintf("10.0.0.%d", i+1)},
+				Conditions: discoveryv1.EndpointConditions{Ready: &ready[i]},
+			})
+		}
+		return s
+	}
+	backends := newServiceBackends(
+		[]discoveryv1.EndpointSlice{slice("partial", yes, no), slice("down", no), slice("empty")},
+		[]v1.Pod{{ObjectMeta: metav1.ObjectMeta{Name: "web-a", Namespace: "shop", Labels: map[string]string{"app": "web"}}}},
+	)
+	status := func(svc *v1.Service) (string, bool) {
+		svc.Namespace = "shop"
+		if svc.Spec.Selector == nil {
+			svc.Spec.Selector = map[string]string{"app": "web"}
+		}
+		kubeService := toKubeService(svc)
+		applyServiceBackends(&kubeService, backends)
+		return kubeService.Status.Reason, kubeService.Status.Ready
+	}
+
+	cases := []struct {
+		service *v1.Service
+		reason  string
+		ready   bool
+	}{
+		{&v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "partial"}}, "EndpointsNotReady", true},
+		{&v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "down"}}, "NoReadyEndpoints", false},
+		{&v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "empty"}}, "NoEndpoints", false},
+		{&v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "typo"}, Spec: v1.ServiceSpec{Selector: map[string]string{"app": "wbe"}}}, "SelectorMatchesNoPods", false},
+		{&v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "partial"}, Spec: v1.ServiceSpec{Type: v1.ServiceTypeLoadBalancer}}, "LoadBalancerPending", false},
+		{&v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "db"}, Spec: v1.ServiceSpec{Type: v1.ServiceTypeExternalName, ExternalName: "db.example.com"}}, "", true},
+	}
+	for _, c := range cases {
+		if reason, ready := status(c.service); reason != c.reason || ready != c.ready {
+			t.Errorf("service %s (%s): got reason %q ready %v, want %q %v", c.service.Name, c.service.Spec.Type, reason, ready, c.reason, c.ready)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"kubey/api/internal/models"
+	"kubey/api/internal/store"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+)
+
+// eventPruneInterval is how often expired events are removed from the store
+const eventPruneInterval = 10 * time.Minute
+
+// RecordEvents persists events from every watched cluster to the store,
+// keeping them for the retention period instead of the apiserver's event TTL.
+func RecordEvents(ctx context.Context, s *store.Store, retention time.Duration) {
+	registerInformers(func(clusterID string, factory informers.SharedInformerFactory) {
+		save := func(obj interface{}) {
+			event, ok := obj.(*v1.Event)
+			if !ok {
+				return
+			}
+
+			kubeEvent := toKubeEvent(clusterID, event)
+			if kubeEvent.LastTimestamp.Before(time.Now().Add(-retention)) {
+				return
+			}
+			if err := s.SaveEvent(kubeEvent); err != nil {
+				log.Printf("Failed to record event %s/%s for %s: %v", event.Namespace, event.Name, clusterID, err)
+			}
+		}
+
+		factory.Core().V1().Events().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    save,
+			UpdateFunc: func(_, obj interface{}) { save(obj) },
+		})
+	})
+
+	go func() {
+		ticker := time.NewTicker(eventPruneInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				removed, err := s.PruneEvents(time.Now().Add(-retention))
+				if err != nil {
+					log.Printf("Failed to prune recorded events: %v", err)
+				} else if removed > 0 {
+					log.Printf("Pruned %d recorded events older than %v", removed, retention)
+				}
+			}
+		}
+	}()
+}
+
+// QueryEvents searches recorded events. A cluster filter must name a cluster in the kubeconfig.
+func QueryEvents(s *store.Store, query models.EventQuery) ([]models.KubeEvent, error) {
+	if err := checkRecordQuery(query.ClusterID, query.Since, query.Until); err != nil {
+		return nil, err
+	}
+	return s.QueryEvents(query)
+}
+
+// checkRecordQuery validates the cluster and time range of a query for recorded data
+func checkRecordQuery(clusterID string, since, until time.Time) error {
+	if !since.IsZero() && !until.IsZero() && since.After(until) {
+		return fmt.Errorf("%w: since must be before until", ErrInvalidArgument)
+	}
+	if clusterID == "" {
+		return nil
+	}
+	if _, err := contextNameForCluster(clusterID); err != nil {
+		if errors.Is(err, ErrClusterNotFound) {
+			return fmt.Errorf("%w: %s", ErrClusterNotFound, clusterID)
+		}
+		return err
+	}
+	return nil
+}
+
+// toKubeEvent converts a core/v1 event into the recorded event model
+func toKubeEvent(clusterID string, event *v1.Event) models.KubeEvent {
+	firstTimestamp := event.FirstTimestamp.Time
+	lastTimestamp := event.LastTimestamp.Time
+
+	// Events created through events.k8s.io only set EventTime
+	if lastTimestamp.IsZero() {
+		lastTimestamp = event.EventTime.Time
+	}
+	if lastTimestamp.IsZero() {
+		lastTimestamp = event.CreationTimestamp.Time
+	}
+	if firstTimestamp.IsZero() {
+		firstTimestamp = lastTimestamp
+	}
+
+	source := event.Source.Component
+	if source == "" {
+		source = event.ReportingController
+	}
+
+	count := event.Count
+	if event.Series != nil && event.Series.Count > count {
+		count = event.Series.Count
+	}
+	if count == 0 {
+		count = 1
+	}
+
+	return models.KubeEvent{
+		ClusterID: clusterID,
+		UID:       string(event.UID),
+		Namespace: event.Namespace,
+		Name:      event.Name,
+		Type:      event.Type,
+		Reason:    event.Reason,
+		Message:   event.Message,
+		Object: models.ObjectReference{
+			Kind:      event.InvolvedObject.Kind,
+			Namespace: event.InvolvedObject.Namespace,
+			Name:      event.InvolvedObject.Name,
+			UID:       string(event.InvolvedObject.UID),
+		},
+		Source:         source,
+		Count:          count,
+		FirstTimestamp: firstTimestamp,
+		LastTimestamp:  lastTimestamp,
+	}
+}
@@ -0,0 +1,34 @@
+package kubernetes
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCheckRecordQuery(t *testing.T) {
+	previous := kubeconfigPath
+	defer func() { kubeconfigPath = previous }()
+
+	kubeconfigPath = filepath.Join(t.TempDir(), "config")
+	kubeconfig := "apiVersion: v1\nkind: Config\ncontexts:\n- name: prod\n  context: {cluster: prod, user: prod}\n"
+	if err := os.WriteFile(kubeconfigPath, []byte(kubeconfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	if err := checkRecordQuery("", time.Time{}, time.Time{}); err != nil {
+		t.Fatalf("expected an unfiltered query to pass, got %v", err)
+	}
+	if err := checkRecordQuery(clusterIDForContext("prod"), now.Add(-time.Hour), now); err != nil {
+		t.Fatalf("expected a known cluster to pass, got %v", err)
+	}
+	if err := checkRecordQuery(clusterIDForContext("staging"), time.Time{}, time.Time{}); !errors.Is(err, ErrClusterNotFound) {
+		t.Fatalf("expected an unknown cluster to be not found, got %v", err)
+	}
+	if err := checkRecordQuery("", now, now.Add(-time.Hour)); !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("expected an inverted range to be invalid, got %v", err)
+	}
+}
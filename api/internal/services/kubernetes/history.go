@@ -0,0 +1,336 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"kubey/api/internal/models"
+	"kubey/api/internal/store"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	appslisters "k8s.io/client-go/listers/apps/v1"
+	batchlisters "k8s.io/client-go/listers/batch/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// historyRollupResolution is the window raw summary samples are averaged into once they age out
+const historyRollupResolution = time.Hour
+
+// historySampler reads a cluster's state from the shared informer caches
+type historySampler struct {
+	nodes        corelisters.NodeLister
+	pods         corelisters.PodLister
+	namespaces   corelisters.NamespaceLister
+	deployments  appslisters.DeploymentLister
+	statefulSets appslisters.StatefulSetLister
+	daemonSets   appslisters.DaemonSetLister
+	services     corelisters.ServiceLister
+	replicaSets  appslisters.ReplicaSetLister
+	jobs         batchlisters.JobLister
+	hasSynced    []cache.InformerSynced
+}
+
+// HistoryOptions configures summary history sampling
+type HistoryOptions struct {
+	Interval     time.Duration
+	RawRetention time.Duration
+	Retention    time.Duration
+
+	// Cost usage is grouped by node pool (the value of PoolLabel), namespace and each CostLabels pod label
+	PoolLabel  string
+	CostLabels []string
+}
+
+// RecordSummaryHistory samples every watched cluster's summary and per-namespace usage once per
+// interval. Raw samples are kept for RawRetention and then averaged into hourly rollups, which
+// are kept for the Retention period. Per-container workload usage, used for right-sizing, is
+// kept for RawRetention only. Hourly resource-hours for cost allocation are kept for Retention.
+func RecordSummaryHistory(ctx context.Context, s *store.Store, opts HistoryOptions) {
+	var mu sync.Mutex
+	samplers := map[string]*historySampler{}
+
+	registerInformers(func(clusterID string, factory informers.SharedInformerFactory) {
+		core := factory.Core().V1()
+		apps := factory.Apps().V1()
+
+		sampler := &historySampler{
+			nodes:        core.Nodes().Lister(),
+			pods:         core.Pods().Lister(),
+			namespaces:   core.Namespaces().Lister(),
+			deployments:  apps.Deployments().Lister(),
+			statefulSets: apps.StatefulSets().Lister(),
+			daemonSets:   apps.DaemonSets().Lister(),
+			services:     core.Services().Lister(),
+			replicaSets:  apps.ReplicaSets().Lister(),
+			jobs:         factory.Batch().V1().Jobs().Lister(),
+			hasSynced: []cache.InformerSynced{
+				core.Nodes().Informer().HasSynced,
+				core.Pods().Informer().HasSynced,
+				core.Namespaces().Informer().HasSynced,
+				apps.Deployments().Informer().HasSynced,
+				apps.StatefulSets().Informer().HasSynced,
+				apps.DaemonSets().Informer().HasSynced,
+				core.Services().Informer().HasSynced,
+				apps.ReplicaSets().Informer().HasSynced,
+				factory.Batch().V1().Jobs().Informer().HasSynced,
+			},
+		}
+
+		mu.Lock()
+		samplers[clusterID] = sampler
+		mu.Unlock()
+	})
+
+	go func() {
+		ticker := time.NewTicker(opts.Interval)
+		defer ticker.Stop()
+
+		lastCompaction := time.Now()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				mu.Lock()
+				clusters := make(map[string]*historySampler, len(samplers))
+				for clusterID, sampler := range samplers {
+					clusters[clusterID] = sampler
+				}
+				mu.Unlock()
+
+				for clusterID, sampler := range clusters {
+					sampleSummary(ctx, s, clusterID, sampler, now, opts)
+				}
+
+				if now.Sub(lastCompaction) >= historyRollupResolution {
+					lastCompaction = now
+					compactSummaryHistory(s, now, opts.RawRetention, opts.Retention)
+				}
+			}
+		}
+	}()
+}
+
+// GetSummaryHistory returns a cluster's recorded summaries over the last window, averaged per step
+func GetSummaryHistory(clusterID string, window, step time.Duration, s *store.Store) (*models.SummaryHistory, error) {
+	if window <= 0 || step <= 0 {
+		return nil, fmt.Errorf("%w: range and step must be positive", ErrInvalidArgument)
+	}
+	if _, err := contextNameForCluster(clusterID); err != nil {
+		return nil, err
+	}
+
+	end := time.Now()
+	start := end.Add(-window).Truncate(step)
+
+	samples, err := s.QuerySummaryHistory(clusterID, start, end, step)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.SummaryHistory{
+		ClusterID: clusterID,
+		Start:     start,
+		End:       end,
+		Step:      step.String(),
+		Samples:   samples,
+	}, nil
+}
+
+// sampleSummary records one sample for a cluster once its informer caches have synced
+func sampleSummary(ctx context.Context, s *store.Store, clusterID string, sampler *historySampler, now time.Time, opts HistoryOptions) {
+	for _, hasSynced := range sampler.hasSynced {
+		if !hasSynced() {
+			return
+		}
+	}
+
+	nodes, err := sampler.nodes.List(labels.Everything())
+	if err != nil {
+		return
+	}
+	pods, err := sampler.pods.List(labels.Everything())
+	if err != nil {
+		return
+	}
+	namespaces, _ := sampler.namespaces.List(labels.Everything())
+	deployments, _ := sampler.deployments.List(labels.Everything())
+	statefulSets, _ := sampler.statefulSets.List(labels.Everything())
+	daemonSets, _ := sampler.daemonSets.List(labels.Everything())
+	replicaSets, _ := sampler.replicaSets.List(labels.Everything())
+	jobs, _ := sampler.jobs.List(labels.Everything())
+	services, _ := sampler.services.List(labels.Everything())
+
+	// Usage comes from metrics-server; without it the sample still records counts and requests
+	var nodeUsage map[string]resourceUsage
+	var podUsage map[string]map[string]resourceUsage
+	if cs, err := getClientsetForCluster(clusterID); err == nil {
+		metricsCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		nodeUsage, _ = fetchNodeUsage(metricsCtx, cs)
+		podUsage, _ = fetchPodUsage(metricsCtx, cs, "")
+		cancel()
+	}
+
+	sample := buildSummarySample(now, nodes, pods, nodeUsage, podUsage)
+	sample.Summary.TotalNamespaces = len(namespaces)
+	sample.Summary.TotalDeployments = len(deployments)
+	sample.Summary.TotalStatefulSets = len(statefulSets)
+	sample.Summary.TotalDaemonSets = len(daemonSets)
+	sample.Summary.TotalReplicaSets = len(replicaSets)
+	sample.Summary.TotalServices = len(services)
+	sample.Summary.FailedJobs = countFailedJobs(jobs)
+
+	if err := s.SaveSummarySample(clusterID, sample); err != nil {
+		log.Printf("Failed to record summary sample for %s: %v", clusterID, err)
+	}
+
+	costUsage := buildCostUsage(now, opts.Interval, nodes, pods, podUsage, opts.PoolLabel, opts.CostLabels)
+	if err := s.AddCostUsage(clusterID, costUsage); err != nil {
+		log.Printf("Failed to record cost usage for %s: %v", clusterID, err)
+	}
+
+	if podUsage != nil {
+		replicaSetOwner := func(namespace, name string) string {
+			rs, err := sampler.replicaSets.ReplicaSets(namespace).Get(name)
+			if err != nil {
+				return ""
+			}
+			if owner := metav1.GetControllerOf(rs); owner != nil && owner.Kind == "Deployment" {
+				return owner.Name
+			}
+			return ""
+		}
+		usage := buildWorkloadUsage(pods, podUsage, replicaSetOwner)
+		if err := s.SaveWorkloadUsage(clusterID, now, usage); err != nil {
+			log.Printf("Failed to record workload usage for %s: %v", clusterID, err)
+		}
+	}
+}
+
+// buildWorkloadUsage returns the usage of each Deployment and StatefulSet container, taking the
+// busiest replica so recommendations cover every pod. replicaSetOwner resolves a ReplicaSet to
+// its Deployment's name, or "" when it has none.
+func buildWorkloadUsage(pods []*v1.Pod, podUsage map[string]map[string]resourceUsage, replicaSetOwner func(namespace, name string) string) map[models.ContainerKey]models.UsageSample {
+	usage := map[models.ContainerKey]models.UsageSample{}
+
+	for _, pod := range pods {
+		containers, ok := podUsage[pod.Namespace+"/"+pod.Name]
+		if !ok {
+			continue
+		}
+		kind, name := workloadOwner(pod, replicaSetOwner)
+		if name == "" {
+			continue
+		}
+
+		for container, u := range containers {
+			key := models.ContainerKey{Kind: kind, Namespace: pod.Namespace, Name: name, Container: container}
+			current := usage[key]
+			current.CPU = max(current.CPU, u.CPU)
+			current.Memory = max(current.Memory, u.Memory)
+			usage[key] = current
+		}
+	}
+
+	return usage
+}
+
+// workloadOwner returns the Deployment or StatefulSet controlling a pod through its ownerReferences,
+// or an empty name when it has none. replicaSetOwner resolves a ReplicaSet to its Deployment's name.
+func workloadOwner(pod *v1.Pod, replicaSetOwner func(namespace, name string) string) (string, string) {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return "", ""
+	}
+	switch owner.Kind {
+	case "StatefulSet":
+		return "StatefulSet", owner.Name
+	case "ReplicaSet":
+		return "Deployment", replicaSetOwner(pod.Namespace, owner.Name)
+	}
+	return "", ""
+}
+
+// buildSummarySample computes node and pod counts, utilization and per-namespace usage
+func buildSummarySample(now time.Time, nodes []*v1.Node, pods []*v1.Pod, nodeUsage map[string]resourceUsage, podUsage map[string]map[string]resourceUsage) models.SummarySample {
+	sample := models.SummarySample{
+		Time:       now,
+		Namespaces: map[string]models.NamespaceUsage{},
+	}
+	summary := &sample.Summary
+
+	nodeList := make([]v1.Node, 0, len(nodes))
+	for _, node := range nodes {
+		nodeList = append(nodeList, *node)
+		summary.TotalNodes++
+		if isNodeReady(node) {
+			summary.ReadyNodes++
+		}
+	}
+
+	podList := make([]v1.Pod, 0, len(pods))
+	for _, pod := range pods {
+		podList = append(podList, *pod)
+		summary.TotalPods++
+
+		usage := sample.Namespaces[pod.Namespace]
+		usage.Pods++
+		switch pod.Status.Phase {
+		case v1.PodRunning:
+			summary.RunningPods++
+			usage.RunningPods++
+		case v1.PodPending:
+			summary.PendingPods++
+		case v1.PodFailed:
+			summary.FailedPods++
+		}
+
+		if !isPodTerminal(pod) {
+			var containerUsage map[string]resourceUsage
+			if podUsage != nil {
+				containerUsage = podUsage[pod.Namespace+"/"+pod.Name]
+			}
+			metrics := getPodMetrics(pod, containerUsage)
+			usage.CPUUsage += metrics.CPUUsage
+			usage.MemoryUsage += metrics.MemoryUsage
+			usage.CPURequest += metrics.CPURequest
+			usage.MemoryRequest += metrics.MemoryRequest
+		}
+		sample.Namespaces[pod.Namespace] = usage
+	}
+
+	applyUtilization(summary, nodeList, podList, nodeUsage)
+	return sample
+}
+
+// compactSummaryHistory rolls up raw samples older than rawRetention and prunes expired history
+func compactSummaryHistory(s *store.Store, now time.Time, rawRetention, retention time.Duration) {
+	compacted, err := s.CompactSummaryHistory(now.Add(-rawRetention), historyRollupResolution)
+	if err != nil {
+		log.Printf("Failed to compact summary history: %v", err)
+	} else if compacted > 0 {
+		log.Printf("Compacted %d summary samples older than %v", compacted, rawRetention)
+	}
+
+	if _, err := s.PruneWorkloadUsage(now.Add(-rawRetention)); err != nil {
+		log.Printf("Failed to prune workload usage: %v", err)
+	}
+
+	if _, err := s.PruneCostUsage(now.Add(-retention)); err != nil {
+		log.Printf("Failed to prune cost usage: %v", err)
+	}
+
+	removed, err := s.PruneSummaryHistory(now.Add(-retention))
+	if err != nil {
+		log.Printf("Failed to prune summary history: %v", err)
+	} else if removed > 0 {
+		log.Printf("Pruned %d summary history entries older than %v", removed, retention)
+	}
+}
@@ -0,0 +1,54 @@
+package kubernetes
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestBuildSummarySample(t *testing.T) {
+	nodes := []*v1.Node{{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-a"},
+		Status: v1.NodeStatus{
+			Conditions:  []v1.NodeCondition{{Type: v1.NodeReady, Status: v1.ConditionTrue}},
+			Allocatable: v1.ResourceList{v1.ResourceCPU: resource.MustParse("2"), v1.ResourceMemory: resource.MustParse("4Gi")},
+		},
+	}}
+	pod := func(namespace, name string, phase v1.PodPhase) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Spec: v1.PodSpec{NodeName: "node-a", Containers: []v1.Container{{
+				Name:      "app",
+				Resources: v1.ResourceRequirements{Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("500m")}},
+			}}},
+			Status: v1.PodStatus{Phase: phase},
+		}
+	}
+	pods := []*v1.Pod{
+		pod("shop", "web-1", v1.PodRunning),
+		pod("shop", "web-2", v1.PodPending),
+		pod("batch", "job-1", v1.PodSucceeded),
+	}
+	podUsage := map[string]map[string]resourceUsage{"shop/web-1": {"app": {CPU: 200, Memory: 64 << 20}}}
+
+	sample := buildSummarySample(time.Now(), nodes, pods, nil, podUsage)
+
+	if sample.Summary.TotalNodes != 1 || sample.Summary.ReadyNodes != 1 || sample.Summary.TotalPods != 3 ||
+		sample.Summary.RunningPods != 1 || sample.Summary.PendingPods != 1 {
+		t.Fatalf("unexpected counts: %+v", sample.Summary)
+	}
+	if sample.Summary.CPURequested != 50 {
+		t.Errorf("CPURequested = %v, want 50", sample.Summary.CPURequested)
+	}
+
+	shop := sample.Namespaces["shop"]
+	if shop.Pods != 2 || shop.RunningPods != 1 || shop.CPURequest != 1000 || shop.CPUUsage != 200 || shop.MemoryUsage != 64<<20 {
+		t.Errorf("unexpected shop usage: %+v", shop)
+	}
+	if batch := sample.Namespaces["batch"]; batch.Pods != 1 || batch.CPURequest != 0 {
+		t.Errorf("finished pods should be counted without requests: %+v", batch)
+	}
+}
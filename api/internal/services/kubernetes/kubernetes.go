@@ -4,8 +4,10 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
+	"kubey/api/internal/metrics"
 	"kubey/api/internal/models"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -49,6 +51,13 @@ func InitClient(kubeconfig string) error {
 		}
 	}
 
+	// Label API server metrics of the default clientset with the current context's cluster ID
+	defaultCluster := "in-cluster"
+	if raw, err := clientcmd.LoadFromFile(kubeconfigPath); err == nil && raw.CurrentContext != "" {
+		defaultCluster = clusterIDForContext(raw.CurrentContext)
+	}
+	config.Wrap(metrics.InstrumentTransport(defaultCluster))
+
 	// Create a clientset for the current context to verify connectivity
 	clientset, err = kubernetes.NewForConfig(config)
 	if err != nil {
@@ -61,53 +70,25 @@ func InitClient(kubeconfig string) error {
 
 // GetClusters returns all clusters from all contexts in the kubeconfig (loaded in parallel)
 func GetClusters() ([]models.KubeCluster, error) {
-	if kubeconfigPath == "" {
-		return nil, fmt.Errorf("kubernetes client not initialized")
-	}
-
-	// Load the kubeconfig file
-	config, err := clientcmd.LoadFromFile(kubeconfigPath)
+	contextNames, err := listContextNames()
 	if err != nil {
-		return nil, fmt.Errorf("failed to load kubeconfig: %v", err)
-	}
-
-	// Collect context names
-	var contextNames []string
-	for contextName := range config.Contexts {
-		contextNames = append(contextNames, contextName)
-	}
-
-	if len(contextNames) == 0 {
-		return nil, fmt.Errorf("no contexts found in kubeconfig")
+		return nil, err
 	}
 
 	// Process all contexts in parallel using goroutines
-	type result struct {
-		cluster *models.KubeCluster
-		err     error
-		name    string
-	}
-
-	results := make(chan result, len(contextNames))
-
-	for _, contextName := range contextNames {
-		go func(name string) {
-			log.Printf("Processing context: %s", name)
-			cluster, err := getClusterDataForContext(name)
-			results <- result{cluster: cluster, err: err, name: name}
-		}(contextName)
-	}
+	results := fanOut(contextNames, func(name string) (*models.KubeCluster, error) {
+		log.Printf("Processing context: %s", name)
+		return getClusterDataForContext(name)
+	})
 
-	// Collect results from all goroutines
 	var clusters []models.KubeCluster
-	for i := 0; i < len(contextNames); i++ {
-		res := <-results
+	for _, res := range results {
 		if res.err != nil {
 			// If connection fails, create an offline cluster entry
-			log.Printf("Failed to connect to context %s: %v", res.name, res.err)
+			log.Printf("Failed to connect to context %s: %v", res.key, res.err)
 			clusters = append(clusters, models.KubeCluster{
-				ID:      fmt.Sprintf("context-%s", res.name),
-				Name:    res.name,
+				ID:      clusterIDForContext(res.key),
+				Name:    res.key,
 				Version: "unknown",
 				Status: models.ResourceStatus{
 					Phase:       "Offline",
@@ -118,13 +99,38 @@ func GetClusters() ([]models.KubeCluster, error) {
 				},
 			})
 		} else {
-			clusters = append(clusters, *res.cluster)
+			clusters = append(clusters, *res.value)
 		}
 	}
 
 	return clusters, nil
 }
 
+// fanOutResult is the outcome of a fanOut call for one context or cluster
+type fanOutResult[T any] struct {
+	key   string
+	value T
+	err   error
+}
+
+// fanOut calls fn for each context or cluster in parallel and returns the results in the order
+// they complete
+func fanOut[T any](keys []string, fn func(key string) (T, error)) []fanOutResult[T] {
+	results := make(chan fanOutResult[T], len(keys))
+	for _, key := range keys {
+		go func(key string) {
+			value, err := fn(key)
+			results <- fanOutResult[T]{key: key, value: value, err: err}
+		}(key)
+	}
+
+	collected := make([]fanOutResult[T], 0, len(keys))
+	for range keys {
+		collected = append(collected, <-results)
+	}
+	return collected
+}
+
 // GetCluster returns a specific cluster by ID
 func GetCluster(clusterID string) (*models.KubeCluster, error) {
 	clusters, err := GetClusters()
@@ -141,91 +147,244 @@ func GetCluster(clusterID string) (*models.KubeCluster, error) {
 	return nil, nil
 }
 
-// GetClusterNodes returns nodes for a specific cluster
-func GetClusterNodes(clusterID string) ([]models.KubeNode, error) {
-	if clientset == nil {
-		return nil, fmt.Errorf("kubernetes client not initialized")
+// GetClusterNodes returns nodes for a specific cluster.
+// With withStats, node and pod storage usage is read from every node's kubelet stats summary.
+func GetClusterNodes(clusterID string, withStats bool) ([]models.KubeNode, error) {
+	cs, err := getClientsetForCluster(clusterID)
+	if err != nil {
+		return nil, err
 	}
+	return getClusterNodes(context.TODO(), cs, withStats)
+}
 
-	nodes, err := clientset.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
+// getClusterNodes returns nodes with the pods scheduled on them using the provided clientset.
+// Node metrics combine usage from metrics-server with the requests and limits of their running pods.
+// Kubelet stats summaries are only fetched when withStats is set, as they cost a request per node.
+func getClusterNodes(ctx context.Context, cs kubernetes.Interface, withStats bool) ([]models.KubeNode, error) {
+	nodes, err := cs.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list nodes: %v", err)
 	}
 
+	// Without the pods every node would report zero requests, so a failed list is an error
+	pods, err := cs.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods for nodes: %v", err)
+	}
+
+	nodeUsage, err := fetchNodeUsage(ctx, cs)
+	if err != nil {
+		log.Printf("Node usage unavailable: %v", err)
+	}
+
+	var nodeStats map[string]*statsSummary
+	if withStats {
+		nodeNames := make([]string, 0, len(nodes.Items))
+		for _, node := range nodes.Items {
+			nodeNames = append(nodeNames, node.Name)
+		}
+		nodeStats = fetchNodeStats(ctx, cs, nodeNames)
+	}
+
+	podUsage, err := fetchPodUsage(ctx, cs, "")
+	if err != nil {
+		log.Printf("Pod usage unavailable: %v", err)
+	}
+	claims, err := getClaimIndex(ctx, cs, "")
+	if err != nil {
+		log.Printf("Persistent volume claims unavailable: %v", err)
+	}
+	podStats := podStatsByKey(nodeStats)
+	podsByNode := map[string][]models.KubePod{}
+	requestedByNode := map[string]*models.ResourceMetrics{}
+	for _, pod := range pods.Items {
+		if pod.Spec.NodeName == "" {
+			continue
+		}
+		kubePod := toKubePod(&pod, podUsage[pod.Namespace+"/"+pod.Name])
+		linkPodVolumes(&kubePod, claims)
+		applyPodStats(&kubePod, podStats[pod.Namespace+"/"+pod.Name])
+		podsByNode[pod.Spec.NodeName] = append(podsByNode[pod.Spec.NodeName], kubePod)
+
+		requested := requestedByNode[pod.Spec.NodeName]
+		if requested == nil {
+			requested = &models.ResourceMetrics{}
+			requestedByNode[pod.Spec.NodeName] = requested
+		}
+		addPodRequests(requested, &pod, kubePod.Metrics)
+	}
+
 	var kubeNodes []models.KubeNode
 	for _, node := range nodes.Items {
-		kubeNode := models.KubeNode{
-			Name:        node.Name,
-			Kubelet:     node.Status.NodeInfo.KubeletVersion,
-			Runtime:     node.Status.NodeInfo.ContainerRuntimeVersion,
-			Role:        getNodeRole(&node),
-			Pods:        []models.KubePod{}, // Will be populated separately
-			Labels:      node.Labels,
-			Annotations: node.Annotations,
-			CreatedAt:   node.CreationTimestamp.Time,
-			Status:      getNodeStatus(&node),
-			Capacity:    getNodeCapacity(node.Status.Capacity),
-			Allocatable: getNodeCapacity(node.Status.Allocatable),
-			Conditions:  getNodeConditions(&node),
+		nodePods := podsByNode[node.Name]
+		if nodePods == nil {
+			nodePods = []models.KubePod{}
+		}
+
+		metrics := requestedByNode[node.Name]
+		if metrics == nil {
+			metrics = &models.ResourceMetrics{}
+		}
+		if usage, ok := nodeUsage[node.Name]; ok {
+			metrics.CPUUsage = usage.CPU
+			metrics.MemoryUsage = usage.Memory
+			metrics.UsageAvailable = true
 		}
+
+		kubeNode := toKubeNode(&node, nodePods)
+		kubeNode.Metrics = metrics
+		applyNodeStats(&kubeNode, nodeStats[node.Name])
 		kubeNodes = append(kubeNodes, kubeNode)
 	}
 
 	return kubeNodes, nil
 }
 
-// GetClusterPods returns pods for a specific cluster
-func GetClusterPods(clusterID string) ([]models.KubePod, error) {
-	if clientset == nil {
-		return nil, fmt.Errorf("kubernetes client not initialized")
+// toKubeNode converts a node without metrics or kubelet stats
+func toKubeNode(node *v1.Node, pods []models.KubePod) models.KubeNode {
+	info := node.Status.NodeInfo
+	kubeNode := models.KubeNode{
+		Name:          node.Name,
+		Kubelet:       info.KubeletVersion,
+		Runtime:       info.ContainerRuntimeVersion,
+		Role:          getNodeRole(node),
+		Pods:          pods,
+		Labels:        node.Labels,
+		Annotations:   node.Annotations,
+		CreatedAt:     node.CreationTimestamp.Time,
+		Status:        getNodeStatus(node),
+		Capacity:      getNodeCapacity(node.Status.Capacity),
+		Allocatable:   getNodeCapacity(node.Status.Allocatable),
+		Conditions:    getNodeConditions(node),
+		Unschedulable: node.Spec.Unschedulable,
+		Taints:        []models.NodeTaint{},
+		Pressure:      getNodePressure(node),
+		Addresses:     []models.NodeAddress{},
+		Zone:          nodeLabel(node, v1.LabelTopologyZone, v1.LabelFailureDomainBetaZone),
+		Region:        nodeLabel(node, v1.LabelTopologyRegion, v1.LabelFailureDomainBetaRegion),
+		OS:            info.OperatingSystem,
+		Architecture:  info.Architecture,
+		OSImage:       info.OSImage,
+		KernelVersion: info.KernelVersion,
+	}
+	for _, taint := range node.Spec.Taints {
+		kubeNode.Taints = append(kubeNode.Taints, models.NodeTaint{
+			Key:    taint.Key,
+			Value:  taint.Value,
+			Effect: string(taint.Effect),
+		})
 	}
+	for _, address := range node.Status.Addresses {
+		kubeNode.Addresses = append(kubeNode.Addresses, models.NodeAddress{
+			Type:    string(address.Type),
+			Address: address.Address,
+		})
+	}
+	return kubeNode
+}
 
-	pods, err := clientset.CoreV1().Pods("").List(context.TODO(), metav1.ListOptions{})
+// nodeLabel returns the first of the given labels set on the node, to fall back to deprecated keys
+func nodeLabel(node *v1.Node, keys ...string) string {
+	for _, key := range keys {
+		if value := node.Labels[key]; value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// GetClusterPods returns pods for a specific cluster.
+// With withStats, storage usage is read from the kubelet stats summary of every node running them.
+func GetClusterPods(clusterID string, withStats bool) ([]models.KubePod, error) {
+	cs, err := getClientsetForCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	pods, err := cs.CoreV1().Pods("").List(context.TODO(), metav1.ListOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list pods: %v", err)
 	}
 
-	var kubePods []models.KubePod
-	for _, pod := range pods.Items {
-		kubePod := models.KubePod{
-			Name:         pod.Name,
-			Namespace:    pod.Namespace,
-			Role:         getPodRole(&pod),
-			IP:           pod.Status.PodIP,
-			Labels:       pod.Labels,
-			NodeName:     pod.Spec.NodeName,
-			CreatedAt:    pod.CreationTimestamp.Time,
-			RestartCount: getTotalRestartCount(&pod),
-			Status:       getPodStatus(&pod),
-		}
-
-		// Add containers with status
-		for _, container := range pod.Spec.Containers {
-			containerStatus := getContainerStatus(&pod, container.Name)
-			kubeContainer := models.KubeContainer{
-				Name:   container.Name,
-				Image:  container.Image,
-				Ready:  containerStatus.Ready,
-				Status: containerStatus.Status,
+	podUsage, err := fetchPodUsage(context.TODO(), cs, "")
+	if err != nil {
+		log.Printf("Pod usage unavailable: %v", err)
+	}
+
+	var podStats map[string]*podStats
+	if withStats {
+		var nodeNames []string
+		seen := map[string]bool{}
+		for _, pod := range pods.Items {
+			if pod.Spec.NodeName != "" && !seen[pod.Spec.NodeName] {
+				seen[pod.Spec.NodeName] = true
+				nodeNames = append(nodeNames, pod.Spec.NodeName)
 			}
-			kubePod.Containers = append(kubePod.Containers, kubeContainer)
 		}
+		podStats = podStatsByKey(fetchNodeStats(context.TODO(), cs, nodeNames))
+	}
 
-		// Add volumes
-		for _, volume := range pod.Spec.Volumes {
-			volumeType := getVolumeType(&volume)
-			kubePod.Volumes = append(kubePod.Volumes, models.KubeVolume{
-				Name: volume.Name,
-				Type: volumeType,
-			})
-		}
+	claims, err := getClaimIndex(context.TODO(), cs, "")
+	if err != nil {
+		log.Printf("Persistent volume claims unavailable: %v", err)
+	}
 
+	var kubePods []models.KubePod
+	for _, pod := range pods.Items {
+		kubePod := toKubePod(&pod, podUsage[pod.Namespace+"/"+pod.Name])
+		linkPodVolumes(&kubePod, claims)
+		applyPodStats(&kubePod, podStats[pod.Namespace+"/"+pod.Name])
 		kubePods = append(kubePods, kubePod)
 	}
 
 	return kubePods, nil
 }
 
+// toKubePod converts a pod, with its per-container usage if known, into the API model
+func toKubePod(pod *v1.Pod, usage map[string]resourceUsage) models.KubePod {
+	kubePod := models.KubePod{
+		Name:         pod.Name,
+		Namespace:    pod.Namespace,
+		Role:         getPodRole(pod),
+		IP:           pod.Status.PodIP,
+		Labels:       pod.Labels,
+		NodeName:     pod.Spec.NodeName,
+		CreatedAt:    pod.CreationTimestamp.Time,
+		RestartCount: getTotalRestartCount(pod),
+		Status:       getPodStatus(pod),
+		Metrics:      getPodMetrics(pod, usage),
+	}
+
+	// Add containers with status
+	for _, container := range pod.Spec.Containers {
+		containerStatus := getContainerStatus(pod, container.Name)
+		var containerUsage *resourceUsage
+		if u, ok := usage[container.Name]; ok {
+			containerUsage = &u
+		}
+		kubeContainer := models.KubeContainer{
+			Name:    container.Name,
+			Image:   container.Image,
+			Ready:   containerStatus.Ready,
+			Status:  containerStatus.Status,
+			Metrics: getContainerMetrics(&container, containerUsage),
+		}
+		kubePod.Containers = append(kubePod.Containers, kubeContainer)
+	}
+
+	// Add volumes
+	for _, volume := range pod.Spec.Volumes {
+		volumeType := getVolumeType(&volume)
+		kubePod.Volumes = append(kubePod.Volumes, models.KubeVolume{
+			Name:      volume.Name,
+			Type:      volumeType,
+			ClaimName: getVolumeClaimName(pod, &volume),
+		})
+	}
+
+	return kubePod
+}
+
 // GetClusterServices returns services for a specific cluster
 func GetClusterServices(clusterID string) ([]models.KubeService, error) {
 	if clientset == nil {
@@ -237,48 +396,65 @@ func GetClusterServices(clusterID string) ([]models.KubeService, error) {
 		return nil, fmt.Errorf("failed to list services: %v", err)
 	}
 
-	var kubeServices []models.KubeService
-	for _, svc := range services.Items {
-		kubeService := models.KubeService{
-			Name:        svc.Name,
-			Namespace:   svc.Namespace,
-			Type:        string(svc.Spec.Type),
-			ClusterIP:   svc.Spec.ClusterIP,
-			Selector:    fmt.Sprintf("%v", svc.Spec.Selector),
-			Role:        getServiceRole(&svc),
-			Labels:      svc.Labels,
-			CreatedAt:   svc.CreationTimestamp.Time,
-			Status:      getServiceStatus(&svc),
-			Pods:        []models.KubePod{}, // Can be populated with related pods
-			ExternalIPs: svc.Spec.ExternalIPs,
-		}
-
-		// Handle LoadBalancer IP
-		if len(svc.Status.LoadBalancer.Ingress) > 0 {
-			kubeService.LoadBalancerIP = svc.Status.LoadBalancer.Ingress[0].IP
-		}
-
-		// Convert ports
-		for _, port := range svc.Spec.Ports {
-			servicePort := models.ServicePort{
-				Name:       port.Name,
-				Port:       port.Port,
-				TargetPort: port.TargetPort.String(),
-				Protocol:   string(port.Protocol),
-			}
-			if port.NodePort != 0 {
-				servicePort.NodePort = port.NodePort
-				kubeService.NodePort = &port.NodePort
-			}
-			kubeService.Ports = append(kubeService.Ports, servicePort)
-		}
+	backends, err := getServiceBackends(context.TODO(), clientset, "")
+	if err != nil {
+		log.Printf("Service endpoints unavailable: %v", err)
+	}
 
+	var kubeServices []models.KubeService
+	for i := range services.Items {
+		kubeService := toKubeService(&services.Items[i])
+		applyServiceBackends(&kubeService, backends)
 		kubeServices = append(kubeServices, kubeService)
 	}
 
 	return kubeServices, nil
 }
 
+// toKubeService converts a service into the API model
+func toKubeService(svc *v1.Service) models.KubeService {
+	kubeService := models.KubeService{
+		Name:        svc.Name,
+		Namespace:   svc.Namespace,
+		Type:        string(svc.Spec.Type),
+		ClusterIP:   svc.Spec.ClusterIP,
+		Selector:    svc.Spec.Selector,
+		Role:        getServiceRole(svc),
+		Labels:      svc.Labels,
+		CreatedAt:   svc.CreationTimestamp.Time,
+		Status:      getServiceStatus(svc),
+		Pods:        []models.KubePod{}, // Filled from EndpointSlices by applyServiceBackends
+		Endpoints:   []models.ServiceEndpoint{},
+		ExternalIPs: svc.Spec.ExternalIPs,
+	}
+
+	if kubeService.Selector == nil {
+		kubeService.Selector = map[string]string{}
+	}
+
+	// Handle LoadBalancer IP
+	if len(svc.Status.LoadBalancer.Ingress) > 0 {
+		kubeService.LoadBalancerIP = svc.Status.LoadBalancer.Ingress[0].IP
+	}
+
+	// Convert ports
+	for _, port := range svc.Spec.Ports {
+		servicePort := models.ServicePort{
+			Name:       port.Name,
+			Port:       port.Port,
+			TargetPort: port.TargetPort.String(),
+			Protocol:   string(port.Protocol),
+		}
+		if port.NodePort != 0 {
+			servicePort.NodePort = port.NodePort
+			kubeService.NodePort = &port.NodePort
+		}
+		kubeService.Ports = append(kubeService.Ports, servicePort)
+	}
+
+	return kubeService
+}
+
 // GetClusterDeployments returns deployments for a specific cluster
 func GetClusterDeployments(clusterID string) ([]models.KubeDeployment, error) {
 	if clientset == nil {
@@ -322,12 +498,60 @@ func GetClusterNamespaces(clusterID string) ([]models.KubeNamespace, error) {
 }
 
 // getClusterNamespaces returns namespaces using the provided clientset
-func getClusterNamespaces(cs *kubernetes.Clientset) ([]models.KubeNamespace, error) {
+func getClusterNamespaces(cs kubernetes.Interface) ([]models.KubeNamespace, error) {
 	namespaces, err := cs.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list namespaces: %v", err)
 	}
 
+	// List the other workload kinds once across the cluster; a failure leaves that kind empty
+	// rather than dropping namespaces
+	statefulSets := map[string][]models.KubeStatefulSet{}
+	if list, err := cs.AppsV1().StatefulSets("").List(context.TODO(), metav1.ListOptions{}); err != nil {
+		log.Printf("Failed to get statefulsets: %v", err)
+	} else {
+		for i := range list.Items {
+			item := &list.Items[i]
+			statefulSets[item.Namespace] = append(statefulSets[item.Namespace], toKubeStatefulSet(item))
+		}
+	}
+
+	daemonSets := map[string][]models.KubeDaemonSet{}
+	if list, err := cs.AppsV1().DaemonSets("").List(context.TODO(), metav1.ListOptions{}); err != nil {
+		log.Printf("Failed to get daemonsets: %v", err)
+	} else {
+		for i := range list.Items {
+			item := &list.Items[i]
+			daemonSets[item.Namespace] = append(daemonSets[item.Namespace], toKubeDaemonSet(item))
+		}
+	}
+
+	replicaSets := map[string][]models.KubeReplicaSet{}
+	if list, err := cs.AppsV1().ReplicaSets("").List(context.TODO(), metav1.ListOptions{}); err != nil {
+		log.Printf("Failed to get replicasets: %v", err)
+	} else {
+		for i := range list.Items {
+			item := &list.Items[i]
+			replicaSets[item.Namespace] = append(replicaSets[item.Namespace], toKubeReplicaSet(item))
+		}
+	}
+
+	// Count pods and resolve services to them from one cluster-wide list of pods and EndpointSlices
+	podCounts := map[string]int{}
+	var backends *serviceBackends
+	if podList, err := cs.CoreV1().Pods("").List(context.TODO(), metav1.ListOptions{}); err != nil {
+		log.Printf("Failed to get pods: %v", err)
+	} else {
+		for i := range podList.Items {
+			podCounts[podList.Items[i].Namespace]++
+		}
+		if sliceList, err := cs.DiscoveryV1().EndpointSlices("").List(context.TODO(), metav1.ListOptions{}); err != nil {
+			log.Printf("Failed to get endpoint slices: %v", err)
+		} else {
+			backends = newServiceBackends(sliceList.Items, podList.Items)
+		}
+	}
+
 	var kubeNamespaces []models.KubeNamespace
 	for _, ns := range namespaces.Items {
 		// Get deployments for this namespace
@@ -363,54 +587,23 @@ func getClusterNamespaces(cs *kubernetes.Clientset) ([]models.KubeNamespace, err
 		}
 
 		var services []models.KubeService
-		for _, svc := range serviceList.Items {
-			kubeService := models.KubeService{
-				Name:        svc.Name,
-				Namespace:   svc.Namespace,
-				Type:        string(svc.Spec.Type),
-				ClusterIP:   svc.Spec.ClusterIP,
-				Selector:    fmt.Sprintf("%v", svc.Spec.Selector),
-				Role:        getServiceRole(&svc),
-				Labels:      svc.Labels,
-				CreatedAt:   svc.CreationTimestamp.Time,
-				Status:      getServiceStatus(&svc),
-				Pods:        []models.KubePod{},
-				ExternalIPs: svc.Spec.ExternalIPs,
-			}
-
-			// Convert ports
-			for _, port := range svc.Spec.Ports {
-				servicePort := models.ServicePort{
-					Name:       port.Name,
-					Port:       port.Port,
-					TargetPort: port.TargetPort.String(),
-					Protocol:   string(port.Protocol),
-				}
-				if port.NodePort != 0 {
-					servicePort.NodePort = port.NodePort
-					kubeService.NodePort = &port.NodePort
-				}
-				kubeService.Ports = append(kubeService.Ports, servicePort)
-			}
-
-			services = append(services, kubeService)
-		}
-
-		// Get pod count for this namespace
-		podList, err := cs.CoreV1().Pods(ns.Name).List(context.TODO(), metav1.ListOptions{})
-		podCount := 0
-		if err == nil {
-			podCount = len(podList.Items)
+		for i := range serviceList.Items {
+			service := toKubeService(&serviceList.Items[i])
+			applyServiceBackends(&service, backends)
+			services = append(services, service)
 		}
 
 		kubeNamespace := models.KubeNamespace{
-			Name:        ns.Name,
-			Deployments: deployments,
-			Services:    services,
-			PodCount:    podCount,
-			Labels:      ns.Labels,
-			CreatedAt:   ns.CreationTimestamp.Time,
-			Status:      getNamespaceStatus(&ns),
+			Name:         ns.Name,
+			Deployments:  deployments,
+			StatefulSets: emptyIfNil(statefulSets[ns.Name]),
+			DaemonSets:   emptyIfNil(daemonSets[ns.Name]),
+			ReplicaSets:  emptyIfNil(replicaSets[ns.Name]),
+			Services:     services,
+			PodCount:     podCounts[ns.Name],
+			Labels:       ns.Labels,
+			CreatedAt:    ns.CreationTimestamp.Time,
+			Status:       getNamespaceStatus(&ns),
 		}
 
 		kubeNamespaces = append(kubeNamespaces, kubeNamespace)
@@ -419,22 +612,20 @@ func getClusterNamespaces(cs *kubernetes.Clientset) ([]models.KubeNamespace, err
 	return kubeNamespaces, nil
 }
 
-// getClusterDataForContext creates a clientset for the given context and retrieves lightweight cluster data
-func getClusterDataForContext(contextName string) (*models.KubeCluster, error) {
-	// Build config for this specific context
-	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
-		&clientcmd.ClientConfigLoadingRules{ExplicitPath: kubeconfigPath},
-		&clientcmd.ConfigOverrides{CurrentContext: contextName},
-	).ClientConfig()
-	if err != nil {
-		return nil, fmt.Errorf("failed to build config for context %s: %v", contextName, err)
+// emptyIfNil returns an empty slice for nil, so that it is serialized as [] rather than null
+func emptyIfNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
 	}
+	return items
+}
 
+// getClusterDataForContext creates a clientset for the given context and retrieves lightweight cluster data
+func getClusterDataForContext(contextName string) (*models.KubeCluster, error) {
 	// Create a clientset for this context with a short timeout
-	config.Timeout = 5 * time.Second
-	contextClientset, err := kubernetes.NewForConfig(config)
+	contextClientset, err := newClientsetForContext(contextName, 5*time.Second)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create clientset for context %s: %v", contextName, err)
+		return nil, err
 	}
 
 	// Get lightweight cluster data (no detailed resources)
@@ -453,7 +644,7 @@ func getClusterDataLightweight(contextName string, cs *kubernetes.Clientset) (*m
 	}
 
 	cluster := &models.KubeCluster{
-		ID:      fmt.Sprintf("context-%s", contextName),
+		ID:      clusterIDForContext(contextName),
 		Name:    contextName,
 		Version: version.GitVersion,
 		Status: models.ResourceStatus{
@@ -466,6 +657,7 @@ func getClusterDataLightweight(contextName string, cs *kubernetes.Clientset) (*m
 	// Get quick counts without loading full data
 	// Node count
 	nodes, err := cs.CoreV1().Nodes().List(ctx, metav1.ListOptions{Limit: 1000})
+	nodesListed := err == nil
 	if err == nil {
 		cluster.Summary.TotalNodes = len(nodes.Items)
 		for _, node := range nodes.Items {
@@ -483,6 +675,7 @@ func getClusterDataLightweight(contextName string, cs *kubernetes.Clientset) (*m
 
 	// Pod count (just count, don't load full data)
 	pods, err := cs.CoreV1().Pods("").List(ctx, metav1.ListOptions{Limit: 10000})
+	podsListed := err == nil
 	if err == nil {
 		cluster.Summary.TotalPods = len(pods.Items)
 		for _, pod := range pods.Items {
@@ -494,18 +687,48 @@ func getClusterDataLightweight(contextName string, cs *kubernetes.Clientset) (*m
 		}
 	}
 
+	// Utilization against allocatable capacity
+	if nodesListed && podsListed {
+		nodeUsage, err := fetchNodeUsage(ctx, cs)
+		if err != nil {
+			log.Printf("Node usage unavailable for %s: %v", contextName, err)
+		}
+		applyUtilization(&cluster.Summary, nodes.Items, pods.Items, nodeUsage)
+	}
+
 	// Deployment count
 	deployments, err := cs.AppsV1().Deployments("").List(ctx, metav1.ListOptions{Limit: 1000})
 	if err == nil {
 		cluster.Summary.TotalDeployments = len(deployments.Items)
 	}
 
+	// StatefulSet, DaemonSet and ReplicaSet counts
+	statefulSets, err := cs.AppsV1().StatefulSets("").List(ctx, metav1.ListOptions{Limit: 1000})
+	if err == nil {
+		cluster.Summary.TotalStatefulSets = len(statefulSets.Items)
+	}
+	daemonSets, err := cs.AppsV1().DaemonSets("").List(ctx, metav1.ListOptions{Limit: 1000})
+	if err == nil {
+		cluster.Summary.TotalDaemonSets = len(daemonSets.Items)
+	}
+	replicaSets, err := cs.AppsV1().ReplicaSets("").List(ctx, metav1.ListOptions{Limit: 1000})
+	if err == nil {
+		cluster.Summary.TotalReplicaSets = len(replicaSets.Items)
+	}
+
 	// Service count
 	services, err := cs.CoreV1().Services("").List(ctx, metav1.ListOptions{Limit: 1000})
 	if err == nil {
 		cluster.Summary.TotalServices = len(services.Items)
 	}
 
+	// Failed jobs from the watches when they have synced, otherwise from a bounded list like the counts above
+	if failedJobs, ok := liveFailedJobs(cluster.ID); ok {
+		cluster.Summary.FailedJobs = failedJobs
+	} else {
+		cluster.Summary.FailedJobs = getFailedJobCount(ctx, cs, 1000)
+	}
+
 	return cluster, nil
 }
 
@@ -528,26 +751,23 @@ func getClusterData(contextName string, cs *kubernetes.Clientset) (*models.KubeC
 	}
 
 	cluster := &models.KubeCluster{
-		ID:      fmt.Sprintf("context-%s", contextName),
+		ID:      clusterIDForContext(contextName),
 		Name:    contextName,
 		Version: version.GitVersion,
 		Status:  getClusterStatus(),
 	}
 
-	// Get control plane nodes
-	controlPlaneNodes, err := getControlPlaneNodes(cs)
+	// Get nodes with their pods, split into the control plane and workers
+	nodes, err := getClusterNodes(context.TODO(), cs, false)
 	if err != nil {
-		log.Printf("Failed to get control plane nodes for %s: %v", contextName, err)
-	} else {
-		cluster.ControlPlane.Nodes = controlPlaneNodes
+		log.Printf("Failed to get nodes for %s: %v", contextName, err)
 	}
-
-	// Get worker nodes
-	workerNodes, err := getWorkerNodes(cs)
-	if err != nil {
-		log.Printf("Failed to get worker nodes for %s: %v", contextName, err)
-	} else {
-		cluster.Nodes = workerNodes
+	for _, node := range nodes {
+		if node.Role == "control-plane" {
+			cluster.ControlPlane.Nodes = append(cluster.ControlPlane.Nodes, node)
+		} else {
+			cluster.Nodes = append(cluster.Nodes, node)
+		}
 	}
 
 	// Get namespaces with deployments and services
@@ -564,51 +784,6 @@ func getClusterData(contextName string, cs *kubernetes.Clientset) (*models.KubeC
 	return cluster, nil
 }
 
-// getControlPlaneNodes returns control plane nodes
-func getControlPlaneNodes(cs *kubernetes.Clientset) ([]models.KubeNode, error) {
-	nodes, err := cs.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{
-		LabelSelector: "node-role.kubernetes.io/control-plane",
-	})
-	if err != nil {
-		return nil, err
-	}
-
-	var controlPlaneNodes []models.KubeNode
-	for _, node := range nodes.Items {
-		controlPlaneNodes = append(controlPlaneNodes, models.KubeNode{
-			Kubelet: node.Status.NodeInfo.KubeletVersion,
-			Runtime: node.Status.NodeInfo.ContainerRuntimeVersion,
-			Role:    "control-plane",
-		})
-	}
-
-	return controlPlaneNodes, nil
-}
-
-// getWorkerNodes returns worker nodes
-func getWorkerNodes(cs *kubernetes.Clientset) ([]models.KubeNode, error) {
-	nodes, err := cs.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
-	if err != nil {
-		return nil, err
-	}
-
-	var workerNodes []models.KubeNode
-	for _, node := range nodes.Items {
-		// Skip control plane nodes
-		if _, isControlPlane := node.Labels["node-role.kubernetes.io/control-plane"]; isControlPlane {
-			continue
-		}
-
-		workerNodes = append(workerNodes, models.KubeNode{
-			Kubelet: node.Status.NodeInfo.KubeletVersion,
-			Runtime: node.Status.NodeInfo.ContainerRuntimeVersion,
-			Role:    "worker",
-		})
-	}
-
-	return workerNodes, nil
-}
-
 // Helper functions for determining roles
 func getNodeRole(node *v1.Node) string {
 	if _, ok := node.Labels["node-role.kubernetes.io/control-plane"]; ok {
@@ -658,6 +833,28 @@ func getPodStatus(pod *v1.Pod) models.ResourceStatus {
 	}
 }
 
+// addPodRequests adds the requests and limits of a pod to the totals of its node. Terminal pods
+// are skipped as they no longer hold their requests.
+func addPodRequests(total *models.ResourceMetrics, pod *v1.Pod, metrics *models.ResourceMetrics) {
+	if isPodTerminal(pod) || metrics == nil {
+		return
+	}
+	total.CPURequest += metrics.CPURequest
+	total.MemoryRequest += metrics.MemoryRequest
+	total.CPULimit += metrics.CPULimit
+	total.MemoryLimit += metrics.MemoryLimit
+}
+
+// isPodTerminal reports whether a pod has finished and no longer holds node resources
+func isPodTerminal(pod *v1.Pod) bool {
+	return pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed
+}
+
+// isKubePodTerminal is isPodTerminal for a converted pod
+func isKubePodTerminal(pod *models.KubePod) bool {
+	return pod.Status.Phase == string(v1.PodSucceeded) || pod.Status.Phase == string(v1.PodFailed)
+}
+
 func isPodReady(pod *v1.Pod) bool {
 	for _, condition := range pod.Status.Conditions {
 		if condition.Type == v1.PodReady {
@@ -738,6 +935,8 @@ func getVolumeType(volume *v1.Volume) string {
 		return "projected"
 	} else if volume.DownwardAPI != nil {
 		return "downwardAPI"
+	} else if volume.Ephemeral != nil {
+		return "ephemeral"
 	}
 	return "unknown"
 }
@@ -761,6 +960,17 @@ func getNodeStatus(node *v1.Node) models.ResourceStatus {
 		phase = "NotReady"
 	}
 
+	// A ready node may still be under pressure or cordoned, which stops new pods landing on it
+	if ready {
+		if pressure := getNodePressure(node); len(pressure) > 0 {
+			reason = pressure[0]
+			message = fmt.Sprintf("node reports %s", strings.Join(pressure, ", "))
+		} else if node.Spec.Unschedulable {
+			reason = "SchedulingDisabled"
+			message = "node is cordoned"
+		}
+	}
+
 	return models.ResourceStatus{
 		Phase:       phase,
 		Ready:       ready,
@@ -770,6 +980,27 @@ func getNodeStatus(node *v1.Node) models.ResourceStatus {
 	}
 }
 
+// nodePressureConditions are the conditions that report trouble when True
+var nodePressureConditions = []v1.NodeConditionType{
+	v1.NodeMemoryPressure,
+	v1.NodeDiskPressure,
+	v1.NodePIDPressure,
+	v1.NodeNetworkUnavailable,
+}
+
+// getNodePressure returns the pressure conditions that are True on the node
+func getNodePressure(node *v1.Node) []string {
+	pressure := []string{}
+	for _, conditionType := range nodePressureConditions {
+		for _, condition := range node.Status.Conditions {
+			if condition.Type == conditionType && condition.Status == v1.ConditionTrue {
+				pressure = append(pressure, string(conditionType))
+			}
+		}
+	}
+	return pressure
+}
+
 func getNodeCapacity(capacity v1.ResourceList) models.NodeCapacity {
 	return models.NodeCapacity{
 		CPU:              capacity.Cpu().String(),
@@ -794,13 +1025,20 @@ func getNodeConditions(node *v1.Node) []models.NodeCondition {
 	return conditions
 }
 
+// getServiceStatus reports what the service spec and status alone tell: whether a LoadBalancer
+// is still waiting for its address. applyServiceBackends refines it from the service endpoints.
 func getServiceStatus(service *v1.Service) models.ResourceStatus {
-	// Services are generally always ready if they exist
-	return models.ResourceStatus{
-		Phase:       "Active",
-		Ready:       true,
-		LastUpdated: time.Now(),
-	}
+	status := models.ResourceStatus{Phase: "Active", Ready: true, LastUpdated: time.Now()}
+	switch {
+	case service.Spec.Type == v1.ServiceTypeExternalName:
+		status.Message = "resolves to " + service.Spec.ExternalName
+	case service.Spec.Type == v1.ServiceTypeLoadBalancer && len(service.Status.LoadBalancer.Ingress) == 0:
+		status.Phase = "Pending"
+		status.Ready = false
+		status.Reason = "LoadBalancerPending"
+		status.Message = "waiting for the load balancer to be provisioned"
+	}
+	return status
 }
 
 func getDeploymentStatus(deployment *appsv1.Deployment) models.ResourceStatus {
@@ -874,6 +1112,9 @@ func calculateClusterSummary(cluster *models.KubeCluster) models.ClusterSummary
 	summary.TotalNamespaces = len(cluster.Namespaces)
 	for _, namespace := range cluster.Namespaces {
 		summary.TotalDeployments += len(namespace.Deployments)
+		summary.TotalStatefulSets += len(namespace.StatefulSets)
+		summary.TotalDaemonSets += len(namespace.DaemonSets)
+		summary.TotalReplicaSets += len(namespace.ReplicaSets)
 		summary.TotalServices += len(namespace.Services)
 		summary.TotalPods += namespace.PodCount
 
@@ -887,9 +1128,5 @@ func calculateClusterSummary(cluster *models.KubeCluster) models.ClusterSummary
 		}
 	}
 
-	// Calculate utilization (placeholder values - real implementation would use metrics)
-	summary.CPUUtilization = 45.5    // percentage
-	summary.MemoryUtilization = 62.3 // percentage
-
 	return summary
 }
@@ -0,0 +1,193 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"kubey/api/internal/models"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// errMetricsUnavailable is returned when the metrics.k8s.io API is not served (metrics-server missing or down)
+var errMetricsUnavailable = errors.New("metrics API unavailable")
+
+// metricsAPIPath is the base path of the resource metrics API served by metrics-server
+const metricsAPIPath = "/apis/metrics.k8s.io/v1beta1"
+
+// resourceUsage is CPU and memory usage reported by metrics-server
+type resourceUsage struct {
+	CPU    float64 // In millicores
+	Memory int64   // In bytes
+}
+
+// metricsItem mirrors the fields of metrics.k8s.io NodeMetrics/PodMetrics that kubey uses
+type metricsItem struct {
+	Metadata   metav1.ObjectMeta `json:"metadata"`
+	Usage      v1.ResourceList   `json:"usage"`
+	Containers []struct {
+		Name  string          `json:"name"`
+		Usage v1.ResourceList `json:"usage"`
+	} `json:"containers"`
+}
+
+type metricsList struct {
+	Items []metricsItem `json:"items"`
+}
+
+// fetchNodeUsage returns usage per node name
+func fetchNodeUsage(ctx context.Context, cs kubernetes.Interface) (map[string]resourceUsage, error) {
+	list, err := fetchMetrics(ctx, cs, metricsAPIPath+"/nodes")
+	if err != nil {
+		return nil, err
+	}
+
+	usage := make(map[string]resourceUsage, len(list.Items))
+	for _, item := range list.Items {
+		usage[item.Metadata.Name] = toResourceUsage(item.Usage)
+	}
+	return usage, nil
+}
+
+// fetchPodUsage returns usage per "namespace/pod" and container name. An empty namespace lists all namespaces.
+func fetchPodUsage(ctx context.Context, cs kubernetes.Interface, namespace string) (map[string]map[string]resourceUsage, error) {
+	path := metricsAPIPath + "/pods"
+	if namespace != "" {
+		path = metricsAPIPath + "/namespaces/" + namespace + "/pods"
+	}
+
+	list, err := fetchMetrics(ctx, cs, path)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := make(map[string]map[string]resourceUsage, len(list.Items))
+	for _, item := range list.Items {
+		containers := make(map[string]resourceUsage, len(item.Containers))
+		for _, container := range item.Containers {
+			containers[container.Name] = toResourceUsage(container.Usage)
+		}
+		usage[item.Metadata.Namespace+"/"+item.Metadata.Name] = containers
+	}
+	return usage, nil
+}
+
+func fetchMetrics(ctx context.Context, cs kubernetes.Interface, path string) (*metricsList, error) {
+	body, err := cs.CoreV1().RESTClient().Get().AbsPath(path).DoRaw(ctx)
+	if err != nil {
+		if apierrors.IsNotFound(err) || apierrors.IsServiceUnavailable(err) || apierrors.IsMethodNotSupported(err) {
+			return nil, errMetricsUnavailable
+		}
+		return nil, fmt.Errorf("failed to query metrics API: %v", err)
+	}
+
+	var list metricsList
+	if err := json.Unmarshal(body, &list); err != nil {
+		return nil, fmt.Errorf("failed to decode metrics: %v", err)
+	}
+	return &list, nil
+}
+
+func toResourceUsage(usage v1.ResourceList) resourceUsage {
+	return resourceUsage{
+		CPU:    float64(usage.Cpu().MilliValue()),
+		Memory: usage.Memory().Value(),
+	}
+}
+
+// getContainerMetrics combines a container's requests and limits with its usage, if known
+func getContainerMetrics(container *v1.Container, usage *resourceUsage) *models.ResourceMetrics {
+	metrics := &models.ResourceMetrics{
+		CPURequest:    float64(container.Resources.Requests.Cpu().MilliValue()),
+		MemoryRequest: container.Resources.Requests.Memory().Value(),
+		CPULimit:      float64(container.Resources.Limits.Cpu().MilliValue()),
+		MemoryLimit:   container.Resources.Limits.Memory().Value(),
+	}
+	if usage != nil {
+		metrics.CPUUsage = usage.CPU
+		metrics.MemoryUsage = usage.Memory
+		metrics.UsageAvailable = true
+	}
+	return metrics
+}
+
+// getPodMetrics returns the effective requests and limits of a pod as the scheduler reserves
+// them, with the summed usage of its running containers. usage is nil when metrics are
+// unavailable for the pod.
+//
+// Regular containers and restartable init containers (sidecars) run together, so their
+// resources add up. Other init containers run one at a time before them, alongside the
+// sidecars started earlier, so the pod needs the largest of those phases. Spec.Overhead
+// is added on top.
+func getPodMetrics(pod *v1.Pod, usage map[string]resourceUsage) *models.ResourceMetrics {
+	metrics := &models.ResourceMetrics{UsageAvailable: usage != nil}
+	containerMetrics := func(container *v1.Container) *models.ResourceMetrics {
+		var containerUsage *resourceUsage
+		if u, ok := usage[container.Name]; ok {
+			containerUsage = &u
+		}
+		return getContainerMetrics(container, containerUsage)
+	}
+
+	for i := range pod.Spec.Containers {
+		addMetrics(metrics, containerMetrics(&pod.Spec.Containers[i]))
+	}
+
+	sidecars, initPhase := &models.ResourceMetrics{}, &models.ResourceMetrics{}
+	for i := range pod.Spec.InitContainers {
+		container := &pod.Spec.InitContainers[i]
+		phase := *sidecars
+		if isSidecar(container) {
+			sidecar := containerMetrics(container)
+			addMetrics(metrics, sidecar)
+			addMetrics(sidecars, sidecar)
+			phase = *sidecars
+		} else {
+			addMetrics(&phase, getContainerMetrics(container, nil))
+		}
+		maxMetrics(initPhase, &phase)
+	}
+	maxMetrics(metrics, initPhase)
+
+	if pod.Spec.Overhead != nil {
+		overhead := getContainerMetrics(&v1.Container{Resources: v1.ResourceRequirements{Requests: pod.Spec.Overhead}}, nil)
+		metrics.CPURequest += overhead.CPURequest
+		metrics.MemoryRequest += overhead.MemoryRequest
+		// Like the scheduler, overhead only raises limits that are set
+		if metrics.CPULimit > 0 {
+			metrics.CPULimit += overhead.CPURequest
+		}
+		if metrics.MemoryLimit > 0 {
+			metrics.MemoryLimit += overhead.MemoryRequest
+		}
+	}
+	return metrics
+}
+
+// isSidecar reports whether an init container keeps running alongside the regular containers
+func isSidecar(container *v1.Container) bool {
+	return container.RestartPolicy != nil && *container.RestartPolicy == v1.ContainerRestartPolicyAlways
+}
+
+// maxMetrics raises the requests and limits of a to those of b where b's are larger
+func maxMetrics(a, b *models.ResourceMetrics) {
+	a.CPURequest = max(a.CPURequest, b.CPURequest)
+	a.MemoryRequest = max(a.MemoryRequest, b.MemoryRequest)
+	a.CPULimit = max(a.CPULimit, b.CPULimit)
+	a.MemoryLimit = max(a.MemoryLimit, b.MemoryLimit)
+}
+
+// addMetrics adds the requests, limits and usage of b to a
+func addMetrics(a, b *models.ResourceMetrics) {
+	a.CPUUsage += b.CPUUsage
+	a.MemoryUsage += b.MemoryUsage
+	a.CPURequest += b.CPURequest
+	a.MemoryRequest += b.MemoryRequest
+	a.CPULimit += b.CPULimit
+	a.MemoryLimit += b.MemoryLimit
+}
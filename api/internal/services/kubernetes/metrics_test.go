@@ -0,0 +1,79 @@
+package kubernetes
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestGetPodMetrics(t *testing.T) {
+	pod := &v1.Pod{
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{
+					Name: "app",
+					Resources: v1.ResourceRequirements{
+						Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("250m"), v1.ResourceMemory: resource.MustParse("128Mi")},
+						Limits:   v1.ResourceList{v1.ResourceCPU: resource.MustParse("1"), v1.ResourceMemory: resource.MustParse("256Mi")},
+					},
+				},
+				{
+					Name: "sidecar",
+					Resources: v1.ResourceRequirements{
+						Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("50m")},
+					},
+				},
+			},
+		},
+	}
+
+	withoutUsage := getPodMetrics(pod, nil)
+	if withoutUsage.UsageAvailable {
+		t.Fatalf("expected usage to be unavailable without metrics")
+	}
+	if withoutUsage.CPURequest != 300 || withoutUsage.CPULimit != 1000 || withoutUsage.MemoryRequest != 128<<20 || withoutUsage.MemoryLimit != 256<<20 {
+		t.Fatalf("unexpected requests/limits: %+v", withoutUsage)
+	}
+
+	withUsage := getPodMetrics(pod, map[string]resourceUsage{
+		"app":     {CPU: 120, Memory: 100 << 20},
+		"sidecar": {CPU: 5, Memory: 10 << 20},
+	})
+	if !withUsage.UsageAvailable || withUsage.CPUUsage != 125 || withUsage.MemoryUsage != 110<<20 {
+		t.Fatalf("unexpected usage: %+v", withUsage)
+	}
+}
+
+func TestGetPodMetricsEffectiveRequests(t *testing.T) {
+	requests := func(cpu, memory string) v1.ResourceRequirements {
+		return v1.ResourceRequirements{Requests: v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse(cpu),
+			v1.ResourceMemory: resource.MustParse(memory),
+		}}
+	}
+	always := v1.ContainerRestartPolicyAlways
+	pod := &v1.Pod{
+		Spec: v1.PodSpec{
+			InitContainers: []v1.Container{
+				{Name: "proxy", RestartPolicy: &always, Resources: requests("100m", "64Mi")},
+				// Runs alongside the proxy started before it: 1100m, 192Mi
+				{Name: "migrate", Resources: requests("1", "128Mi")},
+			},
+			Containers: []v1.Container{{Name: "app", Resources: requests("500m", "512Mi")}},
+			Overhead:   v1.ResourceList{v1.ResourceCPU: resource.MustParse("10m"), v1.ResourceMemory: resource.MustParse("16Mi")},
+		},
+	}
+
+	// CPU: max(1100m init phase, 500m app + 100m proxy) + 10m; memory: max(192Mi, 576Mi) + 16Mi
+	metrics := getPodMetrics(pod, map[string]resourceUsage{"app": {CPU: 200}, "proxy": {CPU: 20}})
+	if metrics.CPURequest != 1110 || metrics.MemoryRequest != 592<<20 {
+		t.Fatalf("unexpected effective requests: %+v", metrics)
+	}
+	if metrics.CPULimit != 0 || metrics.MemoryLimit != 0 {
+		t.Fatalf("expected overhead not to add limits that are unset: %+v", metrics)
+	}
+	if metrics.CPUUsage != 220 {
+		t.Fatalf("expected sidecar usage to count, got %v", metrics.CPUUsage)
+	}
+}
@@ -0,0 +1,708 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"kubey/api/internal/models"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+)
+
+// defaultIngressClassAnnotation marks the ingress class used by ingresses without a class
+const defaultIngressClassAnnotation = "ingressclass.kubernetes.io/is-default-class"
+
+// gatewayAPIGroup is the API group of the Gateway API resources
+const gatewayAPIGroup = "gateway.networking.k8s.io"
+
+// gatewayAPIVersions are the Gateway API versions kubey can decode, in order of preference
+var gatewayAPIVersions = []string{"v1", "v1beta1"}
+
+// errGatewayAPINotInstalled is returned when the cluster does not serve the Gateway API
+var errGatewayAPINotInstalled = fmt.Errorf("%w: the Gateway API is not installed in this cluster", ErrResourceNotFound)
+
+// gatewayParentRef mirrors the Gateway API ParentReference
+type gatewayParentRef struct {
+	Name        string  `json:"name"`
+	Namespace   *string `json:"namespace"`
+	SectionName *string `json:"sectionName"`
+}
+
+// gatewayObject mirrors the fields of a Gateway API Gateway that kubey uses
+type gatewayObject struct {
+	Metadata metav1.ObjectMeta `json:"metadata"`
+	Spec     struct {
+		GatewayClassName string `json:"gatewayClassName"`
+		Listeners        []struct {
+			Name     string  `json:"name"`
+			Hostname *string `json:"hostname"`
+			Port     int32   `json:"port"`
+			Protocol string  `json:"protocol"`
+			TLS      *struct {
+				CertificateRefs []struct {
+					Name      string  `json:"name"`
+					Namespace *string `json:"namespace"`
+				} `json:"certificateRefs"`
+			} `json:"tls"`
+		} `json:"listeners"`
+	} `json:"spec"`
+	Status struct {
+		Addresses []struct {
+			Value string `json:"value"`
+		} `json:"addresses"`
+		Conditions []metav1.Condition `json:"conditions"`
+		Listeners  []struct {
+			Name           string `json:"name"`
+			AttachedRoutes int32  `json:"attachedRoutes"`
+		} `json:"listeners"`
+	} `json:"status"`
+}
+
+// httpRouteObject mirrors the fields of a Gateway API HTTPRoute that kubey uses
+type httpRouteObject struct {
+	Metadata metav1.ObjectMeta `json:"metadata"`
+	Spec     struct {
+		ParentRefs []gatewayParentRef `json:"parentRefs"`
+		Hostnames  []string           `json:"hostnames"`
+		Rules      []struct {
+			Matches []struct {
+				Path *struct {
+					Type  *string `json:"type"`
+					Value *string `json:"value"`
+				} `json:"path"`
+				Method *string `json:"method"`
+			} `json:"matches"`
+			BackendRefs []struct {
+				Kind      *string `json:"kind"`
+				Name      string  `json:"name"`
+				Namespace *string `json:"namespace"`
+				Port      *int32  `json:"port"`
+				Weight    *int32  `json:"weight"`
+			} `json:"backendRefs"`
+		} `json:"rules"`
+	} `json:"spec"`
+	Status struct {
+		Parents []struct {
+			ParentRef  gatewayParentRef   `json:"parentRef"`
+			Conditions []metav1.Condition `json:"conditions"`
+		} `json:"parents"`
+	} `json:"status"`
+}
+
+// GetIngresses returns the ingresses of a cluster with their backends linked to services.
+// An empty namespace lists all namespaces.
+func GetIngresses(clusterID, namespace string) ([]models.KubeIngress, error) {
+	cs, err := getClientsetForCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := cs.NetworkingV1().Ingresses(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ingresses: %v", err)
+	}
+	services, err := getServiceIndex(context.TODO(), cs, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	ingresses := []models.KubeIngress{}
+	for i := range list.Items {
+		ingresses = append(ingresses, toKubeIngress(&list.Items[i], services))
+	}
+	return ingresses, nil
+}
+
+// GetIngress returns a single ingress with its backends linked to services
+func GetIngress(clusterID, namespace, name string) (*models.KubeIngress, error) {
+	cs, err := getClientsetForCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	ingress, err := cs.NetworkingV1().Ingresses(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, getError(err, "ingress", namespace, name)
+	}
+	services, err := getServiceIndex(context.TODO(), cs, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	kubeIngress := toKubeIngress(ingress, services)
+	return &kubeIngress, nil
+}
+
+// GetIngressClasses returns the ingress classes of a cluster
+func GetIngressClasses(clusterID string) ([]models.KubeIngressClass, error) {
+	cs, err := getClientsetForCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := cs.NetworkingV1().IngressClasses().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ingress classes: %v", err)
+	}
+
+	classes := []models.KubeIngressClass{}
+	for _, class := range list.Items {
+		kubeClass := models.KubeIngressClass{
+			Name:       class.Name,
+			Controller: class.Spec.Controller,
+			Default:    class.Annotations[defaultIngressClassAnnotation] == "true",
+			Labels:     class.Labels,
+			CreatedAt:  class.CreationTimestamp.Time,
+		}
+		if params := class.Spec.Parameters; params != nil {
+			kubeClass.Parameters = params.Kind + "/" + params.Name
+		}
+		classes = append(classes, kubeClass)
+	}
+	return classes, nil
+}
+
+// GetGateways returns the Gateway API gateways of a cluster. An empty namespace lists all namespaces.
+func GetGateways(clusterID, namespace string) ([]models.KubeGateway, error) {
+	items, err := listGatewayAPI(clusterID, "gateways", namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	gateways := []models.KubeGateway{}
+	for i := range items {
+		var gw gatewayObject
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(items[i].Object, &gw); err != nil {
+			return nil, fmt.Errorf("failed to decode gateway %s/%s: %v", items[i].GetNamespace(), items[i].GetName(), err)
+		}
+		gateways = append(gateways, toKubeGateway(&gw))
+	}
+	return gateways, nil
+}
+
+// GetGateway returns a single Gateway API gateway
+func GetGateway(clusterID, namespace, name string) (*models.KubeGateway, error) {
+	item, err := getGatewayAPI(clusterID, "gateways", namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var gw gatewayObject
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, &gw); err != nil {
+		return nil, fmt.Errorf("failed to decode gateway %s/%s: %v", namespace, name, err)
+	}
+	gateway := toKubeGateway(&gw)
+	return &gateway, nil
+}
+
+// GetHTTPRoutes returns the Gateway API HTTP routes of a cluster with their backends linked to services.
+// An empty namespace lists all namespaces.
+func GetHTTPRoutes(clusterID, namespace string) ([]models.KubeHTTPRoute, error) {
+	items, err := listGatewayAPI(clusterID, "httproutes", namespace)
+	if err != nil {
+		return nil, err
+	}
+	services, err := getClusterServiceIndex(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	routes := []models.KubeHTTPRoute{}
+	for i := range items {
+		var route httpRouteObject
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(items[i].Object, &route); err != nil {
+			return nil, fmt.Errorf("failed to decode httproute %s/%s: %v", items[i].GetNamespace(), items[i].GetName(), err)
+		}
+		routes = append(routes, toKubeHTTPRoute(&route, services))
+	}
+	return routes, nil
+}
+
+// GetHTTPRoute returns a single Gateway API HTTP route with its backends linked to services
+func GetHTTPRoute(clusterID, namespace, name string) (*models.KubeHTTPRoute, error) {
+	item, err := getGatewayAPI(clusterID, "httproutes", namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	services, err := getClusterServiceIndex(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	var route httpRouteObject
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, &route); err != nil {
+		return nil, fmt.Errorf("failed to decode httproute %s/%s: %v", namespace, name, err)
+	}
+	kubeRoute := toKubeHTTPRoute(&route, services)
+	return &kubeRoute, nil
+}
+
+func listGatewayAPI(clusterID, resource, namespace string) ([]unstructured.Unstructured, error) {
+	gvr, err := gatewayAPIResource(clusterID, resource)
+	if err != nil {
+		return nil, err
+	}
+	client, err := getDynamicClientForCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := client.Resource(gvr).Namespace(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, errGatewayAPINotInstalled
+		}
+		return nil, fmt.Errorf("failed to list %s: %v", resource, err)
+	}
+	return list.Items, nil
+}
+
+func getGatewayAPI(clusterID, resource, namespace, name string) (*unstructured.Unstructured, error) {
+	gvr, err := gatewayAPIResource(clusterID, resource)
+	if err != nil {
+		return nil, err
+	}
+	client, err := getDynamicClientForCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := client.Resource(gvr).Namespace(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, getError(err, strings.TrimSuffix(resource, "s"), namespace, name)
+	}
+	return item, nil
+}
+
+// gatewayAPIResource resolves a Gateway API resource to the version the cluster serves,
+// as clusters on older Gateway API releases only serve v1beta1
+func gatewayAPIResource(clusterID, resource string) (schema.GroupVersionResource, error) {
+	dc, err := getDiscoveryForCluster(clusterID)
+	if err != nil {
+		return schema.GroupVersionResource{}, err
+	}
+	groups, err := dc.ServerGroups()
+	if err != nil {
+		return schema.GroupVersionResource{}, fmt.Errorf("failed to discover API groups: %v", err)
+	}
+
+	version := ""
+	for _, group := range groups.Groups {
+		if group.Name == gatewayAPIGroup {
+			version = servedGatewayAPIVersion(group)
+			break
+		}
+	}
+	if version == "" {
+		return schema.GroupVersionResource{}, errGatewayAPINotInstalled
+	}
+	return schema.GroupVersionResource{Group: gatewayAPIGroup, Version: version, Resource: resource}, nil
+}
+
+// servedGatewayAPIVersion returns the group's preferred version when kubey can decode it,
+// otherwise the first supported version the group serves
+func servedGatewayAPIVersion(group metav1.APIGroup) string {
+	served := map[string]bool{}
+	for _, version := range group.Versions {
+		served[version.Version] = true
+	}
+	if slices.Contains(gatewayAPIVersions, group.PreferredVersion.Version) {
+		return group.PreferredVersion.Version
+	}
+	for _, version := range gatewayAPIVersions {
+		if served[version] {
+			return version
+		}
+	}
+	return ""
+}
+
+// getClusterServiceIndex returns every service of a cluster by "namespace/name"
+func getClusterServiceIndex(clusterID string) (map[string]*models.KubeService, error) {
+	cs, err := getClientsetForCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	return getServiceIndex(context.TODO(), cs, "")
+}
+
+// getServiceIndex returns the services of a namespace, or all namespaces, by "namespace/name",
+// with the endpoints and pods behind them
+func getServiceIndex(ctx context.Context, cs kubernetes.Interface, namespace string) (map[string]*models.KubeService, error) {
+	list, err := cs.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list services: %v", err)
+	}
+
+	backends, err := getServiceBackends(ctx, cs, namespace)
+	if err != nil {
+		log.Printf("Service endpoints unavailable: %v", err)
+	}
+
+	services := make(map[string]*models.KubeService, len(list.Items))
+	for i := range list.Items {
+		service := toKubeService(&list.Items[i])
+		applyServiceBackends(&service, backends)
+		services[service.Namespace+"/"+service.Name] = &service
+	}
+	return services, nil
+}
+
+// resolveBackend links a backend to its service in the index, if it exists
+func resolveBackend(services map[string]*models.KubeService, namespace, name, port string, weight *int32) models.BackendRef {
+	return models.BackendRef{
+		Name:      name,
+		Namespace: namespace,
+		Port:      port,
+		Weight:    weight,
+		Service:   services[namespace+"/"+name],
+	}
+}
+
+// tlsCovers reports whether a TLS host matches host, either exactly or as a wildcard
+// such as *.example.com, which covers a single DNS label
+func tlsCovers(tlsHosts map[string]bool, host string) bool {
+	if host == "" {
+		return false
+	}
+	if tlsHosts[host] {
+		return true
+	}
+	if _, domain, ok := strings.Cut(host, "."); ok {
+		return tlsHosts["*."+domain]
+	}
+	return false
+}
+
+// missingBackends lists the backends whose service does not exist as "namespace/name"
+func missingBackends(backends []models.BackendRef) []string {
+	var missing []string
+	seen := map[string]bool{}
+	for _, backend := range backends {
+		key := backend.Namespace + "/" + backend.Name
+		if backend.Service == nil && !seen[key] {
+			seen[key] = true
+			missing = append(missing, key)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+func toKubeIngress(ingress *networkingv1.Ingress, services map[string]*models.KubeService) models.KubeIngress {
+	kubeIngress := models.KubeIngress{
+		Name:      ingress.Name,
+		Namespace: ingress.Namespace,
+		Rules:     []models.IngressRule{},
+		TLS:       []models.IngressTLS{},
+		Addresses: []string{},
+		Labels:    ingress.Labels,
+		CreatedAt: ingress.CreationTimestamp.Time,
+	}
+	if ingress.Spec.IngressClassName != nil {
+		kubeIngress.ClassName = *ingress.Spec.IngressClassName
+	}
+	for _, lb := range ingress.Status.LoadBalancer.Ingress {
+		if lb.IP != "" {
+			kubeIngress.Addresses = append(kubeIngress.Addresses, lb.IP)
+		} else if lb.Hostname != "" {
+			kubeIngress.Addresses = append(kubeIngress.Addresses, lb.Hostname)
+		}
+	}
+
+	tlsHosts := map[string]bool{}
+	tlsWildcard := false
+	for _, tls := range ingress.Spec.TLS {
+		kubeIngress.TLS = append(kubeIngress.TLS, models.IngressTLS{Hosts: tls.Hosts, SecretName: tls.SecretName})
+		if len(tls.Hosts) == 0 {
+			tlsWildcard = true
+		}
+		for _, host := range tls.Hosts {
+			tlsHosts[host] = true
+		}
+	}
+
+	var backends []models.BackendRef
+	if backend := ingress.Spec.DefaultBackend; backend != nil && backend.Service != nil {
+		ref := resolveBackend(services, ingress.Namespace, backend.Service.Name, ingressBackendPort(backend.Service.Port), nil)
+		kubeIngress.DefaultBackend = &ref
+		backends = append(backends, ref)
+	}
+
+	for _, rule := range ingress.Spec.Rules {
+		kubeRule := models.IngressRule{Host: rule.Host, Paths: []models.IngressPath{}}
+		if rule.HTTP == nil {
+			kubeIngress.Rules = append(kubeIngress.Rules, kubeRule)
+			continue
+		}
+
+		host := rule.Host
+		if host == "" && len(kubeIngress.Addresses) > 0 {
+			host = kubeIngress.Addresses[0]
+		}
+		scheme := "http"
+		if tlsWildcard || tlsCovers(tlsHosts, rule.Host) {
+			scheme = "https"
+		}
+
+		for _, path := range rule.HTTP.Paths {
+			kubePath := models.IngressPath{Path: path.Path}
+			if kubePath.Path == "" {
+				kubePath.Path = "/"
+			}
+			if path.PathType != nil {
+				kubePath.PathType = string(*path.PathType)
+			}
+			// A wildcard host matches many names and has no single URL
+			if host != "" && !strings.HasPrefix(host, "*") {
+				kubePath.URL = scheme + "://" + host + kubePath.Path
+			}
+			if path.Backend.Service != nil {
+				kubePath.Backend = resolveBackend(services, ingress.Namespace, path.Backend.Service.Name, ingressBackendPort(path.Backend.Service.Port), nil)
+				backends = append(backends, kubePath.Backend)
+			}
+			kubeRule.Paths = append(kubeRule.Paths, kubePath)
+		}
+		kubeIngress.Rules = append(kubeIngress.Rules, kubeRule)
+	}
+
+	kubeIngress.Status = getIngressStatus(len(kubeIngress.Addresses) > 0, missingBackends(backends))
+	return kubeIngress
+}
+
+func ingressBackendPort(port networkingv1.ServiceBackendPort) string {
+	if port.Name != "" {
+		return port.Name
+	}
+	if port.Number != 0 {
+		return strconv.Itoa(int(port.Number))
+	}
+	return ""
+}
+
+// getIngressStatus reports an ingress as Degraded when a backend service is missing and
+// Pending until the ingress controller has assigned it an address
+func getIngressStatus(hasAddress bool, missing []string) models.ResourceStatus {
+	status := models.ResourceStatus{Phase: "Active", Ready: true, LastUpdated: time.Now()}
+	switch {
+	case len(missing) > 0:
+		status.Phase = "Degraded"
+		status.Ready = false
+		status.Reason = "BackendNotFound"
+		status.Message = "missing services: " + strings.Join(missing, ", ")
+	case !hasAddress:
+		status.Phase = "Pending"
+		status.Ready = false
+		status.Reason = "AddressNotAssigned"
+		status.Message = "the ingress controller has not assigned an address"
+	}
+	return status
+}
+
+func toKubeGateway(gw *gatewayObject) models.KubeGateway {
+	gateway := models.KubeGateway{
+		Name:      gw.Metadata.Name,
+		Namespace: gw.Metadata.Namespace,
+		ClassName: gw.Spec.GatewayClassName,
+		Listeners: []models.GatewayListener{},
+		Addresses: []string{},
+		Labels:    gw.Metadata.Labels,
+		CreatedAt: gw.Metadata.CreationTimestamp.Time,
+		Status:    getGatewayStatus(gw.Status.Conditions),
+	}
+	for _, address := range gw.Status.Addresses {
+		gateway.Addresses = append(gateway.Addresses, address.Value)
+	}
+
+	attached := map[string]int32{}
+	for _, listener := range gw.Status.Listeners {
+		attached[listener.Name] = listener.AttachedRoutes
+	}
+	for _, listener := range gw.Spec.Listeners {
+		kubeListener := models.GatewayListener{
+			Name:           listener.Name,
+			Port:           listener.Port,
+			Protocol:       listener.Protocol,
+			AttachedRoutes: attached[listener.Name],
+		}
+		if listener.Hostname != nil {
+			kubeListener.Hostname = *listener.Hostname
+		}
+		if listener.TLS != nil {
+			for _, ref := range listener.TLS.CertificateRefs {
+				namespace := gateway.Namespace
+				if ref.Namespace != nil {
+					namespace = *ref.Namespace
+				}
+				kubeListener.TLSSecrets = append(kubeListener.TLSSecrets, namespace+"/"+ref.Name)
+			}
+		}
+		gateway.Listeners = append(gateway.Listeners, kubeListener)
+	}
+	return gateway
+}
+
+// getGatewayStatus derives a gateway's status from its Accepted and Programmed conditions
+func getGatewayStatus(conditions []metav1.Condition) models.ResourceStatus {
+	status := models.ResourceStatus{Phase: "Pending", LastUpdated: time.Now()}
+
+	if accepted := findCondition(conditions, "Accepted"); accepted != nil && accepted.Status == metav1.ConditionFalse {
+		status.Phase = "NotAccepted"
+		status.Reason = accepted.Reason
+		status.Message = accepted.Message
+		return status
+	}
+	if programmed := findCondition(conditions, "Programmed"); programmed != nil {
+		status.Reason = programmed.Reason
+		status.Message = programmed.Message
+		if programmed.Status == metav1.ConditionTrue {
+			status.Phase = "Programmed"
+			status.Ready = true
+		}
+	}
+	return status
+}
+
+func toKubeHTTPRoute(route *httpRouteObject, services map[string]*models.KubeService) models.KubeHTTPRoute {
+	kubeRoute := models.KubeHTTPRoute{
+		Name:       route.Metadata.Name,
+		Namespace:  route.Metadata.Namespace,
+		Hostnames:  route.Spec.Hostnames,
+		ParentRefs: []models.RouteParentRef{},
+		Rules:      []models.HTTPRouteRule{},
+		Labels:     route.Metadata.Labels,
+		CreatedAt:  route.Metadata.CreationTimestamp.Time,
+	}
+	if kubeRoute.Hostnames == nil {
+		kubeRoute.Hostnames = []string{}
+	}
+
+	parentKey := func(ref gatewayParentRef) string {
+		namespace := kubeRoute.Namespace
+		if ref.Namespace != nil {
+			namespace = *ref.Namespace
+		}
+		section := ""
+		if ref.SectionName != nil {
+			section = *ref.SectionName
+		}
+		return namespace + "/" + ref.Name + "/" + section
+	}
+
+	accepted := map[string]bool{}
+	var notAccepted, unresolved *metav1.Condition
+	for _, parent := range route.Status.Parents {
+		if condition := findCondition(parent.Conditions, "Accepted"); condition != nil {
+			if condition.Status == metav1.ConditionTrue {
+				accepted[parentKey(parent.ParentRef)] = true
+			} else if notAccepted == nil {
+				notAccepted = condition
+			}
+		}
+		if condition := findCondition(parent.Conditions, "ResolvedRefs"); condition != nil && condition.Status == metav1.ConditionFalse && unresolved == nil {
+			unresolved = condition
+		}
+	}
+
+	for _, ref := range route.Spec.ParentRefs {
+		parent := models.RouteParentRef{Name: ref.Name, Namespace: kubeRoute.Namespace, Accepted: accepted[parentKey(ref)]}
+		if ref.Namespace != nil {
+			parent.Namespace = *ref.Namespace
+		}
+		if ref.SectionName != nil {
+			parent.SectionName = *ref.SectionName
+		}
+		kubeRoute.ParentRefs = append(kubeRoute.ParentRefs, parent)
+	}
+
+	var backends []models.BackendRef
+	for _, rule := range route.Spec.Rules {
+		kubeRule := models.HTTPRouteRule{Matches: []models.HTTPRouteMatch{}, Backends: []models.BackendRef{}}
+		for _, match := range rule.Matches {
+			kubeMatch := models.HTTPRouteMatch{}
+			if match.Path != nil {
+				if match.Path.Value != nil {
+					kubeMatch.Path = *match.Path.Value
+				}
+				if match.Path.Type != nil {
+					kubeMatch.PathType = *match.Path.Type
+				}
+			}
+			if match.Method != nil {
+				kubeMatch.Method = *match.Method
+			}
+			kubeRule.Matches = append(kubeRule.Matches, kubeMatch)
+		}
+		for _, ref := range rule.BackendRefs {
+			// Only Service backends (the default kind) can be linked
+			if ref.Kind != nil && *ref.Kind != "Service" {
+				continue
+			}
+			namespace := kubeRoute.Namespace
+			if ref.Namespace != nil {
+				namespace = *ref.Namespace
+			}
+			port := ""
+			if ref.Port != nil {
+				port = strconv.Itoa(int(*ref.Port))
+			}
+			backend := resolveBackend(services, namespace, ref.Name, port, ref.Weight)
+			kubeRule.Backends = append(kubeRule.Backends, backend)
+			backends = append(backends, backend)
+		}
+		kubeRoute.Rules = append(kubeRoute.Rules, kubeRule)
+	}
+
+	kubeRoute.Status = getHTTPRouteStatus(len(route.Status.Parents) > 0, notAccepted, unresolved, missingBackends(backends))
+	return kubeRoute
+}
+
+// getHTTPRouteStatus reports whether every parent gateway accepted the route and all backends resolved
+func getHTTPRouteStatus(hasParents bool, notAccepted, unresolved *metav1.Condition, missing []string) models.ResourceStatus {
+	status := models.ResourceStatus{Phase: "Accepted", Ready: true, LastUpdated: time.Now()}
+	switch {
+	case notAccepted != nil:
+		status.Phase = "NotAccepted"
+		status.Ready = false
+		status.Reason = notAccepted.Reason
+		status.Message = notAccepted.Message
+	case len(missing) > 0:
+		status.Phase = "Degraded"
+		status.Ready = false
+		status.Reason = "BackendNotFound"
+		status.Message = "missing services: " + strings.Join(missing, ", ")
+	case unresolved != nil:
+		status.Phase = "Degraded"
+		status.Ready = false
+		status.Reason = unresolved.Reason
+		status.Message = unresolved.Message
+	case !hasParents:
+		status.Phase = "Pending"
+		status.Ready = false
+		status.Reason = "NotAttached"
+		status.Message = "no gateway has reported on this route"
+	}
+	return status
+}
+
+// findCondition returns the condition of the given type, or nil
+func findCondition(conditions []metav1.Condition, conditionType string) *metav1.Condition {
+	for i := range conditions {
+		if conditions[i].Type == conditionType {
+			return &conditions[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package kubernetes
+
+import (
+	"encoding/json"
+	"testing"
+
+	"kubey/api/internal/models"
+
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestToKubeIngress(t *testing.T) {
+	prefix := networkingv1.PathTypePrefix
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "shop"},
+		Spec: networkingv1.IngressSpec{
+			TLS: []networkingv1.IngressTLS{{Hosts: []string{"shop.example.com"}, SecretName: "shop-tls"}},
+			Rules: []networkingv1.IngressRule{{
+				Host: "shop.example.com",
+				IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{
+					Paths: []networkingv1.HTTPIngressPath{
+						{Path: "/api", PathType: &prefix, Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{
+							Name: "api", Port: networkingv1.ServiceBackendPort{Number: 8080},
+						}}},
+						{Path: "/", PathType: &prefix, Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{
+							Name: "web", Port: networkingv1.ServiceBackendPort{Name: "http"},
+						}}},
+					},
+				}},
+			}},
+		},
+		Status: networkingv1.IngressStatus{LoadBalancer: networkingv1.IngressLoadBalancerStatus{
+			Ingress: []networkingv1.IngressLoadBalancerIngress{{IP: "203.0.113.10"}},
+		}},
+	}
+	api := toKubeService(&v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "shop"}})
+	services := map[string]*models.KubeService{"shop/api": &api}
+
+	kubeIngress := toKubeIngress(ingress, services)
+	paths := kubeIngress.Rules[0].Paths
+	if paths[0].URL != "https://shop.example.com/api" || paths[0].Backend.Port != "8080" || paths[0].Backend.Service == nil {
+		t.Fatalf("unexpected linked path: %+v", paths[0])
+	}
+	if paths[1].Backend.Port != "http" || paths[1].Backend.Service != nil {
+		t.Fatalf("expected an unlinked backend for the missing service, got %+v", paths[1].Backend)
+	}
+	if s := kubeIngress.Status; s.Phase != "Degraded" || s.Reason != "BackendNotFound" || s.Message != "missing services: shop/web" {
+		t.Fatalf("unexpected ingress status: %+v", s)
+	}
+
+	ingress.Spec.TLS[0].Hosts = []string{"*.example.com"}
+	if url := toKubeIngress(ingress, services).Rules[0].Paths[0].URL; url != "https://shop.example.com/api" {
+		t.Fatalf("expected a wildcard TLS host to cover shop.example.com, got %q", url)
+	}
+	if tlsCovers(map[string]bool{"*.example.com": true}, "a.b.example.com") {
+		t.Fatalf("expected a wildcard TLS host to cover only one label")
+	}
+	ingress.Spec.TLS[0].Hosts = []string{"shop.example.com"}
+
+	ingress.Spec.Rules[0].Host = "*.example.com"
+	if url := toKubeIngress(ingress, services).Rules[0].Paths[0].URL; url != "" {
+		t.Fatalf("expected no URL for a wildcard host, got %q", url)
+	}
+	ingress.Spec.Rules[0].Host = "shop.example.com"
+
+	web := toKubeService(&v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"}})
+	services["shop/web"] = &web
+	if s := toKubeIngress(ingress, services).Status; !s.Ready || s.Phase != "Active" {
+		t.Fatalf("expected an active ingress, got %+v", s)
+	}
+}
+
+func TestServedGatewayAPIVersion(t *testing.T) {
+	group := func(preferred string, versions ...string) metav1.APIGroup {
+		g := metav1.APIGroup{Name: gatewayAPIGroup, PreferredVersion: metav1.GroupVersionForDiscovery{Version: preferred}}
+		for _, v := range versions {
+			g.Versions = append(g.Versions, metav1.GroupVersionForDiscovery{Version: v})
+		}
+		return g
+	}
+	tests := []struct {
+		name  string
+		group metav1.APIGroup
+		want  string
+	}{
+		{"v1", group("v1", "v1", "v1beta1"), "v1"},
+		{"v1beta1 only", group("v1beta1", "v1beta1", "v1alpha2"), "v1beta1"},
+		{"unsupported preferred", group("v2", "v2", "v1"), "v1"},
+		{"alpha only", group("v1alpha2", "v1alpha2"), ""},
+	}
+	for _, tt := range tests {
+		if got := servedGatewayAPIVersion(tt.group); got != tt.want {
+			t.Errorf("%s: servedGatewayAPIVersion() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestToKubeHTTPRoute(t *testing.T) {
+	raw := `{
+	  "metadata": {"name": "shop", "namespace": "shop"},
+	  "spec": {
+	    "parentRefs": [{"name": "public", "namespace": "gateways", "sectionName": "https"}],
+	    "hostnames": ["shop.example.com"],
+	    "rules": [{
+	      "matches": [{"path": {"type": "PathPrefix", "value": "/api"}, "method": "GET"}],
+	      "backendRefs": [
+	        {"name": "api", "port": 8080, "weight": 90},
+	        {"name": "api-canary", "port": 8080, "weight": 10},
+	        {"kind": "Bucket", "group": "storage.example.com", "name": "assets"}
+	      ]
+	    }]
+	  },
+	  "status": {"parents": [{
+	    "parentRef": {"name": "public", "namespace": "gateways", "sectionName": "https"},
+	    "conditions": [
+	      {"type": "Accepted", "status": "True", "reason": "Accepted", "lastTransitionTime": "2024-01-01T00:00:00Z"},
+	      {"type": "ResolvedRefs", "status": "True", "reason": "ResolvedRefs", "lastTransitionTime": "2024-01-01T00:00:00Z"}
+	    ]
+	  }]}
+	}`
+	var object map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &object); err != nil {
+		t.Fatal(err)
+	}
+	var route httpRouteObject
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(object, &route); err != nil {
+		t.Fatalf("failed to decode route: %v", err)
+	}
+
+	api := toKubeService(&v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "shop"}})
+	canary := toKubeService(&v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "api-canary", Namespace: "shop"}})
+	services := map[string]*models.KubeService{"shop/api": &api, "shop/api-canary": &canary}
+
+	kubeRoute := toKubeHTTPRoute(&route, services)
+	if len(kubeRoute.ParentRefs) != 1 || !kubeRoute.ParentRefs[0].Accepted || kubeRoute.ParentRefs[0].Namespace != "gateways" {
+		t.Fatalf("unexpected parents: %+v", kubeRoute.ParentRefs)
+	}
+	rule := kubeRoute.Rules[0]
+	if rule.Matches[0].Path != "/api" || rule.Matches[0].Method != "GET" {
+		t.Fatalf("unexpected matches: %+v", rule.Matches)
+	}
+	if len(rule.Backends) != 2 || rule.Backends[0].Service == nil || *rule.Backends[1].Weight != 10 {
+		t.Fatalf("expected two linked service backends, got %+v", rule.Backends)
+	}
+	if s := kubeRoute.Status; !s.Ready || s.Phase != "Accepted" {
+		t.Fatalf("unexpected route status: %+v", s)
+	}
+}
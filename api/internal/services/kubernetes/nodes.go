@@ -0,0 +1,117 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"maps"
+	"slices"
+	"sort"
+
+	"kubey/api/internal/models"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+// GetNodePods returns the pods scheduled on a node with their resource requests
+func GetNodePods(clusterID, nodeName string) (*models.NodePods, error) {
+	cs, err := getClientsetForCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	node, err := cs.CoreV1().Nodes().Get(context.TODO(), nodeName, metav1.GetOptions{})
+	if err != nil {
+		return nil, getError(err, "node", "", nodeName)
+	}
+	pods, err := cs.CoreV1().Pods("").List(context.TODO(), metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("spec.nodeName", nodeName).String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods on node %s: %v", nodeName, err)
+	}
+
+	// metrics-server cannot filter by node, so usage is only fetched for the namespaces the
+	// node's pods run in rather than for every pod in the cluster
+	podUsage := map[string]map[string]resourceUsage{}
+	for _, namespace := range podNamespaces(pods.Items) {
+		usage, err := fetchPodUsage(context.TODO(), cs, namespace)
+		if err != nil {
+			log.Printf("Pod usage unavailable for namespace %s: %v", namespace, err)
+			continue
+		}
+		maps.Copy(podUsage, usage)
+	}
+	kubePods := make([]models.KubePod, 0, len(pods.Items))
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		kubePods = append(kubePods, toKubePod(pod, podUsage[pod.Namespace+"/"+pod.Name]))
+	}
+	return toNodePods(node, pods.Items, kubePods), nil
+}
+
+// podNamespaces returns the distinct namespaces of pods, sorted
+func podNamespaces(pods []v1.Pod) []string {
+	namespaces := map[string]bool{}
+	for i := range pods {
+		namespaces[pods[i].Namespace] = true
+	}
+	return slices.Sorted(maps.Keys(namespaces))
+}
+
+// toNodePods pairs the pods on a node with their converted form, in the same order
+func toNodePods(node *v1.Node, pods []v1.Pod, kubePods []models.KubePod) *models.NodePods {
+	allocatableCPU := float64(node.Status.Allocatable.Cpu().MilliValue())
+	allocatableMemory := node.Status.Allocatable.Memory().Value()
+
+	nodePods := &models.NodePods{
+		Node:        node.Name,
+		Allocatable: getNodeCapacity(node.Status.Allocatable),
+		Pods:        make([]models.NodePod, 0, len(pods)),
+	}
+	for i := range pods {
+		pod, metrics := &pods[i], kubePods[i].Metrics
+		nodePod := models.NodePod{
+			Name:      pod.Name,
+			Namespace: pod.Namespace,
+			Status:    kubePods[i].Status,
+			QOSClass:  string(pod.Status.QOSClass),
+			Terminal:  isPodTerminal(pod),
+		}
+		if metrics != nil {
+			nodePod.CPURequest = metrics.CPURequest
+			nodePod.MemoryRequest = metrics.MemoryRequest
+			nodePod.CPULimit = metrics.CPULimit
+			nodePod.MemoryLimit = metrics.MemoryLimit
+		}
+		if allocatableCPU > 0 {
+			nodePod.CPURequestPercent = nodePod.CPURequest / allocatableCPU * 100
+		}
+		if allocatableMemory > 0 {
+			nodePod.MemoryRequestPercent = float64(nodePod.MemoryRequest) / float64(allocatableMemory) * 100
+		}
+		nodePods.Pods = append(nodePods.Pods, nodePod)
+
+		addPodRequests(&nodePods.Requested, pod, metrics)
+		if nodePod.Terminal || metrics == nil || !metrics.UsageAvailable {
+			continue
+		}
+		nodePods.Requested.CPUUsage += metrics.CPUUsage
+		nodePods.Requested.MemoryUsage += metrics.MemoryUsage
+		nodePods.Requested.UsageAvailable = true
+	}
+
+	sort.SliceStable(nodePods.Pods, func(i, j int) bool {
+		a, b := nodePods.Pods[i], nodePods.Pods[j]
+		if a.CPURequest != b.CPURequest {
+			return a.CPURequest > b.CPURequest
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	return nodePods
+}
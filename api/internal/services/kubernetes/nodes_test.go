@@ -0,0 +1,99 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"kubey/api/internal/models"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestKubeNodeDetails(t *testing.T) {
+	node := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "worker-1", Labels: map[string]string{
+			v1.LabelTopologyRegion:        "eu-west-1",
+			v1.LabelFailureDomainBetaZone: "eu-west-1a",
+		}},
+		Spec: v1.NodeSpec{
+			Unschedulable: true,
+			Taints:        []v1.Taint{{Key: "dedicated", Value: "db", Effect: v1.TaintEffectNoSchedule}},
+		},
+		Status: v1.NodeStatus{
+			Conditions: []v1.NodeCondition{
+				{Type: v1.NodeReady, Status: v1.ConditionTrue, Reason: "KubeletReady"},
+				{Type: v1.NodeMemoryPressure, Status: v1.ConditionFalse},
+			},
+			Addresses: []v1.NodeAddress{{Type: v1.NodeInternalIP, Address: "10.0.0.4"}},
+			NodeInfo:  v1.NodeSystemInfo{OperatingSystem: "linux", Architecture: "arm64", KernelVersion: "6.1.0"},
+		},
+	}
+
+	kubeNode := toKubeNode(node, nil)
+	if kubeNode.Zone != "eu-west-1a" || kubeNode.Region != "eu-west-1" {
+		t.Fatalf("unexpected placement: zone %q region %q", kubeNode.Zone, kubeNode.Region)
+	}
+	if !kubeNode.Unschedulable || len(kubeNode.Taints) != 1 || kubeNode.Taints[0].Effect != "NoSchedule" {
+		t.Fatalf("unexpected scheduling details: %+v %+v", kubeNode.Unschedulable, kubeNode.Taints)
+	}
+	if len(kubeNode.Addresses) != 1 || kubeNode.Architecture != "arm64" || kubeNode.KernelVersion != "6.1.0" {
+		t.Fatalf("unexpected system details: %+v", kubeNode)
+	}
+	if s := kubeNode.Status; !s.Ready || s.Reason != "SchedulingDisabled" || len(kubeNode.Pressure) != 0 {
+		t.Fatalf("expected a cordoned ready node, got %+v", s)
+	}
+
+	node.Status.Conditions[1].Status = v1.ConditionTrue
+	if s := getNodeStatus(node); !s.Ready || s.Reason != "MemoryPressure" {
+		t.Fatalf("expected pressure to take precedence over cordoning, got %+v", s)
+	}
+}
+
+func TestNodePods(t *testing.T) {
+	node := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "worker-1"},
+		Status: v1.NodeStatus{Allocatable: v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse("2"),
+			v1.ResourceMemory: resource.MustParse("4Gi"),
+		}},
+	}
+	pod := func(name, cpu string, phase v1.PodPhase) v1.Pod {
+		return v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop"},
+			Spec: v1.PodSpec{NodeName: "worker-1", Containers: []v1.Container{{
+				Name: "app",
+				Resources: v1.ResourceRequirements{Requests: v1.ResourceList{
+					v1.ResourceCPU:    resource.MustParse(cpu),
+					v1.ResourceMemory: resource.MustParse("1Gi"),
+				}},
+			}}},
+			Status: v1.PodStatus{Phase: phase, QOSClass: v1.PodQOSBurstable},
+		}
+	}
+	pods := []v1.Pod{
+		pod("web", "250m", v1.PodRunning),
+		pod("db", "1", v1.PodRunning),
+		pod("migrate", "500m", v1.PodSucceeded),
+	}
+	kubePods := make([]models.KubePod, 0, len(pods))
+	for i := range pods {
+		kubePods = append(kubePods, toKubePod(&pods[i], nil))
+	}
+
+	nodePods := toNodePods(node, pods, kubePods)
+	if len(nodePods.Pods) != 3 || nodePods.Pods[0].Name != "db" || nodePods.Pods[2].Name != "web" {
+		t.Fatalf("expected pods by CPU request, got %+v", nodePods.Pods)
+	}
+	if db := nodePods.Pods[0]; db.CPURequestPercent != 50 || db.MemoryRequestPercent != 25 || db.QOSClass != "Burstable" {
+		t.Fatalf("unexpected pod requests: %+v", db)
+	}
+	if !nodePods.Pods[1].Terminal || nodePods.Requested.CPURequest != 1250 || nodePods.Requested.MemoryRequest != 2<<30 {
+		t.Fatalf("expected the terminal pod to be left out of the totals, got %+v", nodePods.Requested)
+	}
+
+	pods[1].Namespace = "data"
+	if namespaces := podNamespaces(pods); len(namespaces) != 2 || namespaces[0] != "data" || namespaces[1] != "shop" {
+		t.Fatalf("expected the sorted namespaces of the pods, got %v", namespaces)
+	}
+}
@@ -0,0 +1,461 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"kubey/api/internal/metrics"
+	"kubey/api/internal/models"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// coreGroupAlias names the core API group in URLs, where an empty path segment is not possible
+const coreGroupAlias = "core"
+
+var crdResource = schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+
+// readinessConditions are the condition types that report whether an object is healthy, by priority
+var readinessConditions = []string{"Ready", "Available", "Healthy", "Succeeded", "Established", "Programmed", "Accepted", "Synced"}
+
+// failureConditions are condition types that mark an object unhealthy while True
+var failureConditions = []string{"Stalled", "Degraded", "Failed"}
+
+// healthyPhases are the status.phase values treated as ready when an object has no readiness condition
+var healthyPhases = map[string]bool{
+	"Active": true, "Available": true, "Bound": true, "Complete": true, "Completed": true,
+	"Healthy": true, "Ready": true, "Running": true, "Succeeded": true,
+}
+
+// defaultColumns are shown for resources without CRD printer columns
+var defaultColumns = []printerColumn{{
+	ResourceColumn: models.ResourceColumn{Name: "Age", Type: "date"},
+	JSONPath:       ".metadata.creationTimestamp",
+}}
+
+// printerColumn is a table column and the JSONPath its values are read from
+type printerColumn struct {
+	models.ResourceColumn
+	JSONPath string `json:"jsonPath"`
+}
+
+// crdObject mirrors the fields of a CustomResourceDefinition that kubey uses
+type crdObject struct {
+	Spec struct {
+		Versions []struct {
+			Name                     string          `json:"name"`
+			AdditionalPrinterColumns []printerColumn `json:"additionalPrinterColumns"`
+		} `json:"versions"`
+	} `json:"spec"`
+}
+
+// conditionedObject mirrors the status fields most controllers report
+type conditionedObject struct {
+	Status struct {
+		Phase      string             `json:"phase"`
+		Conditions []metav1.Condition `json:"conditions"`
+	} `json:"status"`
+}
+
+// GetResourceTypes returns the preferred version of every listable resource the cluster serves,
+// including custom resources. Groups whose discovery fails, such as an unavailable aggregated API,
+// are left out.
+func GetResourceTypes(clusterID string) ([]models.APIResourceType, error) {
+	dc, err := getDiscoveryForCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	lists, err := dc.ServerPreferredResources()
+	if err != nil {
+		if !discovery.IsGroupDiscoveryFailedError(err) || len(lists) == 0 {
+			return nil, fmt.Errorf("failed to discover resources: %v", err)
+		}
+		log.Printf("Partial resource discovery for %s: %v", clusterID, err)
+	}
+	custom := getCustomResourceNames(clusterID)
+
+	types := []models.APIResourceType{}
+	for _, list := range lists {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			continue
+		}
+		for _, resource := range list.APIResources {
+			// Subresources such as pods/log cannot be listed on their own
+			if strings.Contains(resource.Name, "/") || !slices.Contains(resource.Verbs, "list") {
+				continue
+			}
+			types = append(types, models.APIResourceType{
+				Group:      gv.Group,
+				Version:    gv.Version,
+				Resource:   resource.Name,
+				Kind:       resource.Kind,
+				Namespaced: resource.Namespaced,
+				Custom:     custom[resource.Name+"."+gv.Group],
+			})
+		}
+	}
+
+	sort.Slice(types, func(i, j int) bool {
+		if types[i].Group != types[j].Group {
+			return types[i].Group < types[j].Group
+		}
+		return types[i].Resource < types[j].Resource
+	})
+	return types, nil
+}
+
+// GetResourceTable lists the objects of any resource as a table of its printer columns.
+// The namespace is ignored for cluster-scoped resources; an empty namespace lists all namespaces.
+func GetResourceTable(clusterID, group, version, resource, namespace string, limit int64, continueToken string) (*models.ResourceTable, error) {
+	resourceType, columns, err := resolveResourceType(clusterID, group, version, resource)
+	if err != nil {
+		return nil, err
+	}
+	client, err := getDynamicClientForCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	if !resourceType.Namespaced {
+		namespace = ""
+	}
+
+	gvr := schema.GroupVersionResource{Group: resourceType.Group, Version: resourceType.Version, Resource: resourceType.Resource}
+	list, err := client.Resource(gvr).Namespace(namespace).List(context.TODO(), metav1.ListOptions{Limit: limit, Continue: continueToken})
+	if err != nil {
+		if apierrors.IsForbidden(err) {
+			return nil, fmt.Errorf("%w: %v", ErrForbidden, err)
+		}
+		return nil, fmt.Errorf("failed to list %s: %v", resource, err)
+	}
+
+	table := &models.ResourceTable{
+		Resource: resourceType,
+		Columns:  []models.ResourceColumn{},
+		Rows:     []models.ResourceRow{},
+		Continue: list.GetContinue(),
+	}
+	for _, column := range columns {
+		table.Columns = append(table.Columns, column.ResourceColumn)
+	}
+	paths := parseColumnPaths(columns)
+	for i := range list.Items {
+		table.Rows = append(table.Rows, toResourceRow(&list.Items[i], paths))
+	}
+	return table, nil
+}
+
+// GetResourceObject returns a single object of any resource with its status conditions
+func GetResourceObject(clusterID, group, version, resource, namespace, name string) (*models.ResourceObject, error) {
+	resourceType, _, err := resolveResourceType(clusterID, group, version, resource)
+	if err != nil {
+		return nil, err
+	}
+	if !resourceType.Namespaced {
+		namespace = ""
+	} else if namespace == "" {
+		return nil, fmt.Errorf("%w: %s is namespaced, so a namespace is required", ErrInvalidArgument, resource)
+	}
+	client, err := getDynamicClientForCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	gvr := schema.GroupVersionResource{Group: resourceType.Group, Version: resourceType.Version, Resource: resourceType.Resource}
+	item, err := client.Resource(gvr).Namespace(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, getError(err, strings.ToLower(resourceType.Kind), namespace, name)
+	}
+
+	// Managed fields are bookkeeping that kubectl also hides by default
+	unstructured.RemoveNestedField(item.Object, "metadata", "managedFields")
+
+	phase, conditions := parseConditions(item)
+	object := &models.ResourceObject{
+		Resource:   resourceType,
+		Name:       item.GetName(),
+		Namespace:  item.GetNamespace(),
+		Status:     getGenericStatus(phase, conditions),
+		Conditions: []models.ResourceCondition{},
+		Object:     item.Object,
+	}
+	for _, condition := range conditions {
+		object.Conditions = append(object.Conditions, models.ResourceCondition{
+			Type:               condition.Type,
+			Status:             string(condition.Status),
+			Reason:             condition.Reason,
+			Message:            condition.Message,
+			LastTransitionTime: condition.LastTransitionTime.Time,
+		})
+	}
+	return object, nil
+}
+
+// resolveResourceType looks a resource up with discovery and returns it with the printer
+// columns of its CRD, or the default columns for built-in resources.
+// Secrets are refused so that their values are only served by the redacting Secret endpoints.
+func resolveResourceType(clusterID, group, version, resource string) (models.APIResourceType, []printerColumn, error) {
+	if group == coreGroupAlias {
+		group = ""
+	}
+	if group == "" && resource == "secrets" {
+		return models.APIResourceType{}, nil, fmt.Errorf("%w: secrets are only served by the secrets endpoints", ErrForbidden)
+	}
+
+	dc, err := getDiscoveryForCluster(clusterID)
+	if err != nil {
+		return models.APIResourceType{}, nil, err
+	}
+
+	gv := schema.GroupVersion{Group: group, Version: version}
+	list, err := dc.ServerResourcesForGroupVersion(gv.String())
+	if err != nil {
+		if errors.Is(err, memory.ErrCacheNotFound) || apierrors.IsNotFound(err) {
+			return models.APIResourceType{}, nil, fmt.Errorf("%w: %s is not served by this cluster", ErrResourceNotFound, gv)
+		}
+		return models.APIResourceType{}, nil, fmt.Errorf("failed to discover %s: %v", gv, err)
+	}
+
+	for _, r := range list.APIResources {
+		if r.Name != resource {
+			continue
+		}
+		resourceType := models.APIResourceType{
+			Group:      group,
+			Version:    version,
+			Resource:   r.Name,
+			Kind:       r.Kind,
+			Namespaced: r.Namespaced,
+		}
+		crd, err := getCustomResourceDefinition(clusterID, group, resource)
+		if err != nil {
+			log.Printf("Printer columns unavailable for %s.%s: %v", resource, group, err)
+		}
+		if crd == nil {
+			return resourceType, defaultColumns, nil
+		}
+		resourceType.Custom = true
+		for _, v := range crd.Spec.Versions {
+			if v.Name == version && len(v.AdditionalPrinterColumns) > 0 {
+				return resourceType, v.AdditionalPrinterColumns, nil
+			}
+		}
+		return resourceType, defaultColumns, nil
+	}
+
+	return models.APIResourceType{}, nil, fmt.Errorf("%w: %s is not served in %s", ErrResourceNotFound, resource, gv)
+}
+
+// cachedCRD is a CRD lookup and when it was made. A nil crd records that the resource has no CRD.
+type cachedCRD struct {
+	crd     *crdObject
+	fetched time.Time
+}
+
+// cachedCRDNames is the set of CRD names of a cluster and when it was listed
+type cachedCRDNames struct {
+	names   map[string]bool
+	fetched time.Time
+}
+
+var (
+	crdCacheMu    sync.Mutex
+	crdCache      = map[string]cachedCRD{}      // By "clusterID/resource.group"
+	crdNamesCache = map[string]cachedCRDNames{} // By cluster ID
+)
+
+// getCustomResourceDefinition returns the CRD that defines a resource, or nil for built-in resources.
+// Lookups are cached for discoveryCacheTTL, like the discovery results they complement.
+func getCustomResourceDefinition(clusterID, group, resource string) (*crdObject, error) {
+	if group == "" || !strings.Contains(group, ".") {
+		return nil, nil
+	}
+
+	key := clusterID + "/" + resource + "." + group
+	crdCacheMu.Lock()
+	cached, ok := crdCache[key]
+	crdCacheMu.Unlock()
+	hit := ok && time.Since(cached.fetched) < discoveryCacheTTL
+	metrics.CacheLookup("crd", hit)
+	if hit {
+		return cached.crd, nil
+	}
+
+	crd, err := fetchCustomResourceDefinition(clusterID, group, resource)
+	if err != nil {
+		return nil, err
+	}
+	crdCacheMu.Lock()
+	crdCache[key] = cachedCRD{crd: crd, fetched: time.Now()}
+	crdCacheMu.Unlock()
+	return crd, nil
+}
+
+func fetchCustomResourceDefinition(clusterID, group, resource string) (*crdObject, error) {
+	client, err := getDynamicClientForCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := client.Resource(crdResource).Get(context.TODO(), resource+"."+group, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var crd crdObject
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, &crd); err != nil {
+		return nil, fmt.Errorf("failed to decode custom resource definition: %v", err)
+	}
+	return &crd, nil
+}
+
+// getCustomResourceNames returns the "resource.group" names of every CRD in the cluster.
+// The set is cached for discoveryCacheTTL and must not be modified. It is empty when CRDs
+// cannot be listed, which is not cached.
+func getCustomResourceNames(clusterID string) map[string]bool {
+	crdCacheMu.Lock()
+	cached, ok := crdNamesCache[clusterID]
+	crdCacheMu.Unlock()
+	hit := ok && time.Since(cached.fetched) < discoveryCacheTTL
+	metrics.CacheLookup("crd_names", hit)
+	if hit {
+		return cached.names
+	}
+
+	names := map[string]bool{}
+	client, err := getDynamicClientForCluster(clusterID)
+	if err != nil {
+		return names
+	}
+
+	list, err := client.Resource(crdResource).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		log.Printf("Failed to list custom resource definitions for %s: %v", clusterID, err)
+		return names
+	}
+	for _, item := range list.Items {
+		names[item.GetName()] = true
+	}
+
+	crdCacheMu.Lock()
+	crdNamesCache[clusterID] = cachedCRDNames{names: names, fetched: time.Now()}
+	crdCacheMu.Unlock()
+	return names
+}
+
+// parseColumnPaths parses the JSONPath of each column. Columns with an invalid path get nil.
+func parseColumnPaths(columns []printerColumn) []*jsonpath.JSONPath {
+	paths := make([]*jsonpath.JSONPath, len(columns))
+	for i, column := range columns {
+		path := jsonpath.New(column.Name).AllowMissingKeys(true)
+		if err := path.Parse("{" + column.JSONPath + "}"); err != nil {
+			log.Printf("Invalid printer column path %q: %v", column.JSONPath, err)
+			continue
+		}
+		paths[i] = path
+	}
+	return paths
+}
+
+func toResourceRow(item *unstructured.Unstructured, paths []*jsonpath.JSONPath) models.ResourceRow {
+	phase, conditions := parseConditions(item)
+	row := models.ResourceRow{
+		Name:      item.GetName(),
+		Namespace: item.GetNamespace(),
+		Cells:     make([]interface{}, len(paths)),
+		Status:    getGenericStatus(phase, conditions),
+		CreatedAt: item.GetCreationTimestamp().Time,
+	}
+	for i, path := range paths {
+		if path == nil {
+			continue
+		}
+		results, err := path.FindResults(item.Object)
+		if err != nil || len(results) == 0 || len(results[0]) == 0 {
+			continue
+		}
+		row.Cells[i] = results[0][0].Interface()
+	}
+	return row
+}
+
+// parseConditions reads status.phase and status.conditions, the shape most controllers report.
+// Objects whose status does not follow it have no phase and no conditions.
+func parseConditions(item *unstructured.Unstructured) (string, []metav1.Condition) {
+	status, ok := item.Object["status"]
+	if !ok {
+		return "", nil
+	}
+	var object conditionedObject
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(map[string]interface{}{"status": status}, &object); err != nil {
+		return "", nil
+	}
+	return object.Status.Phase, object.Status.Conditions
+}
+
+// getGenericStatus derives a status from the conditions of any object. A True failure condition
+// wins over readiness conditions, which win over status.phase. Objects that report neither are Active.
+func getGenericStatus(phase string, conditions []metav1.Condition) models.ResourceStatus {
+	status := models.ResourceStatus{Phase: "Active", Ready: true, LastUpdated: time.Now()}
+	fromCondition := func(condition *metav1.Condition) {
+		status.Reason = condition.Reason
+		status.Message = condition.Message
+		if !condition.LastTransitionTime.IsZero() {
+			status.LastUpdated = condition.LastTransitionTime.Time
+		}
+	}
+
+	for _, conditionType := range failureConditions {
+		if condition := findCondition(conditions, conditionType); condition != nil && condition.Status == metav1.ConditionTrue {
+			status.Phase = conditionType
+			status.Ready = false
+			fromCondition(condition)
+			return status
+		}
+	}
+
+	for _, conditionType := range readinessConditions {
+		condition := findCondition(conditions, conditionType)
+		if condition == nil {
+			continue
+		}
+		status.Ready = condition.Status == metav1.ConditionTrue
+		switch condition.Status {
+		case metav1.ConditionTrue:
+			status.Phase = conditionType
+		case metav1.ConditionFalse:
+			status.Phase = "Not" + conditionType
+		default:
+			status.Phase = "Unknown"
+		}
+		if phase != "" {
+			status.Phase = phase
+		}
+		fromCondition(condition)
+		return status
+	}
+
+	if phase != "" {
+		status.Phase = phase
+		status.Ready = healthyPhases[phase]
+	}
+	return status
+}
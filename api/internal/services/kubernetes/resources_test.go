@@ -0,0 +1,106 @@
+package kubernetes
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"kubey/api/internal/models"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestResourceRowAndStatus(t *testing.T) {
+	raw := `{
+	  "apiVersion": "cert-manager.io/v1",
+	  "kind": "Certificate",
+	  "metadata": {"name": "shop-tls", "namespace": "shop", "creationTimestamp": "2024-01-01T00:00:00Z"},
+	  "spec": {"secretName": "shop-tls"},
+	  "status": {"conditions": [
+	    {"type": "Ready", "status": "False", "reason": "DoesNotExist", "message": "Issuing certificate", "lastTransitionTime": "2024-01-02T00:00:00Z"}
+	  ]}
+	}`
+	var object map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &object); err != nil {
+		t.Fatal(err)
+	}
+	item := &unstructured.Unstructured{Object: object}
+
+	columns := []printerColumn{
+		{ResourceColumn: models.ResourceColumn{Name: "Secret", Type: "string"}, JSONPath: ".spec.secretName"},
+		{ResourceColumn: models.ResourceColumn{Name: "Issuer", Type: "string"}, JSONPath: ".spec.issuerRef.name"},
+		{ResourceColumn: models.ResourceColumn{Name: "Broken", Type: "string"}, JSONPath: ".spec[["},
+	}
+	row := toResourceRow(item, parseColumnPaths(columns))
+	if len(row.Cells) != 3 || row.Cells[0] != "shop-tls" || row.Cells[1] != nil || row.Cells[2] != nil {
+		t.Fatalf("unexpected cells: %#v", row.Cells)
+	}
+	if s := row.Status; s.Ready || s.Phase != "NotReady" || s.Reason != "DoesNotExist" || s.LastUpdated.Year() != 2024 {
+		t.Fatalf("unexpected status: %+v", s)
+	}
+}
+
+func TestGenericStatus(t *testing.T) {
+	status := func(raw string) models.ResourceStatus {
+		var object map[string]interface{}
+		if err := json.Unmarshal([]byte(raw), &object); err != nil {
+			t.Fatal(err)
+		}
+		return getGenericStatus(parseConditions(&unstructured.Unstructured{Object: object}))
+	}
+
+	if s := status(`{"status": {"conditions": [
+	    {"type": "Ready", "status": "True", "lastTransitionTime": "2024-01-01T00:00:00Z"},
+	    {"type": "Stalled", "status": "True", "reason": "InvalidSpec", "lastTransitionTime": "2024-01-01T00:00:00Z"}
+	  ]}}`); s.Ready || s.Phase != "Stalled" || s.Reason != "InvalidSpec" {
+		t.Fatalf("expected a stalled object, got %+v", s)
+	}
+	if s := status(`{"status": {"phase": "Provisioning"}}`); s.Ready || s.Phase != "Provisioning" {
+		t.Fatalf("expected an unhealthy phase, got %+v", s)
+	}
+	if s := status(`{"status": {"phase": "Running"}}`); !s.Ready {
+		t.Fatalf("expected a healthy phase, got %+v", s)
+	}
+	if s := status(`{"spec": {}}`); !s.Ready || s.Phase != "Active" {
+		t.Fatalf("expected an object without status to be active, got %+v", s)
+	}
+	if s := status(`{"status": {"conditions": "not a list"}}`); !s.Ready || s.Phase != "Active" {
+		t.Fatalf("expected unparseable conditions to be ignored, got %+v", s)
+	}
+}
+
+func TestCustomResourceDefinitionCache(t *testing.T) {
+	key := "context-cached/widgets.example.com"
+	crdCacheMu.Lock()
+	crdCache[key] = cachedCRD{crd: &crdObject{}, fetched: time.Now()}
+	crdCacheMu.Unlock()
+	defer func() {
+		crdCacheMu.Lock()
+		delete(crdCache, key)
+		crdCacheMu.Unlock()
+	}()
+
+	// The cluster has no client, so only a cache hit can succeed
+	if crd, err := getCustomResourceDefinition("context-cached", "example.com", "widgets"); err != nil || crd == nil {
+		t.Fatalf("expected the cached CRD, got %v, %v", crd, err)
+	}
+
+	crdCacheMu.Lock()
+	crdCache[key] = cachedCRD{crd: &crdObject{}, fetched: time.Now().Add(-discoveryCacheTTL)}
+	crdCacheMu.Unlock()
+	if _, err := getCustomResourceDefinition("context-cached", "example.com", "widgets"); err == nil {
+		t.Fatal("expected an expired entry to be fetched again")
+	}
+
+	crdCacheMu.Lock()
+	crdNamesCache["context-cached"] = cachedCRDNames{names: map[string]bool{"widgets.example.com": true}, fetched: time.Now()}
+	crdCacheMu.Unlock()
+	defer func() {
+		crdCacheMu.Lock()
+		delete(crdNamesCache, "context-cached")
+		crdCacheMu.Unlock()
+	}()
+	if names := getCustomResourceNames("context-cached"); !names["widgets.example.com"] {
+		t.Fatalf("expected the cached CRD names, got %v", names)
+	}
+}
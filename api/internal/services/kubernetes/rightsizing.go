@@ -0,0 +1,385 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"kubey/api/internal/models"
+	"kubey/api/internal/services/prometheus"
+	"kubey/api/internal/store"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Recommendations are rounded up to these steps and never go below the minimums
+const (
+	cpuRecommendationStep    = 5 // millicores
+	minCPURecommendation     = 10
+	memoryRecommendationStep = 1 << 20 // 1Mi
+	minMemoryRecommendation  = 16 << 20
+)
+
+// RightSizingOptions controls how usage is gathered and turned into recommendations
+type RightSizingOptions struct {
+	Namespace        string // Empty for all namespaces
+	Window           time.Duration
+	CPUPercentile    float64 // 0-100
+	MemoryPercentile float64 // 0-100
+	Headroom         float64 // Fraction added on top of the percentile usage
+
+	// PrometheusURL selects Prometheus as the usage source; kubey's own samples are used when empty
+	PrometheusURL     string
+	PrometheusTimeout time.Duration
+
+	// UsageRetention is how long kubey keeps its own usage samples; longer windows are clamped to it
+	UsageRetention time.Duration
+}
+
+// rightSizingWorkload is a Deployment or StatefulSet and its pod template
+type rightSizingWorkload struct {
+	ref      models.ObjectReference
+	replicas int32
+	template v1.PodSpec
+}
+
+// GetRightSizing recommends container requests and limits for Deployments and StatefulSets from
+// percentile usage over the window plus headroom
+func GetRightSizing(clusterID string, opts RightSizingOptions, s *store.Store) (*models.RightSizingReport, error) {
+	contextName, err := contextNameForCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	cs, err := getClientsetForCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	var workloads []rightSizingWorkload
+	deployments, err := cs.AppsV1().Deployments(opts.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployments: %v", err)
+	}
+	for _, d := range deployments.Items {
+		replicas := int32(1)
+		if d.Spec.Replicas != nil {
+			replicas = *d.Spec.Replicas
+		}
+		workloads = append(workloads, rightSizingWorkload{
+			ref:      models.ObjectReference{Kind: "Deployment", Namespace: d.Namespace, Name: d.Name, UID: string(d.UID)},
+			replicas: replicas,
+			template: d.Spec.Template.Spec,
+		})
+	}
+	statefulSets, err := cs.AppsV1().StatefulSets(opts.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stateful sets: %v", err)
+	}
+	for _, sts := range statefulSets.Items {
+		replicas := int32(1)
+		if sts.Spec.Replicas != nil {
+			replicas = *sts.Spec.Replicas
+		}
+		workloads = append(workloads, rightSizingWorkload{
+			ref:      models.ObjectReference{Kind: "StatefulSet", Namespace: sts.Namespace, Name: sts.Name, UID: string(sts.UID)},
+			replicas: replicas,
+			template: sts.Spec.Template.Spec,
+		})
+	}
+
+	report := &models.RightSizingReport{
+		ClusterID:        clusterID,
+		Namespace:        opts.Namespace,
+		Window:           opts.Window.String(),
+		CPUPercentile:    opts.CPUPercentile,
+		MemoryPercentile: opts.MemoryPercentile,
+		Headroom:         opts.Headroom,
+		Workloads:        []models.WorkloadRecommendation{},
+	}
+
+	var usage map[models.ContainerKey]models.UsageSample
+	var dataPoints map[models.ContainerKey]int
+	if opts.PrometheusURL != "" {
+		report.Source = "prometheus"
+		client := prometheus.NewClient(opts.PrometheusURL, opts.PrometheusTimeout)
+		podUsage, err := prometheus.QueryUsagePercentiles(ctx, client, opts.Namespace, opts.Window, opts.CPUPercentile, opts.MemoryPercentile, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		owners, err := listPodOwners(ctx, cs, opts.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		usage = workloadPercentilesFromPods(podUsage, owners)
+	} else {
+		report.Source = "kubey"
+		window := opts.Window
+		if opts.UsageRetention > 0 && window > opts.UsageRetention {
+			window = opts.UsageRetention
+			report.Window = window.String()
+			report.RequestedWindow = opts.Window.String()
+		}
+		samples, err := s.QueryWorkloadUsage(clusterID, opts.Namespace, time.Now().Add(-window))
+		if err != nil {
+			return nil, err
+		}
+		usage = map[models.ContainerKey]models.UsageSample{}
+		dataPoints = map[models.ContainerKey]int{}
+		for key, values := range samples {
+			usage[key] = usagePercentiles(values, opts.CPUPercentile, opts.MemoryPercentile)
+			dataPoints[key] = len(values)
+		}
+	}
+
+	for _, workload := range workloads {
+		recommendation, ok := recommendWorkload(workload, usage, dataPoints, opts.Headroom, contextName)
+		if !ok {
+			continue
+		}
+		report.Workloads = append(report.Workloads, recommendation)
+		report.TotalCPUSavings += recommendation.CPUSavings
+		report.TotalMemorySavings += recommendation.MemorySavings
+	}
+
+	sort.Slice(report.Workloads, func(i, j int) bool {
+		a, b := report.Workloads[i].Workload, report.Workloads[j].Workload
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Name < b.Name
+	})
+
+	return report, nil
+}
+
+// listPodOwners indexes the pods and ReplicaSets of a namespace, or all namespaces when empty
+func listPodOwners(ctx context.Context, cs kubernetes.Interface, namespace string) (*podOwnerIndex, error) {
+	pods, err := cs.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %v", err)
+	}
+	replicaSets, err := cs.AppsV1().ReplicaSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list replica sets: %v", err)
+	}
+	return newPodOwnerIndex(pods.Items, replicaSets.Items), nil
+}
+
+// recommendWorkload builds recommendations for every container of the workload that has usage data
+func recommendWorkload(workload rightSizingWorkload, usage map[models.ContainerKey]models.UsageSample, dataPoints map[models.ContainerKey]int, headroom float64, contextName string) (models.WorkloadRecommendation, bool) {
+	recommendation := models.WorkloadRecommendation{
+		Workload: workload.ref,
+		Replicas: workload.replicas,
+	}
+
+	var patchContainers []map[string]interface{}
+	for i := range workload.template.Containers {
+		container := &workload.template.Containers[i]
+		key := models.ContainerKey{Kind: workload.ref.Kind, Namespace: workload.ref.Namespace, Name: workload.ref.Name, Container: container.Name}
+		sample, ok := usage[key]
+		if !ok {
+			continue
+		}
+
+		current := getContainerMetrics(container, &resourceUsage{CPU: sample.CPU, Memory: sample.Memory})
+		recommended := recommendResources(current, headroom)
+		recommendation.Containers = append(recommendation.Containers, models.ContainerRecommendation{
+			Container:   container.Name,
+			Current:     *current,
+			Recommended: recommended,
+			DataPoints:  dataPoints[key],
+		})
+
+		replicas := float64(workload.replicas)
+		recommendation.CPUSavings += (current.CPURequest - recommended.CPURequest) * replicas
+		recommendation.MemorySavings += (current.MemoryRequest - recommended.MemoryRequest) * int64(workload.replicas)
+
+		patchContainers = append(patchContainers, map[string]interface{}{
+			"name":      container.Name,
+			"resources": resourcesPatch(recommended),
+		})
+	}
+
+	if len(recommendation.Containers) == 0 {
+		return recommendation, false
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{"containers": patchContainers},
+			},
+		},
+	})
+	if err != nil {
+		return recommendation, false
+	}
+	recommendation.Patch = patch
+	recommendation.PatchCommand = fmt.Sprintf("kubectl --context %s -n %s patch %s %s --type strategic -p '%s'",
+		contextName, workload.ref.Namespace, strings.ToLower(workload.ref.Kind), workload.ref.Name, patch)
+
+	return recommendation, true
+}
+
+// recommendResources sizes requests at the percentile usage plus headroom. Limits are only
+// recommended where the container already has one, keeping its current limit-to-request ratio.
+func recommendResources(current *models.ResourceMetrics, headroom float64) models.ResourceMetrics {
+	recommended := models.ResourceMetrics{
+		CPURequest:    roundUpTo(current.CPUUsage*(1+headroom), cpuRecommendationStep, minCPURecommendation),
+		MemoryRequest: int64(roundUpTo(float64(current.MemoryUsage)*(1+headroom), memoryRecommendationStep, minMemoryRecommendation)),
+	}
+
+	if current.CPULimit > 0 {
+		ratio := limitRatio(current.CPULimit, current.CPURequest)
+		recommended.CPULimit = roundUpTo(recommended.CPURequest*ratio, cpuRecommendationStep, recommended.CPURequest)
+	}
+	if current.MemoryLimit > 0 {
+		ratio := limitRatio(float64(current.MemoryLimit), float64(current.MemoryRequest))
+		recommended.MemoryLimit = int64(roundUpTo(float64(recommended.MemoryRequest)*ratio, memoryRecommendationStep, float64(recommended.MemoryRequest)))
+	}
+
+	return recommended
+}
+
+// limitRatio returns limit/request, or 1 when the request is unset (it then defaults to the limit)
+func limitRatio(limit, request float64) float64 {
+	if request <= 0 || limit < request {
+		return 1
+	}
+	return limit / request
+}
+
+// roundUpTo rounds value up to a multiple of step, with a lower bound of min
+func roundUpTo(value, step, min float64) float64 {
+	return math.Max(math.Ceil(value/step)*step, min)
+}
+
+// resourcesPatch renders recommended requests and limits as Kubernetes quantities
+func resourcesPatch(recommended models.ResourceMetrics) map[string]interface{} {
+	resources := map[string]interface{}{
+		"requests": map[string]string{
+			"cpu":    resource.NewMilliQuantity(int64(recommended.CPURequest), resource.DecimalSI).String(),
+			"memory": resource.NewQuantity(recommended.MemoryRequest, resource.BinarySI).String(),
+		},
+	}
+
+	limits := map[string]string{}
+	if recommended.CPULimit > 0 {
+		limits["cpu"] = resource.NewMilliQuantity(int64(recommended.CPULimit), resource.DecimalSI).String()
+	}
+	if recommended.MemoryLimit > 0 {
+		limits["memory"] = resource.NewQuantity(recommended.MemoryLimit, resource.BinarySI).String()
+	}
+	if len(limits) > 0 {
+		resources["limits"] = limits
+	}
+
+	return resources
+}
+
+// usagePercentiles returns the CPU and memory percentiles (0-100) of the samples, by nearest rank
+func usagePercentiles(samples []models.UsageSample, cpuPercentile, memoryPercentile float64) models.UsageSample {
+	cpu := make([]float64, len(samples))
+	memory := make([]float64, len(samples))
+	for i, sample := range samples {
+		cpu[i] = sample.CPU
+		memory[i] = float64(sample.Memory)
+	}
+
+	return models.UsageSample{
+		CPU:    percentile(cpu, cpuPercentile),
+		Memory: int64(percentile(memory, memoryPercentile)),
+	}
+}
+
+func percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sort.Float64s(values)
+	rank := int(math.Ceil(p/100*float64(len(values)))) - 1
+	rank = min(max(rank, 0), len(values)-1)
+	return values[rank]
+}
+
+// workloadPercentilesFromPods attributes per-pod percentiles to their Deployment or StatefulSet.
+// The busiest pod wins.
+func workloadPercentilesFromPods(podUsage map[prometheus.PodContainer]models.UsageSample, owners *podOwnerIndex) map[models.ContainerKey]models.UsageSample {
+	usage := map[models.ContainerKey]models.UsageSample{}
+	for pod, sample := range podUsage {
+		kind, name := owners.owner(pod.Namespace, pod.Pod)
+		if name == "" {
+			continue
+		}
+		key := models.ContainerKey{Kind: kind, Namespace: pod.Namespace, Name: name, Container: pod.Container}
+		current := usage[key]
+		current.CPU = max(current.CPU, sample.CPU)
+		current.Memory = max(current.Memory, sample.Memory)
+		usage[key] = current
+	}
+	return usage
+}
+
+// podOwnerIndex resolves pods to their Deployment or StatefulSet through ownerReferences.
+// Prometheus history includes pods that no longer exist; those are resolved through their
+// ReplicaSet, which is kept for past rollouts, when the pod name has the generated suffix.
+type podOwnerIndex struct {
+	pods        map[string]podOwner // namespace/pod -> workload
+	replicaSets map[string]string   // namespace/replicaset -> deployment
+}
+
+// podOwner is the kind and name of a pod's workload
+type podOwner struct {
+	kind, name string
+}
+
+func newPodOwnerIndex(pods []v1.Pod, replicaSets []appsv1.ReplicaSet) *podOwnerIndex {
+	index := &podOwnerIndex{
+		pods:        make(map[string]podOwner, len(pods)),
+		replicaSets: make(map[string]string, len(replicaSets)),
+	}
+	for i := range replicaSets {
+		rs := &replicaSets[i]
+		if owner := metav1.GetControllerOf(rs); owner != nil && owner.Kind == "Deployment" {
+			index.replicaSets[rs.Namespace+"/"+rs.Name] = owner.Name
+		}
+	}
+	replicaSetOwner := func(namespace, name string) string {
+		return index.replicaSets[namespace+"/"+name]
+	}
+	for i := range pods {
+		pod := &pods[i]
+		if kind, name := workloadOwner(pod, replicaSetOwner); name != "" {
+			index.pods[pod.Namespace+"/"+pod.Name] = podOwner{kind: kind, name: name}
+		}
+	}
+	return index
+}
+
+// owner returns the kind and name of the pod's workload, or an empty name when it is unknown
+func (idx *podOwnerIndex) owner(namespace, pod string) (string, string) {
+	if owner, ok := idx.pods[namespace+"/"+pod]; ok {
+		return owner.kind, owner.name
+	}
+	// Deployment pods are named "<replicaset>-<suffix>"
+	if i := strings.LastIndex(pod, "-"); i > 0 {
+		if deployment := idx.replicaSets[namespace+"/"+pod[:i]]; deployment != "" {
+			return "Deployment", deployment
+		}
+	}
+	return "", ""
+}
@@ -0,0 +1,126 @@
+package kubernetes
+
+import (
+	"encoding/json"
+	"testing"
+
+	"kubey/api/internal/models"
+	"kubey/api/internal/services/prometheus"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRecommendWorkload(t *testing.T) {
+	workload := rightSizingWorkload{
+		ref:      models.ObjectReference{Kind: "Deployment", Namespace: "shop", Name: "web"},
+		replicas: 3,
+		template: v1.PodSpec{Containers: []v1.Container{
+			{
+				Name: "app",
+				Resources: v1.ResourceRequirements{
+					Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("1"), v1.ResourceMemory: resource.MustParse("1Gi")},
+					Limits:   v1.ResourceList{v1.ResourceCPU: resource.MustParse("2"), v1.ResourceMemory: resource.MustParse("1Gi")},
+				},
+			},
+			{Name: "sidecar"}, // No usage recorded
+		}},
+	}
+
+	samples := []models.UsageSample{}
+	for i := 1; i <= 20; i++ {
+		samples = append(samples, models.UsageSample{CPU: float64(10 * i), Memory: int64(i) << 20 * 10})
+	}
+	key := models.ContainerKey{Kind: "Deployment", Namespace: "shop", Name: "web", Container: "app"}
+	usage := map[models.ContainerKey]models.UsageSample{key: usagePercentiles(samples, 95, 100)}
+
+	recommendation, ok := recommendWorkload(workload, usage, map[models.ContainerKey]int{key: 20}, 0.2, "prod")
+	if !ok || len(recommendation.Containers) != 1 {
+		t.Fatalf("expected one container recommendation, got %+v", recommendation)
+	}
+
+	app := recommendation.Containers[0]
+	// p95 CPU is 190m, +20% = 228m, rounded up to 230m; the 2x limit ratio is kept
+	if app.Current.CPUUsage != 190 || app.Recommended.CPURequest != 230 || app.Recommended.CPULimit != 460 {
+		t.Errorf("unexpected CPU recommendation: current %+v, recommended %+v", app.Current, app.Recommended)
+	}
+	// Peak memory is 200Mi, +20% = 240Mi; the limit equals the request like before
+	if app.Recommended.MemoryRequest != 240<<20 || app.Recommended.MemoryLimit != 240<<20 {
+		t.Errorf("unexpected memory recommendation: %+v", app.Recommended)
+	}
+	if recommendation.CPUSavings != 3*(1000-230) || recommendation.MemorySavings != 3*(1024-240)<<20 {
+		t.Errorf("unexpected savings: cpu %v, memory %v", recommendation.CPUSavings, recommendation.MemorySavings)
+	}
+
+	var patch struct {
+		Spec struct {
+			Template struct {
+				Spec struct {
+					Containers []struct {
+						Name      string `json:"name"`
+						Resources struct {
+							Requests map[string]string `json:"requests"`
+							Limits   map[string]string `json:"limits"`
+						} `json:"resources"`
+					} `json:"containers"`
+				} `json:"spec"`
+			} `json:"template"`
+		} `json:"spec"`
+	}
+	if err := json.Unmarshal(recommendation.Patch, &patch); err != nil {
+		t.Fatal(err)
+	}
+	containers := patch.Spec.Template.Spec.Containers
+	if len(containers) != 1 || containers[0].Resources.Requests["cpu"] != "230m" || containers[0].Resources.Limits["memory"] != "240Mi" {
+		t.Errorf("unexpected patch: %s", recommendation.Patch)
+	}
+}
+
+func TestWorkloadPercentilesFromPods(t *testing.T) {
+	controlledBy := func(kind, name string) []metav1.OwnerReference {
+		controller := true
+		return []metav1.OwnerReference{{Kind: kind, Name: name, Controller: &controller}}
+	}
+	meta := func(name string, owners []metav1.OwnerReference) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Namespace: "shop", Name: name, OwnerReferences: owners}
+	}
+	replicaSets := []appsv1.ReplicaSet{
+		{ObjectMeta: meta("web-5d8f7c", controlledBy("Deployment", "web"))},
+		{ObjectMeta: meta("web-6f9a1b", controlledBy("Deployment", "web"))},
+		{ObjectMeta: meta("web-api-5d8f7c", controlledBy("Deployment", "web-api"))},
+	}
+	pods := []v1.Pod{
+		{ObjectMeta: meta("web-6f9a1b-xyz12", controlledBy("ReplicaSet", "web-6f9a1b"))},
+		{ObjectMeta: meta("db-0", controlledBy("StatefulSet", "db"))},
+		// A StatefulSet whose name extends a Deployment's must not be attributed to it by prefix
+		{ObjectMeta: meta("web-cache-0", controlledBy("StatefulSet", "web-cache"))},
+	}
+	podUsage := map[prometheus.PodContainer]models.UsageSample{
+		// Replaced pod, resolved through its ReplicaSet
+		{Namespace: "shop", Pod: "web-5d8f7c-abcde", Container: "app"}:     {CPU: 100},
+		{Namespace: "shop", Pod: "web-6f9a1b-xyz12", Container: "app"}:     {CPU: 300},
+		{Namespace: "shop", Pod: "web-api-5d8f7c-abcde", Container: "app"}: {CPU: 50},
+		{Namespace: "shop", Pod: "db-0", Container: "postgres"}:            {Memory: 1 << 30},
+		{Namespace: "shop", Pod: "web-cache-0", Container: "redis"}:        {CPU: 20},
+		{Namespace: "shop", Pod: "orphan", Container: "app"}:               {CPU: 1},
+	}
+
+	usage := workloadPercentilesFromPods(podUsage, newPodOwnerIndex(pods, replicaSets))
+	if len(usage) != 4 {
+		t.Fatalf("expected 3 workload containers, got %+v", usage)
+	}
+	if got := usage[models.ContainerKey{Kind: "Deployment", Namespace: "shop", Name: "web", Container: "app"}].CPU; got != 300 {
+		t.Errorf("web CPU = %v, want the busiest pod's 300", got)
+	}
+	if got := usage[models.ContainerKey{Kind: "Deployment", Namespace: "shop", Name: "web-api", Container: "app"}].CPU; got != 50 {
+		t.Errorf("web-api CPU = %v, want 50", got)
+	}
+	if got := usage[models.ContainerKey{Kind: "StatefulSet", Namespace: "shop", Name: "db", Container: "postgres"}].Memory; got != 1<<30 {
+		t.Errorf("db memory = %v", got)
+	}
+	if got := usage[models.ContainerKey{Kind: "StatefulSet", Namespace: "shop", Name: "web-cache", Container: "redis"}].CPU; got != 20 {
+		t.Errorf("web-cache CPU = %v, want 20", got)
+	}
+}
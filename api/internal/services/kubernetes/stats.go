@@ -0,0 +1,199 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"kubey/api/internal/models"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// statsConcurrency bounds how many nodes are asked for their stats summary at once
+	statsConcurrency = 8
+	// statsTimeout bounds the stats summary request to a single node
+	statsTimeout = 10 * time.Second
+)
+
+// fsStats mirrors the kubelet stats API FsStats
+type fsStats struct {
+	AvailableBytes *int64 `json:"availableBytes"`
+	CapacityBytes  *int64 `json:"capacityBytes"`
+	UsedBytes      *int64 `json:"usedBytes"`
+}
+
+// volumeStats mirrors the kubelet stats API VolumeStats
+type volumeStats struct {
+	fsStats
+	Name   string `json:"name"`
+	PVCRef *struct {
+		Name      string `json:"name"`
+		Namespace string `json:"namespace"`
+	} `json:"pvcRef"`
+}
+
+// podStats mirrors the fields of the kubelet stats API PodStats that kubey uses
+type podStats struct {
+	PodRef struct {
+		Name      string `json:"name"`
+		Namespace string `json:"namespace"`
+	} `json:"podRef"`
+	Containers []struct {
+		Name   string   `json:"name"`
+		Rootfs *fsStats `json:"rootfs"`
+		Logs   *fsStats `json:"logs"`
+	} `json:"containers"`
+	Volumes          []volumeStats `json:"volume"`
+	EphemeralStorage *fsStats      `json:"ephemeral-storage"`
+}
+
+// statsSummary mirrors the fields of the kubelet /stats/summary response that kubey uses
+type statsSummary struct {
+	Node struct {
+		NodeName string   `json:"nodeName"`
+		Fs       *fsStats `json:"fs"`
+		Runtime  *struct {
+			ImageFs *fsStats `json:"imageFs"`
+		} `json:"runtime"`
+	} `json:"node"`
+	Pods []podStats `json:"pods"`
+}
+
+// fetchNodeStats reads the stats summary of each node through the API server node proxy.
+// Nodes whose kubelet cannot be reached are left out, so callers degrade to the pod specs alone.
+func fetchNodeStats(ctx context.Context, cs kubernetes.Interface, nodeNames []string) map[string]*statsSummary {
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		summary = make(map[string]*statsSummary, len(nodeNames))
+		sem     = make(chan struct{}, statsConcurrency)
+	)
+	for _, name := range nodeNames {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(name string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			stats, err := fetchStatsSummary(ctx, cs, name)
+			if err != nil {
+				log.Printf("Stats summary unavailable for node %s: %v", name, err)
+				return
+			}
+			mu.Lock()
+			summary[name] = stats
+			mu.Unlock()
+		}(name)
+	}
+	wg.Wait()
+	return summary
+}
+
+func fetchStatsSummary(ctx context.Context, cs kubernetes.Interface, nodeName string) (*statsSummary, error) {
+	ctx, cancel := context.WithTimeout(ctx, statsTimeout)
+	defer cancel()
+
+	body, err := cs.CoreV1().RESTClient().Get().
+		AbsPath("/api/v1/nodes", nodeName, "proxy/stats/summary").
+		DoRaw(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query stats summary: %v", err)
+	}
+
+	var stats statsSummary
+	if err := json.Unmarshal(body, &stats); err != nil {
+		return nil, fmt.Errorf("failed to decode stats summary: %v", err)
+	}
+	return &stats, nil
+}
+
+// podStatsByKey indexes the pods of the given summaries by "namespace/pod"
+func podStatsByKey(summaries map[string]*statsSummary) map[string]*podStats {
+	pods := map[string]*podStats{}
+	for _, summary := range summaries {
+		for i := range summary.Pods {
+			pod := &summary.Pods[i]
+			pods[pod.PodRef.Namespace+"/"+pod.PodRef.Name] = pod
+		}
+	}
+	return pods
+}
+
+// applyPodStats fills ephemeral storage, volume usage and container log sizes from a pod's stats
+func applyPodStats(kubePod *models.KubePod, stats *podStats) {
+	if stats == nil {
+		return
+	}
+
+	kubePod.EphemeralStorage = toStorageStats(stats.EphemeralStorage)
+
+	for i := range kubePod.Containers {
+		container := &kubePod.Containers[i]
+		for _, containerStats := range stats.Containers {
+			if containerStats.Name != container.Name {
+				continue
+			}
+			if usage := toStorageStats(containerStats.Logs); usage != nil {
+				container.LogsBytes = usage.UsedBytes
+			}
+			if usage := toStorageStats(containerStats.Rootfs); usage != nil {
+				container.RootfsBytes = usage.UsedBytes
+			}
+		}
+	}
+
+	volumes := make(map[string]*volumeStats, len(stats.Volumes))
+	for i := range stats.Volumes {
+		volumes[stats.Volumes[i].Name] = &stats.Volumes[i]
+	}
+	for i := range kubePod.Volumes {
+		volume := &kubePod.Volumes[i]
+		volumeStats, ok := volumes[volume.Name]
+		if !ok {
+			continue
+		}
+		volume.Usage = toStorageStats(&volumeStats.fsStats)
+		if volumeStats.PVCRef != nil {
+			volume.ClaimName = volumeStats.PVCRef.Name
+		}
+		if volume.Usage != nil && volume.Usage.CapacityBytes > 0 && volume.Size == "" {
+			volume.Size = resource.NewQuantity(volume.Usage.CapacityBytes, resource.BinarySI).String()
+		}
+	}
+}
+
+// applyNodeStats fills the node's root and image filesystem usage
+func applyNodeStats(kubeNode *models.KubeNode, stats *statsSummary) {
+	if stats == nil {
+		return
+	}
+	kubeNode.Filesystem = toStorageStats(stats.Node.Fs)
+	if stats.Node.Runtime != nil {
+		kubeNode.ImageFilesystem = toStorageStats(stats.Node.Runtime.ImageFs)
+	}
+}
+
+// toStorageStats converts kubelet filesystem stats, returning nil when no usage was reported
+func toStorageStats(fs *fsStats) *models.StorageStats {
+	if fs == nil || fs.UsedBytes == nil {
+		return nil
+	}
+	stats := &models.StorageStats{UsedBytes: *fs.UsedBytes}
+	if fs.CapacityBytes != nil {
+		stats.CapacityBytes = *fs.CapacityBytes
+	}
+	if fs.AvailableBytes != nil {
+		stats.AvailableBytes = *fs.AvailableBytes
+	}
+	if stats.CapacityBytes > 0 {
+		stats.UsedPercent = float64(stats.UsedBytes) / float64(stats.CapacityBytes) * 100
+	}
+	return stats
+}
@@ -0,0 +1,68 @@
+package kubernetes
+
+import (
+	"encoding/json"
+	"testing"
+
+	"kubey/api/internal/models"
+)
+
+const testStatsSummary = `{
+  "node": {
+    "nodeName": "node-a",
+    "fs": {"availableBytes": 60, "capacityBytes": 100, "usedBytes": 40},
+    "runtime": {"imageFs": {"availableBytes": 80, "capacityBytes": 100, "usedBytes": 20}}
+  },
+  "pods": [{
+    "podRef": {"name": "db-0", "namespace": "shop"},
+    "containers": [{"name": "postgres", "rootfs": {"usedBytes": 4096}, "logs": {"usedBytes": 2048}}],
+    "volume": [
+      {"name": "data", "usedBytes": 805306368, "capacityBytes": 1073741824, "availableBytes": 268435456, "pvcRef": {"name": "data-db-0", "namespace": "shop"}},
+      {"name": "kube-api-access", "usedBytes": 12288}
+    ],
+    "ephemeral-storage": {"usedBytes": 18432}
+  }]
+}`
+
+func TestApplyStats(t *testing.T) {
+	var summary statsSummary
+	if err := json.Unmarshal([]byte(testStatsSummary), &summary); err != nil {
+		t.Fatalf("failed to decode summary: %v", err)
+	}
+
+	pod := models.KubePod{
+		Name:       "db-0",
+		Namespace:  "shop",
+		Containers: []models.KubeContainer{{Name: "postgres"}},
+		Volumes: []models.KubeVolume{
+			{Name: "data", Type: "persistentVolumeClaim"},
+			{Name: "kube-api-access", Type: "projected"},
+			{Name: "scratch", Type: "emptyDir"},
+		},
+	}
+	stats := podStatsByKey(map[string]*statsSummary{"node-a": &summary})
+	applyPodStats(&pod, stats["shop/db-0"])
+
+	if pod.EphemeralStorage == nil || pod.EphemeralStorage.UsedBytes != 18432 {
+		t.Fatalf("unexpected ephemeral storage: %+v", pod.EphemeralStorage)
+	}
+	if c := pod.Containers[0]; c.LogsBytes != 2048 || c.RootfsBytes != 4096 {
+		t.Fatalf("unexpected container stats: %+v", c)
+	}
+	data := pod.Volumes[0]
+	if data.ClaimName != "data-db-0" || data.Size != "1Gi" || data.Usage == nil || data.Usage.UsedPercent != 75 {
+		t.Fatalf("unexpected PVC volume: %+v (usage %+v)", data, data.Usage)
+	}
+	if token := pod.Volumes[1]; token.Usage == nil || token.Usage.UsedBytes != 12288 || token.Size != "" {
+		t.Fatalf("unexpected projected volume: %+v", token)
+	}
+	if scratch := pod.Volumes[2]; scratch.Usage != nil {
+		t.Fatalf("expected no usage for a volume missing from the summary, got %+v", scratch.Usage)
+	}
+
+	var node models.KubeNode
+	applyNodeStats(&node, &summary)
+	if node.Filesystem == nil || node.Filesystem.UsedPercent != 40 || node.ImageFilesystem == nil || node.ImageFilesystem.UsedBytes != 20 {
+		t.Fatalf("unexpected node filesystems: %+v %+v", node.Filesystem, node.ImageFilesystem)
+	}
+}
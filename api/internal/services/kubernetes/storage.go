@@ -0,0 +1,377 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"kubey/api/internal/models"
+
+	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Annotations marking the storage class used by claims without a class
+const (
+	defaultStorageClassAnnotation     = "storageclass.kubernetes.io/is-default-class"
+	betaDefaultStorageClassAnnotation = "storageclass.beta.kubernetes.io/is-default-class"
+)
+
+// GetPersistentVolumeClaims returns the persistent volume claims of a cluster with the pods
+// that mount them. An empty namespace lists all namespaces.
+func GetPersistentVolumeClaims(clusterID, namespace string) ([]models.KubePersistentVolumeClaim, error) {
+	cs, err := getClientsetForCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := cs.CoreV1().PersistentVolumeClaims(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list persistent volume claims: %v", err)
+	}
+	usedBy, err := getClaimUsers(context.TODO(), cs, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := []models.KubePersistentVolumeClaim{}
+	for i := range list.Items {
+		pvc := &list.Items[i]
+		claims = append(claims, toKubePersistentVolumeClaim(pvc, usedBy[pvc.Namespace+"/"+pvc.Name]))
+	}
+	return claims, nil
+}
+
+// GetPersistentVolumeClaim returns a single persistent volume claim with the pods that mount it
+func GetPersistentVolumeClaim(clusterID, namespace, name string) (*models.KubePersistentVolumeClaim, error) {
+	cs, err := getClientsetForCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	pvc, err := cs.CoreV1().PersistentVolumeClaims(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, getError(err, "persistent volume claim", namespace, name)
+	}
+	usedBy, err := getClaimUsers(context.TODO(), cs, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	claim := toKubePersistentVolumeClaim(pvc, usedBy[namespace+"/"+name])
+	return &claim, nil
+}
+
+// GetPersistentVolumes returns the persistent volumes of a cluster
+func GetPersistentVolumes(clusterID string) ([]models.KubePersistentVolume, error) {
+	cs, err := getClientsetForCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := cs.CoreV1().PersistentVolumes().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list persistent volumes: %v", err)
+	}
+
+	volumes := []models.KubePersistentVolume{}
+	for i := range list.Items {
+		volumes = append(volumes, toKubePersistentVolume(&list.Items[i]))
+	}
+	return volumes, nil
+}
+
+// GetPersistentVolume returns a single persistent volume
+func GetPersistentVolume(clusterID, name string) (*models.KubePersistentVolume, error) {
+	cs, err := getClientsetForCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	pv, err := cs.CoreV1().PersistentVolumes().Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, getError(err, "persistent volume", "", name)
+	}
+
+	volume := toKubePersistentVolume(pv)
+	return &volume, nil
+}
+
+// GetStorageClasses returns the storage classes of a cluster
+func GetStorageClasses(clusterID string) ([]models.KubeStorageClass, error) {
+	cs, err := getClientsetForCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := cs.StorageV1().StorageClasses().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list storage classes: %v", err)
+	}
+
+	classes := []models.KubeStorageClass{}
+	for i := range list.Items {
+		classes = append(classes, toKubeStorageClass(&list.Items[i]))
+	}
+	return classes, nil
+}
+
+// getClaimUsers maps each "namespace/name" claim to the pods in a namespace (all namespaces
+// when empty) that mount it
+func getClaimUsers(ctx context.Context, cs kubernetes.Interface, namespace string) (map[string][]string, error) {
+	pods, err := cs.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %v", err)
+	}
+
+	users := map[string][]string{}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		for j := range pod.Spec.Volumes {
+			if claimName := getVolumeClaimName(pod, &pod.Spec.Volumes[j]); claimName != "" {
+				key := pod.Namespace + "/" + claimName
+				users[key] = append(users[key], pod.Name)
+			}
+		}
+	}
+	return users, nil
+}
+
+// getClaimIndex returns the claims in a namespace (all namespaces when empty) keyed by "namespace/name"
+func getClaimIndex(ctx context.Context, cs kubernetes.Interface, namespace string) (map[string]*models.KubePersistentVolumeClaim, error) {
+	list, err := cs.CoreV1().PersistentVolumeClaims(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list persistent volume claims: %v", err)
+	}
+
+	claims := make(map[string]*models.KubePersistentVolumeClaim, len(list.Items))
+	for i := range list.Items {
+		claim := toKubePersistentVolumeClaim(&list.Items[i], nil)
+		claims[claim.Namespace+"/"+claim.Name] = &claim
+	}
+	return claims, nil
+}
+
+// getVolumeClaimName returns the claim backing a pod volume, including the claims created
+// for generic ephemeral volumes, or "" for other volumes
+func getVolumeClaimName(pod *v1.Pod, volume *v1.Volume) string {
+	switch getVolumeType(volume) {
+	case "persistentVolumeClaim":
+		return volume.PersistentVolumeClaim.ClaimName
+	case "ephemeral":
+		return pod.Name + "-" + volume.Name
+	}
+	return ""
+}
+
+// linkPodVolumes fills the claim, persistent volume, size and status of a pod's claim-backed volumes.
+// A volume whose claim does not exist is flagged with reason ClaimNotFound.
+func linkPodVolumes(kubePod *models.KubePod, claims map[string]*models.KubePersistentVolumeClaim) {
+	if claims == nil {
+		return
+	}
+
+	for i := range kubePod.Volumes {
+		volume := &kubePod.Volumes[i]
+		if volume.ClaimName == "" {
+			continue
+		}
+
+		claim, ok := claims[kubePod.Namespace+"/"+volume.ClaimName]
+		if !ok {
+			volume.Status = &models.ResourceStatus{
+				Phase:       "Missing",
+				Reason:      "ClaimNotFound",
+				Message:     fmt.Sprintf("persistent volume claim %s does not exist", volume.ClaimName),
+				LastUpdated: time.Now(),
+			}
+			continue
+		}
+
+		volume.PersistentVolume = claim.VolumeName
+		volume.StorageClass = claim.StorageClass
+		volume.Size = claim.Capacity
+		if volume.Size == "" {
+			volume.Size = claim.Requested
+		}
+		status := claim.Status
+		volume.Status = &status
+	}
+}
+
+func toKubePersistentVolumeClaim(pvc *v1.PersistentVolumeClaim, usedBy []string) models.KubePersistentVolumeClaim {
+	claim := models.KubePersistentVolumeClaim{
+		Name:        pvc.Name,
+		Namespace:   pvc.Namespace,
+		VolumeName:  pvc.Spec.VolumeName,
+		AccessModes: accessModeStrings(pvc.Spec.AccessModes),
+		VolumeMode:  string(v1.PersistentVolumeFilesystem),
+		UsedBy:      usedBy,
+		Status:      getClaimStatus(pvc),
+		Labels:      pvc.Labels,
+		CreatedAt:   pvc.CreationTimestamp.Time,
+	}
+	if claim.UsedBy == nil {
+		claim.UsedBy = []string{}
+	}
+	if pvc.Spec.StorageClassName != nil {
+		claim.StorageClass = *pvc.Spec.StorageClassName
+	}
+	if pvc.Spec.VolumeMode != nil {
+		claim.VolumeMode = string(*pvc.Spec.VolumeMode)
+	}
+	if request, ok := pvc.Spec.Resources.Requests[v1.ResourceStorage]; ok {
+		claim.Requested = request.String()
+	}
+	if capacity, ok := pvc.Status.Capacity[v1.ResourceStorage]; ok {
+		claim.Capacity = capacity.String()
+	}
+	// The bound volume's access modes are the ones actually granted
+	if len(pvc.Status.AccessModes) > 0 {
+		claim.AccessModes = accessModeStrings(pvc.Status.AccessModes)
+	}
+	return claim
+}
+
+func toKubePersistentVolume(pv *v1.PersistentVolume) models.KubePersistentVolume {
+	volume := models.KubePersistentVolume{
+		Name:          pv.Name,
+		AccessModes:   accessModeStrings(pv.Spec.AccessModes),
+		VolumeMode:    string(v1.PersistentVolumeFilesystem),
+		ReclaimPolicy: string(pv.Spec.PersistentVolumeReclaimPolicy),
+		StorageClass:  pv.Spec.StorageClassName,
+		Source:        getPersistentVolumeSource(pv),
+		Status:        getPersistentVolumeStatus(pv),
+		Labels:        pv.Labels,
+		CreatedAt:     pv.CreationTimestamp.Time,
+	}
+	if capacity, ok := pv.Spec.Capacity[v1.ResourceStorage]; ok {
+		volume.Capacity = capacity.String()
+	}
+	if pv.Spec.VolumeMode != nil {
+		volume.VolumeMode = string(*pv.Spec.VolumeMode)
+	}
+	if pv.Spec.CSI != nil {
+		volume.CSIDriver = pv.Spec.CSI.Driver
+		volume.VolumeHandle = pv.Spec.CSI.VolumeHandle
+	}
+	if ref := pv.Spec.ClaimRef; ref != nil {
+		volume.Claim = ref.Namespace + "/" + ref.Name
+	}
+	return volume
+}
+
+func toKubeStorageClass(sc *storagev1.StorageClass) models.KubeStorageClass {
+	class := models.KubeStorageClass{
+		Name:                 sc.Name,
+		Provisioner:          sc.Provisioner,
+		ReclaimPolicy:        string(v1.PersistentVolumeReclaimDelete),
+		VolumeBindingMode:    string(storagev1.VolumeBindingImmediate),
+		AllowVolumeExpansion: sc.AllowVolumeExpansion != nil && *sc.AllowVolumeExpansion,
+		Default:              sc.Annotations[defaultStorageClassAnnotation] == "true" || sc.Annotations[betaDefaultStorageClassAnnotation] == "true",
+		Parameters:           sc.Parameters,
+		Labels:               sc.Labels,
+		CreatedAt:            sc.CreationTimestamp.Time,
+	}
+	if class.Parameters == nil {
+		class.Parameters = map[string]string{}
+	}
+	if sc.ReclaimPolicy != nil {
+		class.ReclaimPolicy = string(*sc.ReclaimPolicy)
+	}
+	if sc.VolumeBindingMode != nil {
+		class.VolumeBindingMode = string(*sc.VolumeBindingMode)
+	}
+	return class
+}
+
+func accessModeStrings(modes []v1.PersistentVolumeAccessMode) []string {
+	result := make([]string, 0, len(modes))
+	for _, mode := range modes {
+		result = append(result, string(mode))
+	}
+	return result
+}
+
+// getPersistentVolumeSource names the volume plugin that provides a persistent volume
+func getPersistentVolumeSource(pv *v1.PersistentVolume) string {
+	source := pv.Spec.PersistentVolumeSource
+	switch {
+	case source.CSI != nil:
+		return "csi"
+	case source.NFS != nil:
+		return "nfs"
+	case source.Local != nil:
+		return "local"
+	case source.HostPath != nil:
+		return "hostPath"
+	case source.ISCSI != nil:
+		return "iscsi"
+	case source.FC != nil:
+		return "fc"
+	case source.RBD != nil:
+		return "rbd"
+	case source.CephFS != nil:
+		return "cephfs"
+	}
+	return "other"
+}
+
+// getClaimStatus flags claims that are not bound, have lost their volume or are being resized
+func getClaimStatus(pvc *v1.PersistentVolumeClaim) models.ResourceStatus {
+	status := models.ResourceStatus{Phase: string(pvc.Status.Phase), LastUpdated: time.Now()}
+
+	switch pvc.Status.Phase {
+	case v1.ClaimBound:
+		status.Ready = true
+		for _, conditionType := range []v1.PersistentVolumeClaimConditionType{
+			v1.PersistentVolumeClaimControllerResizeError,
+			v1.PersistentVolumeClaimNodeResizeError,
+			v1.PersistentVolumeClaimFileSystemResizePending,
+			v1.PersistentVolumeClaimResizing,
+		} {
+			for _, condition := range pvc.Status.Conditions {
+				if condition.Type == conditionType && condition.Status == v1.ConditionTrue {
+					status.Reason = string(condition.Type)
+					status.Message = condition.Message
+					return status
+				}
+			}
+		}
+	case v1.ClaimLost:
+		status.Reason = "VolumeLost"
+		status.Message = fmt.Sprintf("bound persistent volume %s no longer exists", pvc.Spec.VolumeName)
+	default:
+		status.Phase = string(v1.ClaimPending)
+		status.Reason = "NotBound"
+		status.Message = "the claim is not bound to a persistent volume"
+	}
+	return status
+}
+
+// getPersistentVolumeStatus flags volumes that are released by their claim or failed to be reclaimed
+func getPersistentVolumeStatus(pv *v1.PersistentVolume) models.ResourceStatus {
+	status := models.ResourceStatus{
+		Phase:       string(pv.Status.Phase),
+		Reason:      pv.Status.Reason,
+		Message:     pv.Status.Message,
+		LastUpdated: time.Now(),
+	}
+
+	switch pv.Status.Phase {
+	case v1.VolumeAvailable, v1.VolumeBound:
+		status.Ready = true
+	case v1.VolumeReleased:
+		if status.Reason == "" {
+			status.Reason = "Released"
+			status.Message = fmt.Sprintf("claim was deleted and the %s reclaim policy keeps the volume", pv.Spec.PersistentVolumeReclaimPolicy)
+		}
+	case v1.VolumeFailed:
+		if status.Reason == "" {
+			status.Reason = "ReclaimFailed"
+		}
+	}
+	return status
+}
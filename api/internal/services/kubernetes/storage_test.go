@@ -0,0 +1,77 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"kubey/api/internal/models"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestClaimStatus(t *testing.T) {
+	pvc := &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "data-db-0", Namespace: "shop"},
+		Spec:       v1.PersistentVolumeClaimSpec{VolumeName: "pv-1"},
+		Status:     v1.PersistentVolumeClaimStatus{Phase: v1.ClaimBound},
+	}
+	if s := getClaimStatus(pvc); !s.Ready || s.Reason != "" {
+		t.Fatalf("expected a ready bound claim, got %+v", s)
+	}
+
+	pvc.Status.Conditions = []v1.PersistentVolumeClaimCondition{{Type: v1.PersistentVolumeClaimFileSystemResizePending, Status: v1.ConditionTrue}}
+	if s := getClaimStatus(pvc); !s.Ready || s.Reason != "FileSystemResizePending" {
+		t.Fatalf("expected a pending resize, got %+v", s)
+	}
+
+	pvc.Status = v1.PersistentVolumeClaimStatus{Phase: v1.ClaimLost}
+	if s := getClaimStatus(pvc); s.Ready || s.Reason != "VolumeLost" {
+		t.Fatalf("expected a lost claim, got %+v", s)
+	}
+
+	pvc.Status = v1.PersistentVolumeClaimStatus{}
+	if s := getClaimStatus(pvc); s.Ready || s.Phase != "Pending" || s.Reason != "NotBound" {
+		t.Fatalf("expected an unbound claim, got %+v", s)
+	}
+}
+
+func TestLinkPodVolumes(t *testing.T) {
+	class := "fast"
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "db-0", Namespace: "shop"},
+		Spec: v1.PodSpec{Volumes: []v1.Volume{
+			{Name: "data", VolumeSource: v1.VolumeSource{PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: "data-db-0"}}},
+			{Name: "scratch", VolumeSource: v1.VolumeSource{Ephemeral: &v1.EphemeralVolumeSource{}}},
+			{Name: "config", VolumeSource: v1.VolumeSource{ConfigMap: &v1.ConfigMapVolumeSource{}}},
+		}},
+	}
+	bound := toKubePersistentVolumeClaim(&v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "data-db-0", Namespace: "shop"},
+		Spec: v1.PersistentVolumeClaimSpec{
+			VolumeName:       "pv-1",
+			StorageClassName: &class,
+			Resources:        v1.VolumeResourceRequirements{Requests: v1.ResourceList{v1.ResourceStorage: resource.MustParse("10Gi")}},
+		},
+		Status: v1.PersistentVolumeClaimStatus{
+			Phase:    v1.ClaimBound,
+			Capacity: v1.ResourceList{v1.ResourceStorage: resource.MustParse("16Gi")},
+		},
+	}, nil)
+	claims := map[string]*models.KubePersistentVolumeClaim{"shop/data-db-0": &bound}
+
+	kubePod := toKubePod(pod, nil)
+	linkPodVolumes(&kubePod, claims)
+
+	data := kubePod.Volumes[0]
+	if data.PersistentVolume != "pv-1" || data.StorageClass != "fast" || data.Size != "16Gi" || data.Status == nil || !data.Status.Ready {
+		t.Fatalf("unexpected linked volume: %+v", data)
+	}
+	scratch := kubePod.Volumes[1]
+	if scratch.Type != "ephemeral" || scratch.ClaimName != "db-0-scratch" || scratch.Status == nil || scratch.Status.Reason != "ClaimNotFound" {
+		t.Fatalf("expected the missing ephemeral claim to be flagged, got %+v", scratch)
+	}
+	if config := kubePod.Volumes[2]; config.ClaimName != "" || config.Status != nil {
+		t.Fatalf("expected no claim for a configMap volume, got %+v", config)
+	}
+}
@@ -0,0 +1,446 @@
+package kubernetes
+
+import (
+	"context"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"kubey/api/internal/models"
+
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	// summarySubscriberBuffer is how many pending pushes a slow subscriber may fall behind by
+	summarySubscriberBuffer = 16
+	// summaryUtilizationInterval is how often utilization is recomputed, as metrics-server is not watchable
+	summaryUtilizationInterval = 15 * time.Second
+	// summaryUtilizationTimeout bounds the metrics-server request of a utilization refresh
+	summaryUtilizationTimeout = 10 * time.Second
+)
+
+// summaryTracker keeps a cluster's ClusterSummary up to date from watch events
+type summaryTracker struct {
+	mu           sync.Mutex
+	nodes        map[string]bool        // node name -> ready
+	pods         map[string]v1.PodPhase // namespace/name -> phase
+	namespaces   map[string]bool
+	deployments  map[string]bool
+	statefulSets map[string]bool
+	daemonSets   map[string]bool
+	replicaSets  map[string]bool
+	services     map[string]bool
+	failedJobs   map[string]bool
+	readyNodes   int
+	podPhases    map[v1.PodPhase]int
+	summary      models.ClusterSummary
+	hasSynced    []cache.InformerSynced
+	dirty        bool
+	updatedAt    time.Time
+
+	// Utilization is refreshed periodically from the listers and metrics-server rather than per event
+	nodeLister    corelisters.NodeLister
+	podLister     corelisters.PodLister
+	utilization   models.ClusterSummary // Only the utilization fields are set
+	utilizationAt time.Time
+}
+
+// summaryHub fans out live summaries to subscribers
+type summaryHub struct {
+	mu          sync.Mutex
+	trackers    map[string]*summaryTracker
+	subscribers map[chan []models.SummaryUpdate]bool
+}
+
+var summaries = &summaryHub{
+	trackers:    map[string]*summaryTracker{},
+	subscribers: map[chan []models.SummaryUpdate]bool{},
+}
+
+// TrackSummaries keeps a live ClusterSummary for every watched cluster and pushes
+// the summaries that changed to subscribers at most once per interval
+func TrackSummaries(ctx context.Context, interval time.Duration) {
+	registerInformers(func(clusterID string, factory informers.SharedInformerFactory) {
+		tracker := &summaryTracker{
+			nodes:        map[string]bool{},
+			pods:         map[string]v1.PodPhase{},
+			namespaces:   map[string]bool{},
+			deployments:  map[string]bool{},
+			statefulSets: map[string]bool{},
+			daemonSets:   map[string]bool{},
+			replicaSets:  map[string]bool{},
+			services:     map[string]bool{},
+			failedJobs:   map[string]bool{},
+			podPhases:    map[v1.PodPhase]int{},
+			nodeLister:   factory.Core().V1().Nodes().Lister(),
+			podLister:    factory.Core().V1().Pods().Lister(),
+		}
+
+		core := factory.Core().V1()
+		nodeInformer := core.Nodes().Informer()
+		podInformer := core.Pods().Informer()
+		namespaceInformer := core.Namespaces().Informer()
+		deploymentInformer := factory.Apps().V1().Deployments().Informer()
+		statefulSetInformer := factory.Apps().V1().StatefulSets().Informer()
+		daemonSetInformer := factory.Apps().V1().DaemonSets().Informer()
+		replicaSetInformer := factory.Apps().V1().ReplicaSets().Informer()
+		serviceInformer := core.Services().Informer()
+		jobInformer := factory.Batch().V1().Jobs().Informer()
+
+		nodeInformer.AddEventHandler(tracker.handler(func(obj interface{}, deleted bool) {
+			node, ok := obj.(*v1.Node)
+			if !ok {
+				return
+			}
+			tracker.setNode(node.Name, isNodeReady(node), deleted)
+		}))
+		podInformer.AddEventHandler(tracker.handler(func(obj interface{}, deleted bool) {
+			pod, ok := obj.(*v1.Pod)
+			if !ok {
+				return
+			}
+			tracker.setPod(pod.Namespace+"/"+pod.Name, pod.Status.Phase, deleted)
+		}))
+		namespaceInformer.AddEventHandler(tracker.handler(keySetter(tracker.namespaces)))
+		deploymentInformer.AddEventHandler(tracker.handler(keySetter(tracker.deployments)))
+		statefulSetInformer.AddEventHandler(tracker.handler(keySetter(tracker.statefulSets)))
+		daemonSetInformer.AddEventHandler(tracker.handler(keySetter(tracker.daemonSets)))
+		replicaSetInformer.AddEventHandler(tracker.handler(keySetter(tracker.replicaSets)))
+		serviceInformer.AddEventHandler(tracker.handler(keySetter(tracker.services)))
+		jobInformer.AddEventHandler(tracker.handler(func(obj interface{}, deleted bool) {
+			job, ok := obj.(*batchv1.Job)
+			if !ok {
+				return
+			}
+			keySetter(tracker.failedJobs)(obj, deleted || !isJobFailed(job))
+		}))
+
+		tracker.hasSynced = []cache.InformerSynced{
+			nodeInformer.HasSynced,
+			podInformer.HasSynced,
+			namespaceInformer.HasSynced,
+			deploymentInformer.HasSynced,
+			statefulSetInformer.HasSynced,
+			daemonSetInformer.HasSynced,
+			replicaSetInformer.HasSynced,
+			serviceInformer.HasSynced,
+			jobInformer.HasSynced,
+		}
+
+		summaries.mu.Lock()
+		summaries.trackers[clusterID] = tracker
+		summaries.mu.Unlock()
+	})
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				summaries.pushChanged()
+			}
+		}
+	}()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				summaries.refreshUtilization(ctx, now)
+			}
+		}
+	}()
+}
+
+// GetLiveSummaries returns the current live summary of every tracked cluster
+func GetLiveSummaries() []models.SummaryUpdate {
+	summaries.mu.Lock()
+	defer summaries.mu.Unlock()
+
+	updates := []models.SummaryUpdate{}
+	for clusterID, tracker := range summaries.trackers {
+		updates = append(updates, tracker.snapshot(clusterID))
+	}
+	sort.Slice(updates, func(i, j int) bool {
+		return updates[i].ClusterID < updates[j].ClusterID
+	})
+	return updates
+}
+
+// liveFailedJobs returns the failed job count of a tracked cluster whose watches have synced
+func liveFailedJobs(clusterID string) (int, bool) {
+	summaries.mu.Lock()
+	tracker, ok := summaries.trackers[clusterID]
+	summaries.mu.Unlock()
+	if !ok {
+		return 0, false
+	}
+
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+	if !tracker.synced() {
+		return 0, false
+	}
+	return len(tracker.failedJobs), true
+}
+
+// SubscribeSummaries returns a channel receiving batches of changed cluster summaries.
+// The returned function must be called to unsubscribe.
+func SubscribeSummaries() (<-chan []models.SummaryUpdate, func()) {
+	ch := make(chan []models.SummaryUpdate, summarySubscriberBuffer)
+
+	summaries.mu.Lock()
+	summaries.subscribers[ch] = true
+	summaries.mu.Unlock()
+
+	return ch, func() {
+		summaries.mu.Lock()
+		defer summaries.mu.Unlock()
+		if summaries.subscribers[ch] {
+			delete(summaries.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// pushChanged sends the summaries that changed since the last push to every subscriber
+func (h *summaryHub) pushChanged() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var changed []models.SummaryUpdate
+	for clusterID, tracker := range h.trackers {
+		if update, ok := tracker.takeIfDirty(clusterID); ok {
+			changed = append(changed, update)
+		}
+	}
+	if len(changed) == 0 {
+		return
+	}
+
+	for ch := range h.subscribers {
+		select {
+		case ch <- changed:
+		default:
+			// Drop slow subscribers; they resubscribe and receive a fresh snapshot
+			log.Printf("Dropping slow summary subscriber")
+			delete(h.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// refreshUtilization recomputes the utilization of every synced tracker last refreshed
+// at least summaryUtilizationInterval ago
+func (h *summaryHub) refreshUtilization(ctx context.Context, now time.Time) {
+	h.mu.Lock()
+	trackers := make(map[string]*summaryTracker, len(h.trackers))
+	for clusterID, tracker := range h.trackers {
+		trackers[clusterID] = tracker
+	}
+	h.mu.Unlock()
+
+	for clusterID, tracker := range trackers {
+		tracker.mu.Lock()
+		due := tracker.synced() && now.Sub(tracker.utilizationAt) >= summaryUtilizationInterval
+		tracker.mu.Unlock()
+		if !due {
+			continue
+		}
+
+		nodes, err := tracker.nodeLister.List(labels.Everything())
+		if err != nil {
+			continue
+		}
+		pods, err := tracker.podLister.List(labels.Everything())
+		if err != nil {
+			continue
+		}
+
+		// Without metrics-server, requests are still reported and UtilizationAvailable stays false
+		var nodeUsage map[string]resourceUsage
+		if cs, err := getClientsetForCluster(clusterID); err == nil {
+			metricsCtx, cancel := context.WithTimeout(ctx, summaryUtilizationTimeout)
+			nodeUsage, _ = fetchNodeUsage(metricsCtx, cs)
+			cancel()
+		}
+
+		tracker.setUtilization(computeUtilization(nodes, pods, nodeUsage), now)
+	}
+}
+
+// computeUtilization returns a summary with only the utilization fields set
+func computeUtilization(nodes []*v1.Node, pods []*v1.Pod, nodeUsage map[string]resourceUsage) models.ClusterSummary {
+	nodeList := make([]v1.Node, 0, len(nodes))
+	for _, node := range nodes {
+		nodeList = append(nodeList, *node)
+	}
+	podList := make([]v1.Pod, 0, len(pods))
+	for _, pod := range pods {
+		podList = append(podList, *pod)
+	}
+
+	var utilization models.ClusterSummary
+	applyUtilization(&utilization, nodeList, podList, nodeUsage)
+	return utilization
+}
+
+// setUtilization stores freshly computed utilization and recomputes the summary
+func (t *summaryTracker) setUtilization(utilization models.ClusterSummary, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.utilization = utilization
+	t.utilizationAt = now
+	t.recompute()
+}
+
+// handler wraps a state mutation in informer callbacks that recompute the summary
+func (t *summaryTracker) handler(apply func(obj interface{}, deleted bool)) cache.ResourceEventHandler {
+	update := func(obj interface{}, deleted bool) {
+		if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+			obj = tombstone.Obj
+		}
+
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		apply(obj, deleted)
+		t.recompute()
+	}
+
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { update(obj, false) },
+		UpdateFunc: func(_, obj interface{}) { update(obj, false) },
+		DeleteFunc: func(obj interface{}) { update(obj, true) },
+	}
+}
+
+// setNode updates a node's readiness; callers must hold t.mu
+func (t *summaryTracker) setNode(name string, ready, deleted bool) {
+	if previous, ok := t.nodes[name]; ok && previous {
+		t.readyNodes--
+	}
+	if deleted {
+		delete(t.nodes, name)
+		return
+	}
+	t.nodes[name] = ready
+	if ready {
+		t.readyNodes++
+	}
+}
+
+// setPod updates a pod's phase; callers must hold t.mu
+func (t *summaryTracker) setPod(key string, phase v1.PodPhase, deleted bool) {
+	if previous, ok := t.pods[key]; ok {
+		t.podPhases[previous]--
+	}
+	if deleted {
+		delete(t.pods, key)
+		return
+	}
+	t.pods[key] = phase
+	t.podPhases[phase]++
+}
+
+// recompute rebuilds the summary from the tracked counts; callers must hold t.mu
+func (t *summaryTracker) recompute() {
+	summary := models.ClusterSummary{
+		TotalNodes:        len(t.nodes),
+		ReadyNodes:        t.readyNodes,
+		TotalPods:         len(t.pods),
+		RunningPods:       t.podPhases[v1.PodRunning],
+		PendingPods:       t.podPhases[v1.PodPending],
+		FailedPods:        t.podPhases[v1.PodFailed],
+		TotalNamespaces:   len(t.namespaces),
+		TotalDeployments:  len(t.deployments),
+		TotalStatefulSets: len(t.statefulSets),
+		TotalDaemonSets:   len(t.daemonSets),
+		TotalReplicaSets:  len(t.replicaSets),
+		TotalServices:     len(t.services),
+		FailedJobs:        len(t.failedJobs),
+
+		CPUUtilization:       t.utilization.CPUUtilization,
+		MemoryUtilization:    t.utilization.MemoryUtilization,
+		CPURequested:         t.utilization.CPURequested,
+		MemoryRequested:      t.utilization.MemoryRequested,
+		UtilizationAvailable: t.utilization.UtilizationAvailable,
+	}
+
+	if summary != t.summary {
+		t.summary = summary
+		t.dirty = true
+		t.updatedAt = time.Now()
+	}
+}
+
+func (t *summaryTracker) synced() bool {
+	for _, hasSynced := range t.hasSynced {
+		if !hasSynced() {
+			return false
+		}
+	}
+	return true
+}
+
+func (t *summaryTracker) snapshot(clusterID string) models.SummaryUpdate {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return models.SummaryUpdate{
+		ClusterID: clusterID,
+		Summary:   t.summary,
+		Synced:    t.synced(),
+		UpdatedAt: t.updatedAt,
+	}
+}
+
+func (t *summaryTracker) takeIfDirty(clusterID string) (models.SummaryUpdate, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// Hold back pushes during the initial list so clients don't see counts climb from zero
+	if !t.dirty || !t.synced() {
+		return models.SummaryUpdate{}, false
+	}
+	t.dirty = false
+
+	return models.SummaryUpdate{
+		ClusterID: clusterID,
+		Summary:   t.summary,
+		Synced:    true,
+		UpdatedAt: t.updatedAt,
+	}, true
+}
+
+// keySetter records the presence of namespaced objects by namespace/name
+func keySetter(set map[string]bool) func(obj interface{}, deleted bool) {
+	return func(obj interface{}, deleted bool) {
+		key, err := cache.MetaNamespaceKeyFunc(obj)
+		if err != nil {
+			return
+		}
+		if deleted {
+			delete(set, key)
+		} else {
+			set[key] = true
+		}
+	}
+}
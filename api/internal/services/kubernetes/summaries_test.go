@@ -0,0 +1,79 @@
+package kubernetes
+
+import (
+	"testing"
+	"time"
+
+	"kubey/api/internal/models"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestSummaryTrackerCounts(t *testing.T) {
+	tracker := &summaryTracker{
+		nodes:       map[string]bool{},
+		pods:        map[string]v1.PodPhase{},
+		namespaces:  map[string]bool{},
+		deployments: map[string]bool{},
+		services:    map[string]bool{},
+		podPhases:   map[v1.PodPhase]int{},
+	}
+
+	tracker.setNode("node-a", true, false)
+	tracker.setNode("node-b", false, false)
+	tracker.setPod("default/web-1", v1.PodPending, false)
+	tracker.setPod("default/web-2", v1.PodRunning, false)
+	tracker.recompute()
+
+	if !tracker.dirty {
+		t.Fatalf("expected tracker to be dirty after changes")
+	}
+	if s := tracker.summary; s.TotalNodes != 2 || s.ReadyNodes != 1 || s.TotalPods != 2 || s.PendingPods != 1 || s.RunningPods != 1 {
+		t.Fatalf("unexpected summary: %+v", s)
+	}
+
+	// Transitions move counts between buckets instead of adding to them.
+	tracker.dirty = false
+	tracker.setNode("node-b", true, false)
+	tracker.setPod("default/web-1", v1.PodRunning, false)
+	tracker.setPod("default/web-2", "", true)
+	tracker.recompute()
+
+	if s := tracker.summary; s.ReadyNodes != 2 || s.TotalPods != 1 || s.PendingPods != 0 || s.RunningPods != 1 {
+		t.Fatalf("unexpected summary after transitions: %+v", s)
+	}
+
+	// Re-applying the same state does not mark the summary as changed.
+	tracker.dirty = false
+	tracker.setPod("default/web-1", v1.PodRunning, false)
+	tracker.recompute()
+	if tracker.dirty {
+		t.Fatalf("expected no change to be pushed for identical state")
+	}
+}
+
+func TestSummaryTrackerUtilization(t *testing.T) {
+	tracker := &summaryTracker{
+		nodes:     map[string]bool{},
+		pods:      map[string]v1.PodPhase{},
+		podPhases: map[v1.PodPhase]int{},
+	}
+	tracker.setNode("node-a", true, false)
+	tracker.recompute()
+	tracker.dirty = false
+
+	tracker.setUtilization(models.ClusterSummary{CPURequested: 25, MemoryRequested: 50, CPUUtilization: 10, UtilizationAvailable: true}, time.Now())
+	if !tracker.dirty {
+		t.Fatalf("expected a utilization change to be pushed")
+	}
+	if s := tracker.summary; s.TotalNodes != 1 || s.CPURequested != 25 || s.MemoryRequested != 50 || s.CPUUtilization != 10 || !s.UtilizationAvailable {
+		t.Fatalf("unexpected summary: %+v", s)
+	}
+
+	// Count changes keep the last computed utilization.
+	tracker.setPod("default/web-1", v1.PodRunning, false)
+	tracker.recompute()
+	if s := tracker.summary; s.TotalPods != 1 || s.CPURequested != 25 || !s.UtilizationAvailable {
+		t.Fatalf("expected utilization to survive a recompute: %+v", s)
+	}
+}
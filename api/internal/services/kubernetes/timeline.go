@@ -0,0 +1,416 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"kubey/api/internal/models"
+	"kubey/api/internal/store"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+// timelineTimeout bounds the API requests that build a timeline, so one slow cluster cannot hold
+// the request open
+const timelineTimeout = 30 * time.Second
+
+// timelineWorkload is the resolved scope of a workload timeline
+type timelineWorkload struct {
+	ref      models.ObjectReference
+	selector labels.Selector
+}
+
+// GetTimeline builds a chronological timeline for a namespace, or for a single workload when
+// workload is non-nil. It merges rollout revisions, container restarts and terminations,
+// Warning events (live and recorded), node condition transitions and HPA scaling decisions.
+func GetTimeline(clusterID, namespace string, workload *models.ObjectReference, since, until time.Time, s *store.Store) (*models.Timeline, error) {
+	cs, err := getClientsetForCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timelineTimeout)
+	defer cancel()
+
+	timeline := &models.Timeline{
+		ClusterID: clusterID,
+		Namespace: namespace,
+		Workload:  workload,
+		Since:     since,
+		Until:     until,
+		Entries:   []models.TimelineEntry{},
+	}
+
+	var scope *timelineWorkload
+	podSelector := labels.Everything()
+	if workload != nil {
+		scope, err = resolveTimelineWorkload(ctx, cs, namespace, workload)
+		if err != nil {
+			return nil, err
+		}
+		podSelector = scope.selector
+	}
+
+	pods, err := cs.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: podSelector.String()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %v", err)
+	}
+
+	// Names of objects whose events belong on this timeline
+	related := map[string]bool{}
+	if scope != nil {
+		related[scope.ref.Kind+"/"+scope.ref.Name] = true
+	}
+
+	add := func(entry models.TimelineEntry) {
+		if entry.Time.Before(since) || entry.Time.After(until) {
+			return
+		}
+		timeline.Entries = append(timeline.Entries, entry)
+	}
+
+	// Rollout revisions
+	rollouts, err := getRolloutEntries(ctx, cs, namespace, scope)
+	if err != nil {
+		log.Printf("Failed to get rollout history for %s/%s: %v", clusterID, namespace, err)
+	}
+	for _, entry := range rollouts {
+		related[entry.Object.Kind+"/"+entry.Object.Name] = true
+		add(entry)
+	}
+
+	// Container restarts and terminations
+	nodeNames := map[string]bool{}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		related["Pod/"+pod.Name] = true
+		if pod.Spec.NodeName != "" {
+			nodeNames[pod.Spec.NodeName] = true
+		}
+		for _, entry := range getPodTimelineEntries(pod) {
+			add(entry)
+		}
+	}
+
+	// HPA scaling decisions
+	hpas, err := cs.AutoscalingV2().HorizontalPodAutoscalers(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		log.Printf("Failed to list HPAs for %s/%s: %v", clusterID, namespace, err)
+	} else {
+		for _, hpa := range hpas.Items {
+			target := hpa.Spec.ScaleTargetRef
+			if scope != nil && (target.Kind != scope.ref.Kind || target.Name != scope.ref.Name) {
+				continue
+			}
+			related["HorizontalPodAutoscaler/"+hpa.Name] = true
+			if hpa.Status.LastScaleTime != nil {
+				add(models.TimelineEntry{
+					Time:    hpa.Status.LastScaleTime.Time,
+					Type:    "scaling",
+					Object:  models.ObjectReference{Kind: "HorizontalPodAutoscaler", Namespace: namespace, Name: hpa.Name},
+					Summary: fmt.Sprintf("HPA scaled %s/%s to %d replicas", target.Kind, target.Name, hpa.Status.DesiredReplicas),
+					Details: fmt.Sprintf("current %d, desired %d (min %d, max %d)", hpa.Status.CurrentReplicas, hpa.Status.DesiredReplicas, valueOr(hpa.Spec.MinReplicas, 1), hpa.Spec.MaxReplicas),
+				})
+			}
+		}
+	}
+
+	// Warning events and HPA rescale events, live and recorded
+	for _, event := range getTimelineEvents(ctx, cs, s, clusterID, namespace, since, until) {
+		if scope != nil && !related[event.Object.Kind+"/"+event.Object.Name] {
+			continue
+		}
+
+		entryType := "warning"
+		if event.Reason == "SuccessfulRescale" {
+			entryType = "scaling"
+		}
+		add(models.TimelineEntry{
+			Time:    event.LastTimestamp,
+			Type:    entryType,
+			Object:  event.Object,
+			Summary: fmt.Sprintf("%s: %s", event.Reason, event.Message),
+			Details: fmt.Sprintf("seen %d times since %s", event.Count, event.FirstTimestamp.Format(time.RFC3339)),
+		})
+	}
+
+	// Node condition transitions for nodes hosting the pods
+	for nodeName := range nodeNames {
+		node, err := cs.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+		if err != nil {
+			log.Printf("Failed to get node %s for timeline: %v", nodeName, err)
+			continue
+		}
+		for _, condition := range getNodeConditions(node) {
+			add(models.TimelineEntry{
+				Time:    condition.LastTransitionTime,
+				Type:    "node-condition",
+				Object:  models.ObjectReference{Kind: "Node", Name: nodeName},
+				Summary: fmt.Sprintf("Node %s condition %s=%s", nodeName, condition.Type, condition.Status),
+				Details: joinNonEmpty(condition.Reason, condition.Message),
+			})
+		}
+	}
+
+	sort.SliceStable(timeline.Entries, func(i, j int) bool {
+		return timeline.Entries[i].Time.Before(timeline.Entries[j].Time)
+	})
+
+	return timeline, nil
+}
+
+// TimelineMarkdown renders a timeline as a Markdown document for postmortems
+func TimelineMarkdown(timeline *models.Timeline) string {
+	var b strings.Builder
+
+	scope := "namespace " + timeline.Namespace
+	if timeline.Workload != nil {
+		scope = fmt.Sprintf("%s %s/%s", timeline.Workload.Kind, timeline.Namespace, timeline.Workload.Name)
+	}
+
+	fmt.Fprintf(&b, "# Incident timeline: %s\n\n", scope)
+	fmt.Fprintf(&b, "- **Cluster:** %s\n", timeline.ClusterID)
+	fmt.Fprintf(&b, "- **From:** %s\n", timeline.Since.UTC().Format(time.RFC3339))
+	fmt.Fprintf(&b, "- **To:** %s\n\n", timeline.Until.UTC().Format(time.RFC3339))
+
+	if len(timeline.Entries) == 0 {
+		b.WriteString("_No activity recorded in this window._\n")
+		return b.String()
+	}
+
+	b.WriteString("| Time (UTC) | Type | Object | Summary | Details |\n")
+	b.WriteString("|---|---|---|---|---|\n")
+	for _, entry := range timeline.Entries {
+		object := entry.Object.Kind + "/" + entry.Object.Name
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n",
+			entry.Time.UTC().Format("2006-01-02 15:04:05"),
+			entry.Type,
+			escapeMarkdownCell(object),
+			escapeMarkdownCell(entry.Summary),
+			escapeMarkdownCell(entry.Details),
+		)
+	}
+
+	return b.String()
+}
+
+// resolveTimelineWorkload looks up a workload and its pod selector
+func resolveTimelineWorkload(ctx context.Context, cs kubernetes.Interface, namespace string, workload *models.ObjectReference) (*timelineWorkload, error) {
+	var selector *metav1.LabelSelector
+	var uid string
+	var err error
+
+	switch workload.Kind {
+	case "Deployment":
+		d, getErr := cs.AppsV1().Deployments(namespace).Get(ctx, workload.Name, metav1.GetOptions{})
+		if err = getErr; err == nil {
+			selector, uid = d.Spec.Selector, string(d.UID)
+		}
+	case "StatefulSet":
+		sts, getErr := cs.AppsV1().StatefulSets(namespace).Get(ctx, workload.Name, metav1.GetOptions{})
+		if err = getErr; err == nil {
+			selector, uid = sts.Spec.Selector, string(sts.UID)
+		}
+	case "DaemonSet":
+		ds, getErr := cs.AppsV1().DaemonSets(namespace).Get(ctx, workload.Name, metav1.GetOptions{})
+		if err = getErr; err == nil {
+			selector, uid = ds.Spec.Selector, string(ds.UID)
+		}
+	default:
+		return nil, fmt.Errorf("%w: unsupported workload kind %q, expected Deployment, StatefulSet or DaemonSet", ErrInvalidArgument, workload.Kind)
+	}
+
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("%w: %s %s/%s", ErrResourceNotFound, workload.Kind, namespace, workload.Name)
+		}
+		return nil, fmt.Errorf("failed to get %s: %v", workload.Kind, err)
+	}
+
+	labelSelector, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector on %s %s: %v", workload.Kind, workload.Name, err)
+	}
+
+	return &timelineWorkload{
+		ref:      models.ObjectReference{Kind: workload.Kind, Namespace: namespace, Name: workload.Name, UID: uid},
+		selector: labelSelector,
+	}, nil
+}
+
+// getRolloutEntries returns Deployment revisions (from ReplicaSets) and StatefulSet/DaemonSet
+// revisions (from ControllerRevisions), limited to the workload when one is given
+func getRolloutEntries(ctx context.Context, cs kubernetes.Interface, namespace string, scope *timelineWorkload) ([]models.TimelineEntry, error) {
+	var entries []models.TimelineEntry
+
+	if scope == nil || scope.ref.Kind == "Deployment" {
+		replicaSets, err := cs.AppsV1().ReplicaSets(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list replica sets: %v", err)
+		}
+
+		for _, rs := range replicaSets.Items {
+			owner := metav1.GetControllerOf(&rs)
+			if owner == nil || owner.Kind != "Deployment" {
+				continue
+			}
+			if scope != nil && string(owner.UID) != scope.ref.UID {
+				continue
+			}
+
+			revision := rs.Annotations["deployment.kubernetes.io/revision"]
+			var images []string
+			for _, container := range rs.Spec.Template.Spec.Containers {
+				images = append(images, container.Image)
+			}
+
+			entries = append(entries, models.TimelineEntry{
+				Time:    rs.CreationTimestamp.Time,
+				Type:    "rollout",
+				Object:  models.ObjectReference{Kind: "ReplicaSet", Namespace: namespace, Name: rs.Name},
+				Summary: fmt.Sprintf("Deployment %s rolled out revision %s", owner.Name, revision),
+				Details: "images: " + strings.Join(images, ", "),
+			})
+		}
+	}
+
+	if scope == nil || scope.ref.Kind == "StatefulSet" || scope.ref.Kind == "DaemonSet" {
+		revisions, err := cs.AppsV1().ControllerRevisions(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list controller revisions: %v", err)
+		}
+
+		for _, revision := range revisions.Items {
+			owner := metav1.GetControllerOf(&revision)
+			if owner == nil {
+				continue
+			}
+			if scope != nil && string(owner.UID) != scope.ref.UID {
+				continue
+			}
+
+			entries = append(entries, models.TimelineEntry{
+				Time:    revision.CreationTimestamp.Time,
+				Type:    "rollout",
+				Object:  models.ObjectReference{Kind: "ControllerRevision", Namespace: namespace, Name: revision.Name},
+				Summary: fmt.Sprintf("%s %s rolled out revision %d", owner.Kind, owner.Name, revision.Revision),
+			})
+		}
+	}
+
+	return entries, nil
+}
+
+// getPodTimelineEntries returns container restarts and terminations for a pod
+func getPodTimelineEntries(pod *v1.Pod) []models.TimelineEntry {
+	var entries []models.TimelineEntry
+	podRef := models.ObjectReference{Kind: "Pod", Namespace: pod.Namespace, Name: pod.Name}
+
+	statuses := append(append([]v1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		if last := status.LastTerminationState.Terminated; last != nil {
+			entries = append(entries, models.TimelineEntry{
+				Time:    last.FinishedAt.Time,
+				Type:    "termination",
+				Object:  podRef,
+				Summary: fmt.Sprintf("Container %s terminated: %s (exit code %d)", status.Name, last.Reason, last.ExitCode),
+				Details: last.Message,
+			})
+		}
+
+		if running := status.State.Running; running != nil && status.RestartCount > 0 {
+			entries = append(entries, models.TimelineEntry{
+				Time:    running.StartedAt.Time,
+				Type:    "restart",
+				Object:  podRef,
+				Summary: fmt.Sprintf("Container %s restarted (restart #%d)", status.Name, status.RestartCount),
+			})
+		}
+
+		if current := status.State.Terminated; current != nil {
+			entries = append(entries, models.TimelineEntry{
+				Time:    current.FinishedAt.Time,
+				Type:    "termination",
+				Object:  podRef,
+				Summary: fmt.Sprintf("Container %s terminated: %s (exit code %d)", status.Name, current.Reason, current.ExitCode),
+				Details: current.Message,
+			})
+		}
+	}
+
+	return entries
+}
+
+// getTimelineEvents returns Warning and HPA rescale events from the live cluster and the
+// recorded history, de-duplicated by UID
+func getTimelineEvents(ctx context.Context, cs kubernetes.Interface, s *store.Store, clusterID, namespace string, since, until time.Time) []models.KubeEvent {
+	seen := map[string]bool{}
+	var events []models.KubeEvent
+
+	keep := func(event models.KubeEvent) {
+		if event.Type != v1.EventTypeWarning && event.Reason != "SuccessfulRescale" {
+			return
+		}
+		if seen[event.UID] {
+			return
+		}
+		seen[event.UID] = true
+		events = append(events, event)
+	}
+
+	live, err := cs.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		log.Printf("Failed to list events for %s/%s: %v", clusterID, namespace, err)
+	} else {
+		for i := range live.Items {
+			keep(toKubeEvent(clusterID, &live.Items[i]))
+		}
+	}
+
+	if s != nil {
+		recorded, err := s.QueryEvents(models.EventQuery{
+			ClusterID: clusterID,
+			Namespace: namespace,
+			Since:     since,
+			Until:     until,
+		})
+		if err != nil {
+			log.Printf("Failed to query recorded events for %s/%s: %v", clusterID, namespace, err)
+		}
+		for _, event := range recorded {
+			keep(event)
+		}
+	}
+
+	return events
+}
+
+func valueOr(value *int32, defaultValue int32) int32 {
+	if value == nil {
+		return defaultValue
+	}
+	return *value
+}
+
+func joinNonEmpty(parts ...string) string {
+	var nonEmpty []string
+	for _, part := range parts {
+		if part != "" {
+			nonEmpty = append(nonEmpty, part)
+		}
+	}
+	return strings.Join(nonEmpty, ": ")
+}
+
+func escapeMarkdownCell(value string) string {
+	value = strings.ReplaceAll(value, "|", "\\|")
+	return strings.ReplaceAll(value, "\n", " ")
+}
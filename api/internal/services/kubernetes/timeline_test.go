@@ -0,0 +1,68 @@
+package kubernetes
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"kubey/api/internal/models"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetPodTimelineEntries(t *testing.T) {
+	crashed := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	restarted := crashed.Add(10 * time.Second)
+
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "shop"},
+		Status: v1.PodStatus{
+			ContainerStatuses: []v1.ContainerStatus{{
+				Name:         "app",
+				RestartCount: 3,
+				State:        v1.ContainerState{Running: &v1.ContainerStateRunning{StartedAt: metav1.NewTime(restarted)}},
+				LastTerminationState: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{
+					Reason:     "OOMKilled",
+					ExitCode:   137,
+					FinishedAt: metav1.NewTime(crashed),
+				}},
+			}},
+		},
+	}
+
+	entries := getPodTimelineEntries(pod)
+	if len(entries) != 2 {
+		t.Fatalf("expected termination and restart entries, got %+v", entries)
+	}
+	if entries[0].Type != "termination" || !entries[0].Time.Equal(crashed) || !strings.Contains(entries[0].Summary, "OOMKilled") {
+		t.Fatalf("unexpected termination entry: %+v", entries[0])
+	}
+	if entries[1].Type != "restart" || !entries[1].Time.Equal(restarted) || !strings.Contains(entries[1].Summary, "#3") {
+		t.Fatalf("unexpected restart entry: %+v", entries[1])
+	}
+}
+
+func TestTimelineMarkdown(t *testing.T) {
+	timeline := &models.Timeline{
+		ClusterID: "context-prod",
+		Namespace: "shop",
+		Workload:  &models.ObjectReference{Kind: "Deployment", Name: "web"},
+		Since:     time.Date(2025, 1, 1, 11, 0, 0, 0, time.UTC),
+		Until:     time.Date(2025, 1, 1, 13, 0, 0, 0, time.UTC),
+		Entries: []models.TimelineEntry{{
+			Time:    time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC),
+			Type:    "warning",
+			Object:  models.ObjectReference{Kind: "Pod", Name: "web-1"},
+			Summary: "BackOff: restarting | failed\ncontainer",
+		}},
+	}
+
+	markdown := TimelineMarkdown(timeline)
+	if !strings.HasPrefix(markdown, "# Incident timeline: Deployment shop/web\n") {
+		t.Fatalf("unexpected heading:\n%s", markdown)
+	}
+	if !strings.Contains(markdown, "| 2025-01-01 12:00:00 | warning | Pod/web-1 | BackOff: restarting \\| failed container |  |") {
+		t.Fatalf("expected escaped table row:\n%s", markdown)
+	}
+}
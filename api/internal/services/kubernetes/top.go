@@ -0,0 +1,306 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"kubey/api/internal/models"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TopOptions selects what a top report ranks and across which clusters
+type TopOptions struct {
+	ClusterIDs []string // All clusters in the kubeconfig when empty
+	Kind       string   // pod, container, namespace or node
+	SortBy     string   // cpu, memory, restarts or age
+	Limit      int
+}
+
+// topTimeout bounds the requests to every cluster of a top report, so that an unreachable
+// cluster is reported as failed instead of holding the request open
+const topTimeout = 30 * time.Second
+
+var (
+	topKinds = map[string]bool{"pod": true, "container": true, "namespace": true, "node": true}
+	topSorts = map[string]bool{"cpu": true, "memory": true, "restarts": true, "age": true}
+)
+
+// GetTop ranks pods, containers, namespaces or nodes across clusters, like a fleet-wide kubectl top.
+// Clusters are queried in parallel; clusters that cannot be reached are reported in Failed.
+func GetTop(opts TopOptions) (*models.TopReport, error) {
+	if !topKinds[opts.Kind] {
+		return nil, fmt.Errorf("%w: kind must be pod, container, namespace or node", ErrInvalidArgument)
+	}
+	if !topSorts[opts.SortBy] {
+		return nil, fmt.Errorf("%w: sort must be cpu, memory, restarts or age", ErrInvalidArgument)
+	}
+
+	contextNames, err := listContextNames()
+	if err != nil {
+		return nil, err
+	}
+	clusterIDs, err := selectClusters(contextNames, opts.ClusterIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), topTimeout)
+	defer cancel()
+	results := fanOut(clusterIDs, func(clusterID string) ([]models.TopEntry, error) {
+		return getClusterTop(ctx, clusterID, opts.Kind)
+	})
+
+	report := &models.TopReport{
+		Kind:     opts.Kind,
+		SortBy:   opts.SortBy,
+		Limit:    opts.Limit,
+		Clusters: clusterIDs,
+		Items:    []models.TopEntry{},
+	}
+	var entries []models.TopEntry
+	for _, res := range results {
+		if res.err != nil {
+			log.Printf("Top %s unavailable for cluster %s: %v", opts.Kind, res.key, res.err)
+			report.Failed = append(report.Failed, models.TopClusterError{ClusterID: res.key, Error: res.err.Error()})
+			continue
+		}
+		entries = append(entries, res.value...)
+	}
+	sort.Slice(report.Failed, func(i, j int) bool { return report.Failed[i].ClusterID < report.Failed[j].ClusterID })
+
+	report.Items = append(report.Items, rankTop(entries, opts.SortBy, opts.Limit)...)
+	return report, nil
+}
+
+// selectClusters returns the sorted IDs of the selected clusters, or of every context when none
+// are selected. A cluster selected more than once is only returned once.
+func selectClusters(contextNames, selectedIDs []string) ([]string, error) {
+	selected := map[string]bool{}
+	for _, clusterID := range selectedIDs {
+		selected[clusterID] = true
+	}
+	clusterIDs := []string{}
+	for _, name := range contextNames {
+		clusterID := clusterIDForContext(name)
+		if len(selectedIDs) == 0 || selected[clusterID] {
+			clusterIDs = append(clusterIDs, clusterID)
+			delete(selected, clusterID)
+		}
+	}
+	if len(selected) > 0 {
+		missing := slices.Sorted(maps.Keys(selected))
+		return nil, fmt.Errorf("%w: %s", ErrClusterNotFound, strings.Join(missing, ", "))
+	}
+	sort.Strings(clusterIDs)
+	return clusterIDs, nil
+}
+
+// getClusterTop lists the objects of one cluster with their usage as unranked top entries
+func getClusterTop(ctx context.Context, clusterID, kind string) ([]models.TopEntry, error) {
+	cs, err := getClientsetForCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	pods, err := cs.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %v", err)
+	}
+
+	switch kind {
+	case "node":
+		nodes, err := cs.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list nodes: %v", err)
+		}
+		nodeUsage, err := fetchNodeUsage(ctx, cs)
+		if err != nil {
+			log.Printf("Node usage unavailable for cluster %s: %v", clusterID, err)
+		}
+		return buildNodeTop(clusterID, nodes.Items, pods.Items, nodeUsage), nil
+	case "namespace":
+		namespaces, err := cs.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list namespaces: %v", err)
+		}
+		podUsage, err := fetchPodUsage(ctx, cs, "")
+		if err != nil {
+			log.Printf("Pod usage unavailable for cluster %s: %v", clusterID, err)
+		}
+		return buildNamespaceTop(clusterID, namespaces.Items, pods.Items, podUsage), nil
+	default:
+		podUsage, err := fetchPodUsage(ctx, cs, "")
+		if err != nil {
+			log.Printf("Pod usage unavailable for cluster %s: %v", clusterID, err)
+		}
+		return buildPodTop(clusterID, kind == "container", pods.Items, podUsage), nil
+	}
+}
+
+// buildPodTop returns one entry per pod, or per container when containers is true
+func buildPodTop(clusterID string, containers bool, pods []v1.Pod, podUsage map[string]map[string]resourceUsage) []models.TopEntry {
+	var entries []models.TopEntry
+	for i := range pods {
+		pod := &pods[i]
+		usage, hasUsage := podUsage[pod.Namespace+"/"+pod.Name]
+
+		if !containers {
+			entry := models.TopEntry{
+				ClusterID:      clusterID,
+				Kind:           "pod",
+				Name:           pod.Name,
+				Namespace:      pod.Namespace,
+				Node:           pod.Spec.NodeName,
+				Restarts:       getTotalRestartCount(pod),
+				CreatedAt:      pod.CreationTimestamp.Time,
+				UsageAvailable: hasUsage,
+			}
+			for _, u := range usage {
+				entry.CPU += u.CPU
+				entry.Memory += u.Memory
+			}
+			entries = append(entries, entry)
+			continue
+		}
+
+		for _, container := range pod.Spec.Containers {
+			entry := models.TopEntry{
+				ClusterID: clusterID,
+				Kind:      "container",
+				Name:      container.Name,
+				Namespace: pod.Namespace,
+				Pod:       pod.Name,
+				Node:      pod.Spec.NodeName,
+				CreatedAt: pod.CreationTimestamp.Time,
+			}
+			for _, status := range pod.Status.ContainerStatuses {
+				if status.Name == container.Name {
+					entry.Restarts = status.RestartCount
+					if status.State.Running != nil {
+						entry.CreatedAt = status.State.Running.StartedAt.Time
+					}
+				}
+			}
+			if u, ok := usage[container.Name]; ok {
+				entry.CPU = u.CPU
+				entry.Memory = u.Memory
+				entry.UsageAvailable = true
+			}
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// buildNamespaceTop sums pod usage and restarts per namespace
+func buildNamespaceTop(clusterID string, namespaces []v1.Namespace, pods []v1.Pod, podUsage map[string]map[string]resourceUsage) []models.TopEntry {
+	byName := make(map[string]*models.TopEntry, len(namespaces))
+	entries := make([]models.TopEntry, len(namespaces))
+	for i, ns := range namespaces {
+		entries[i] = models.TopEntry{
+			ClusterID:      clusterID,
+			Kind:           "namespace",
+			Name:           ns.Name,
+			CreatedAt:      ns.CreationTimestamp.Time,
+			UsageAvailable: podUsage != nil,
+		}
+		byName[ns.Name] = &entries[i]
+	}
+
+	for i := range pods {
+		pod := &pods[i]
+		entry, ok := byName[pod.Namespace]
+		if !ok {
+			continue
+		}
+		entry.Pods++
+		entry.Restarts += getTotalRestartCount(pod)
+		for _, u := range podUsage[pod.Namespace+"/"+pod.Name] {
+			entry.CPU += u.CPU
+			entry.Memory += u.Memory
+		}
+	}
+	return entries
+}
+
+// buildNodeTop combines node usage with the pod counts and restarts of the pods scheduled on each node
+func buildNodeTop(clusterID string, nodes []v1.Node, pods []v1.Pod, nodeUsage map[string]resourceUsage) []models.TopEntry {
+	byName := make(map[string]*models.TopEntry, len(nodes))
+	entries := make([]models.TopEntry, len(nodes))
+	for i, node := range nodes {
+		entries[i] = models.TopEntry{
+			ClusterID: clusterID,
+			Kind:      "node",
+			Name:      node.Name,
+			CreatedAt: node.CreationTimestamp.Time,
+		}
+		if u, ok := nodeUsage[node.Name]; ok {
+			entries[i].CPU = u.CPU
+			entries[i].Memory = u.Memory
+			entries[i].UsageAvailable = true
+		}
+		byName[node.Name] = &entries[i]
+	}
+
+	for i := range pods {
+		pod := &pods[i]
+		entry, ok := byName[pod.Spec.NodeName]
+		if !ok {
+			continue
+		}
+		entry.Pods++
+		entry.Restarts += getTotalRestartCount(pod)
+	}
+	return entries
+}
+
+// rankTop sorts entries by sortBy, largest or oldest first, and keeps the first limit.
+// Entries without usage are left out when ranking by CPU or memory.
+func rankTop(entries []models.TopEntry, sortBy string, limit int) []models.TopEntry {
+	if sortBy == "cpu" || sortBy == "memory" {
+		var measured []models.TopEntry
+		for _, entry := range entries {
+			if entry.UsageAvailable {
+				measured = append(measured, entry)
+			}
+		}
+		entries = measured
+	}
+
+	less := func(a, b *models.TopEntry) bool {
+		switch sortBy {
+		case "cpu":
+			if a.CPU != b.CPU {
+				return a.CPU > b.CPU
+			}
+		case "memory":
+			if a.Memory != b.Memory {
+				return a.Memory > b.Memory
+			}
+		case "restarts":
+			if a.Restarts != b.Restarts {
+				return a.Restarts > b.Restarts
+			}
+		case "age":
+			if !a.CreatedAt.Equal(b.CreatedAt) {
+				return a.CreatedAt.Before(b.CreatedAt)
+			}
+		}
+		return strings.Join([]string{a.ClusterID, a.Namespace, a.Pod, a.Name}, "/") <
+			strings.Join([]string{b.ClusterID, b.Namespace, b.Pod, b.Name}, "/")
+	}
+	sort.Slice(entries, func(i, j int) bool { return less(&entries[i], &entries[j]) })
+
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries
+}
@@ -0,0 +1,105 @@
+package kubernetes
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTopRanking(t *testing.T) {
+	now := time.Now()
+	pod := func(namespace, name, node string, age time.Duration, restarts int32) v1.Pod {
+		return v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, CreationTimestamp: metav1.NewTime(now.Add(-age))},
+			Spec:       v1.PodSpec{NodeName: node, Containers: []v1.Container{{Name: "app"}, {Name: "proxy"}}},
+			Status: v1.PodStatus{ContainerStatuses: []v1.ContainerStatus{
+				{Name: "app", RestartCount: restarts},
+			}},
+		}
+	}
+	pods := []v1.Pod{
+		pod("shop", "web-1", "node-a", time.Hour, 0),
+		pod("shop", "web-2", "node-b", 2*time.Hour, 4),
+		pod("batch", "report", "node-a", 72*time.Hour, 1),
+	}
+	usage := map[string]map[string]resourceUsage{
+		"shop/web-1": {"app": {CPU: 300, Memory: 100 << 20}, "proxy": {CPU: 50, Memory: 20 << 20}},
+		"shop/web-2": {"app": {CPU: 100, Memory: 400 << 20}, "proxy": {CPU: 10, Memory: 20 << 20}},
+	}
+
+	podTop := rankTop(buildPodTop("context-a", false, pods, usage), "cpu", 10)
+	if len(podTop) != 2 || podTop[0].Name != "web-1" || podTop[0].CPU != 350 {
+		t.Fatalf("unexpected pods by cpu: %+v", podTop)
+	}
+
+	containerTop := rankTop(buildPodTop("context-a", true, pods, usage), "memory", 1)
+	if len(containerTop) != 1 || containerTop[0].Pod != "web-2" || containerTop[0].Name != "app" {
+		t.Fatalf("unexpected containers by memory: %+v", containerTop)
+	}
+
+	restartTop := rankTop(buildPodTop("context-a", false, pods, usage), "restarts", 10)
+	if len(restartTop) != 3 || restartTop[0].Name != "web-2" || restartTop[2].Name != "web-1" {
+		t.Fatalf("unexpected pods by restarts: %+v", restartTop)
+	}
+
+	ageTop := rankTop(buildPodTop("context-a", false, pods, usage), "age", 1)
+	if ageTop[0].Name != "report" {
+		t.Fatalf("expected the oldest pod first, got %+v", ageTop)
+	}
+
+	namespaces := []v1.Namespace{
+		{ObjectMeta: metav1.ObjectMeta{Name: "shop"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "batch"}},
+	}
+	nsTop := rankTop(buildNamespaceTop("context-a", namespaces, pods, usage), "memory", 10)
+	if nsTop[0].Name != "shop" || nsTop[0].Pods != 2 || nsTop[0].Memory != 540<<20 || nsTop[0].Restarts != 4 {
+		t.Fatalf("unexpected namespaces by memory: %+v", nsTop)
+	}
+
+	nodes := []v1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "node-b"}},
+	}
+	nodeTop := rankTop(buildNodeTop("context-a", nodes, pods, map[string]resourceUsage{"node-a": {CPU: 900}}), "cpu", 10)
+	if len(nodeTop) != 1 || nodeTop[0].Name != "node-a" || nodeTop[0].Pods != 2 || nodeTop[0].Restarts != 1 {
+		t.Fatalf("unexpected nodes by cpu: %+v", nodeTop)
+	}
+}
+
+func TestSelectClusters(t *testing.T) {
+	contexts := []string{"prod", "staging", "dev"}
+	a, b := clusterIDForContext("prod"), clusterIDForContext("staging")
+
+	all, err := selectClusters(contexts, nil)
+	if err != nil || len(all) != 3 {
+		t.Fatalf("expected every cluster, got %v, %v", all, err)
+	}
+	selected, err := selectClusters(contexts, []string{b, a, b})
+	if err != nil || len(selected) != 2 || selected[0] != a || selected[1] != b {
+		t.Fatalf("expected each selected cluster once, got %v, %v", selected, err)
+	}
+	if _, err := selectClusters(contexts, []string{a, "unknown"}); !errors.Is(err, ErrClusterNotFound) {
+		t.Fatalf("expected an unknown cluster to fail, got %v", err)
+	}
+}
+
+func TestFanOut(t *testing.T) {
+	results := fanOut([]string{"a", "b", "c"}, func(key string) (int, error) {
+		if key == "b" {
+			return 0, ErrClusterNotFound
+		}
+		return len(key), nil
+	})
+	failed := 0
+	for _, res := range results {
+		if res.err != nil {
+			failed++
+		}
+	}
+	if len(results) != 3 || failed != 1 {
+		t.Fatalf("expected 3 results with 1 failure, got %+v", results)
+	}
+}
@@ -0,0 +1,61 @@
+package kubernetes
+
+import (
+	"math"
+
+	"kubey/api/internal/models"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// applyUtilization fills a summary's CPU and memory percentages against node allocatable capacity:
+// requested-vs-allocatable from the pod specs, used-vs-allocatable from metrics-server usage.
+// nodeUsage is nil when metrics-server is unavailable. Usage is only compared against the
+// allocatable capacity of nodes that have a usage sample, so missing samples don't dilute it.
+// Without any sample, utilization is reported as unavailable.
+func applyUtilization(summary *models.ClusterSummary, nodes []v1.Node, pods []v1.Pod, nodeUsage map[string]resourceUsage) {
+	var cpuAllocatable, cpuRequested, cpuUsed, cpuMeasured float64
+	var memoryAllocatable, memoryRequested, memoryUsed, memoryMeasured int64
+
+	scheduled := map[string]bool{}
+	for _, node := range nodes {
+		scheduled[node.Name] = true
+		cpu := float64(node.Status.Allocatable.Cpu().MilliValue())
+		memory := node.Status.Allocatable.Memory().Value()
+		cpuAllocatable += cpu
+		memoryAllocatable += memory
+
+		if usage, ok := nodeUsage[node.Name]; ok {
+			cpuUsed += usage.CPU
+			memoryUsed += usage.Memory
+			cpuMeasured += cpu
+			memoryMeasured += memory
+		}
+	}
+
+	for i := range pods {
+		pod := &pods[i]
+		if !scheduled[pod.Spec.NodeName] || isPodTerminal(pod) {
+			continue
+		}
+		metrics := getPodMetrics(pod, nil)
+		cpuRequested += metrics.CPURequest
+		memoryRequested += metrics.MemoryRequest
+	}
+
+	summary.CPURequested = percentage(cpuRequested, cpuAllocatable)
+	summary.MemoryRequested = percentage(float64(memoryRequested), float64(memoryAllocatable))
+	summary.UtilizationAvailable = cpuMeasured > 0 || memoryMeasured > 0
+	if summary.UtilizationAvailable {
+		summary.CPUUtilization = percentage(cpuUsed, cpuMeasured)
+		summary.MemoryUtilization = percentage(float64(memoryUsed), float64(memoryMeasured))
+	}
+}
+
+// percentage returns part/total as a percentage rounded to one decimal place
+func percentage(part, total float64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return math.Round(part/total*1000) / 10
+}
@@ -0,0 +1,72 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"kubey/api/internal/models"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestApplyUtilization(t *testing.T) {
+	nodes := []v1.Node{{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-a"},
+		Status: v1.NodeStatus{Allocatable: v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse("4"),
+			v1.ResourceMemory: resource.MustParse("8Gi"),
+		}},
+	}}
+	requests := func(cpu, memory string) v1.PodSpec {
+		return v1.PodSpec{
+			NodeName: "node-a",
+			Containers: []v1.Container{{Resources: v1.ResourceRequirements{Requests: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse(cpu),
+				v1.ResourceMemory: resource.MustParse(memory),
+			}}}},
+		}
+	}
+	pods := []v1.Pod{
+		{Spec: requests("1", "2Gi"), Status: v1.PodStatus{Phase: v1.PodRunning}},
+		// Finished pods no longer hold their requests.
+		{Spec: requests("2", "4Gi"), Status: v1.PodStatus{Phase: v1.PodSucceeded}},
+	}
+
+	var summary models.ClusterSummary
+	applyUtilization(&summary, nodes, pods, nil)
+	if summary.CPURequested != 25 || summary.MemoryRequested != 25 {
+		t.Fatalf("unexpected requested percentages: %+v", summary)
+	}
+	if summary.UtilizationAvailable || summary.CPUUtilization != 0 {
+		t.Fatalf("expected usage to be unavailable without metrics: %+v", summary)
+	}
+
+	applyUtilization(&summary, nodes, pods, map[string]resourceUsage{"node-a": {CPU: 3000, Memory: 2 << 30}})
+	if !summary.UtilizationAvailable || summary.CPUUtilization != 75 || summary.MemoryUtilization != 25 {
+		t.Fatalf("unexpected usage percentages: %+v", summary)
+	}
+
+	// A node without a usage sample must not dilute the measured nodes' utilization
+	nodes = append(nodes, v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-b"},
+		Status: v1.NodeStatus{Allocatable: v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse("4"),
+			v1.ResourceMemory: resource.MustParse("8Gi"),
+		}},
+	})
+	summary = models.ClusterSummary{}
+	applyUtilization(&summary, nodes, pods, map[string]resourceUsage{"node-a": {CPU: 3000, Memory: 2 << 30}})
+	if !summary.UtilizationAvailable || summary.CPUUtilization != 75 || summary.MemoryUtilization != 25 {
+		t.Fatalf("unexpected usage percentages with a partial sample: %+v", summary)
+	}
+	if summary.CPURequested != 12.5 {
+		t.Fatalf("expected requests to still count every node: %+v", summary)
+	}
+
+	summary = models.ClusterSummary{}
+	applyUtilization(&summary, nodes, pods, map[string]resourceUsage{})
+	if summary.UtilizationAvailable {
+		t.Fatalf("expected usage to be unavailable without any node sample: %+v", summary)
+	}
+}
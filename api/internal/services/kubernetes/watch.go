@@ -0,0 +1,68 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// informerRegistration attaches event handlers to a cluster's shared informer factory
+type informerRegistration func(clusterID string, factory informers.SharedInformerFactory)
+
+var (
+	registrationsMu       sync.Mutex
+	informerRegistrations []informerRegistration
+)
+
+// registerInformers adds a registration that is applied to every cluster when watches start
+func registerInformers(registration informerRegistration) {
+	registrationsMu.Lock()
+	defer registrationsMu.Unlock()
+	informerRegistrations = append(informerRegistrations, registration)
+}
+
+// StartWatches starts shared informers for every context in the kubeconfig.
+// Features that consume watch events must register before StartWatches is called.
+// Informers stop when ctx is cancelled.
+func StartWatches(ctx context.Context) error {
+	registrationsMu.Lock()
+	registrations := append([]informerRegistration(nil), informerRegistrations...)
+	registrationsMu.Unlock()
+
+	if len(registrations) == 0 {
+		return nil
+	}
+
+	if kubeconfigPath == "" {
+		return fmt.Errorf("kubernetes client not initialized")
+	}
+
+	config, err := clientcmd.LoadFromFile(kubeconfigPath)
+	if err != nil {
+		return fmt.Errorf("failed to load kubeconfig: %v", err)
+	}
+
+	for contextName := range config.Contexts {
+		// Watches are long-lived, so the clientset has no request timeout
+		cs, err := newClientsetForContext(contextName, 0)
+		if err != nil {
+			log.Printf("Failed to start watches for context %s: %v", contextName, err)
+			continue
+		}
+
+		clusterID := clusterIDForContext(contextName)
+		factory := informers.NewSharedInformerFactory(cs, 0)
+		for _, register := range registrations {
+			register(clusterID, factory)
+		}
+		factory.Start(ctx.Done())
+
+		log.Printf("Started watches for context: %s", contextName)
+	}
+
+	return nil
+}
@@ -0,0 +1,339 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"kubey/api/internal/models"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// revisionAnnotation is set by the deployment controller on each ReplicaSet it manages
+const revisionAnnotation = "deployment.kubernetes.io/revision"
+
+// GetStatefulSets returns the statefulsets of a cluster. An empty namespace lists all namespaces.
+func GetStatefulSets(clusterID, namespace string) ([]models.KubeStatefulSet, error) {
+	cs, err := getClientsetForCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := cs.AppsV1().StatefulSets(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list statefulsets: %v", err)
+	}
+
+	statefulSets := []models.KubeStatefulSet{}
+	for i := range list.Items {
+		statefulSets = append(statefulSets, toKubeStatefulSet(&list.Items[i]))
+	}
+	return statefulSets, nil
+}
+
+// GetStatefulSet returns a single statefulset
+func GetStatefulSet(clusterID, namespace, name string) (*models.KubeStatefulSet, error) {
+	cs, err := getClientsetForCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	sts, err := cs.AppsV1().StatefulSets(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, getError(err, "statefulset", namespace, name)
+	}
+
+	statefulSet := toKubeStatefulSet(sts)
+	return &statefulSet, nil
+}
+
+// GetDaemonSets returns the daemonsets of a cluster. An empty namespace lists all namespaces.
+func GetDaemonSets(clusterID, namespace string) ([]models.KubeDaemonSet, error) {
+	cs, err := getClientsetForCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := cs.AppsV1().DaemonSets(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list daemonsets: %v", err)
+	}
+
+	daemonSets := []models.KubeDaemonSet{}
+	for i := range list.Items {
+		daemonSets = append(daemonSets, toKubeDaemonSet(&list.Items[i]))
+	}
+	return daemonSets, nil
+}
+
+// GetDaemonSet returns a single daemonset
+func GetDaemonSet(clusterID, namespace, name string) (*models.KubeDaemonSet, error) {
+	cs, err := getClientsetForCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	ds, err := cs.AppsV1().DaemonSets(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, getError(err, "daemonset", namespace, name)
+	}
+
+	daemonSet := toKubeDaemonSet(ds)
+	return &daemonSet, nil
+}
+
+// GetReplicaSets returns the replicasets of a cluster. An empty namespace lists all namespaces.
+func GetReplicaSets(clusterID, namespace string) ([]models.KubeReplicaSet, error) {
+	cs, err := getClientsetForCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := cs.AppsV1().ReplicaSets(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list replicasets: %v", err)
+	}
+
+	replicaSets := []models.KubeReplicaSet{}
+	for i := range list.Items {
+		replicaSets = append(replicaSets, toKubeReplicaSet(&list.Items[i]))
+	}
+	return replicaSets, nil
+}
+
+// GetReplicaSet returns a single replicaset
+func GetReplicaSet(clusterID, namespace, name string) (*models.KubeReplicaSet, error) {
+	cs, err := getClientsetForCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	rs, err := cs.AppsV1().ReplicaSets(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, getError(err, "replicaset", namespace, name)
+	}
+
+	replicaSet := toKubeReplicaSet(rs)
+	return &replicaSet, nil
+}
+
+// getError maps a failed Get to ErrResourceNotFound or ErrForbidden where it applies.
+// An empty namespace is used for cluster-scoped objects.
+func getError(err error, kind, namespace, name string) error {
+	switch {
+	case apierrors.IsNotFound(err) && namespace == "":
+		return fmt.Errorf("%w: %s %s", ErrResourceNotFound, kind, name)
+	case apierrors.IsNotFound(err):
+		return fmt.Errorf("%w: %s %s/%s", ErrResourceNotFound, kind, namespace, name)
+	case apierrors.IsForbidden(err):
+		return fmt.Errorf("%w: %v", ErrForbidden, err)
+	}
+	return fmt.Errorf("failed to get %s: %v", kind, err)
+}
+
+func toKubeStatefulSet(sts *appsv1.StatefulSet) models.KubeStatefulSet {
+	statefulSet := models.KubeStatefulSet{
+		Name:                sts.Name,
+		Namespace:           sts.Namespace,
+		Replicas:            desiredReplicas(sts.Spec.Replicas),
+		ReadyReplicas:       sts.Status.ReadyReplicas,
+		CurrentReplicas:     sts.Status.CurrentReplicas,
+		UpdatedReplicas:     sts.Status.UpdatedReplicas,
+		AvailableReplicas:   sts.Status.AvailableReplicas,
+		ServiceName:         sts.Spec.ServiceName,
+		PodManagementPolicy: string(sts.Spec.PodManagementPolicy),
+		UpdateStrategy:      string(sts.Spec.UpdateStrategy.Type),
+		Partition:           statefulSetPartition(sts),
+		CurrentRevision:     sts.Status.CurrentRevision,
+		UpdateRevision:      sts.Status.UpdateRevision,
+		Role:                getWorkloadRole(sts.Labels),
+		Labels:              sts.Labels,
+		CreatedAt:           sts.CreationTimestamp.Time,
+		Status:              getStatefulSetStatus(sts),
+	}
+	if sts.Spec.Selector != nil {
+		statefulSet.Selector = sts.Spec.Selector.MatchLabels
+	}
+	return statefulSet
+}
+
+func toKubeDaemonSet(ds *appsv1.DaemonSet) models.KubeDaemonSet {
+	daemonSet := models.KubeDaemonSet{
+		Name:                   ds.Name,
+		Namespace:              ds.Namespace,
+		DesiredNumberScheduled: ds.Status.DesiredNumberScheduled,
+		CurrentNumberScheduled: ds.Status.CurrentNumberScheduled,
+		UpdatedNumberScheduled: ds.Status.UpdatedNumberScheduled,
+		NumberReady:            ds.Status.NumberReady,
+		NumberAvailable:        ds.Status.NumberAvailable,
+		NumberMisscheduled:     ds.Status.NumberMisscheduled,
+		UpdateStrategy:         string(ds.Spec.UpdateStrategy.Type),
+		NodeSelector:           ds.Spec.Template.Spec.NodeSelector,
+		Role:                   getWorkloadRole(ds.Labels),
+		Labels:                 ds.Labels,
+		CreatedAt:              ds.CreationTimestamp.Time,
+		Status:                 getDaemonSetStatus(ds),
+	}
+	if ds.Spec.Selector != nil {
+		daemonSet.Selector = ds.Spec.Selector.MatchLabels
+	}
+	if rolling := ds.Spec.UpdateStrategy.RollingUpdate; rolling != nil && rolling.MaxUnavailable != nil {
+		daemonSet.MaxUnavailable = rolling.MaxUnavailable.String()
+	}
+	return daemonSet
+}
+
+func toKubeReplicaSet(rs *appsv1.ReplicaSet) models.KubeReplicaSet {
+	replicaSet := models.KubeReplicaSet{
+		Name:                 rs.Name,
+		Namespace:            rs.Namespace,
+		Replicas:             desiredReplicas(rs.Spec.Replicas),
+		ReadyReplicas:        rs.Status.ReadyReplicas,
+		AvailableReplicas:    rs.Status.AvailableReplicas,
+		FullyLabeledReplicas: rs.Status.FullyLabeledReplicas,
+		Revision:             rs.Annotations[revisionAnnotation],
+		Role:                 getWorkloadRole(rs.Labels),
+		Labels:               rs.Labels,
+		CreatedAt:            rs.CreationTimestamp.Time,
+		Status:               getReplicaSetStatus(rs),
+	}
+	if rs.Spec.Selector != nil {
+		replicaSet.Selector = rs.Spec.Selector.MatchLabels
+	}
+	for _, owner := range rs.OwnerReferences {
+		if owner.Kind == "Deployment" {
+			replicaSet.Owner = owner.Name
+		}
+	}
+	return replicaSet
+}
+
+// getWorkloadRole returns the "app" label of a workload, like getDeploymentRole
+func getWorkloadRole(labels map[string]string) string {
+	if app, ok := labels["app"]; ok {
+		return app
+	}
+	return "unknown"
+}
+
+// desiredReplicas returns the replica count of a workload spec, which defaults to 1 when unset
+func desiredReplicas(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
+
+// statefulSetPartition returns the rolling update partition; pods with a lower ordinal keep the current revision
+func statefulSetPartition(sts *appsv1.StatefulSet) int32 {
+	rolling := sts.Spec.UpdateStrategy.RollingUpdate
+	if sts.Spec.UpdateStrategy.Type != appsv1.RollingUpdateStatefulSetStrategyType || rolling == nil || rolling.Partition == nil {
+		return 0
+	}
+	return *rolling.Partition
+}
+
+// getStatefulSetStatus derives a statefulset's status from its ready replicas and rollout progress.
+// A partitioned rollout is complete once every pod at or above the partition runs the update revision.
+func getStatefulSetStatus(sts *appsv1.StatefulSet) models.ResourceStatus {
+	desired := desiredReplicas(sts.Spec.Replicas)
+	status := models.ResourceStatus{LastUpdated: time.Now()}
+
+	expectedUpdated := max(desired-statefulSetPartition(sts), 0)
+	rolledOut := sts.Status.UpdateRevision == "" || sts.Status.UpdatedReplicas >= expectedUpdated
+	if expectedUpdated == desired && sts.Status.CurrentRevision != sts.Status.UpdateRevision {
+		rolledOut = false
+	}
+
+	switch {
+	case sts.Status.ObservedGeneration < sts.Generation:
+		status.Phase = "Progressing"
+		status.Reason = "SpecNotObserved"
+		status.Message = "the controller has not yet processed the latest spec"
+	case desired > 0 && sts.Status.ReadyReplicas == 0:
+		status.Phase = "Unavailable"
+		status.Reason = "NoReadyReplicas"
+	case sts.Status.ReadyReplicas < desired:
+		status.Phase = "Progressing"
+		status.Reason = "ReplicasNotReady"
+		status.Message = fmt.Sprintf("%d of %d replicas ready", sts.Status.ReadyReplicas, desired)
+	case sts.Spec.UpdateStrategy.Type == appsv1.OnDeleteStatefulSetStrategyType && sts.Status.CurrentRevision != sts.Status.UpdateRevision:
+		status.Phase = "Available"
+		status.Reason = "OnDeleteUpdatePending"
+		status.Message = "pods are updated only when they are deleted"
+	case !rolledOut:
+		status.Phase = "Progressing"
+		status.Reason = "RollingUpdate"
+		status.Message = fmt.Sprintf("%d of %d replicas updated", sts.Status.UpdatedReplicas, expectedUpdated)
+	default:
+		status.Phase = "Available"
+		if expectedUpdated < desired && sts.Status.CurrentRevision != sts.Status.UpdateRevision {
+			status.Reason = "PartitionedRollout"
+			status.Message = fmt.Sprintf("ordinals below %d are held at the current revision", statefulSetPartition(sts))
+		}
+	}
+
+	status.Ready = status.Phase == "Available"
+	return status
+}
+
+// getDaemonSetStatus derives a daemonset's status from its scheduled, updated and ready pod counts
+func getDaemonSetStatus(ds *appsv1.DaemonSet) models.ResourceStatus {
+	desired := ds.Status.DesiredNumberScheduled
+	status := models.ResourceStatus{LastUpdated: time.Now()}
+
+	switch {
+	case ds.Status.ObservedGeneration < ds.Generation:
+		status.Phase = "Progressing"
+		status.Reason = "SpecNotObserved"
+		status.Message = "the controller has not yet processed the latest spec"
+	case desired > 0 && ds.Status.NumberReady == 0:
+		status.Phase = "Unavailable"
+		status.Reason = "NoReadyPods"
+	case ds.Status.NumberReady < desired:
+		status.Phase = "Progressing"
+		status.Reason = "PodsNotReady"
+		status.Message = fmt.Sprintf("%d of %d pods ready", ds.Status.NumberReady, desired)
+	case ds.Spec.UpdateStrategy.Type != appsv1.OnDeleteDaemonSetStrategyType && ds.Status.UpdatedNumberScheduled < desired:
+		status.Phase = "Progressing"
+		status.Reason = "RollingUpdate"
+		status.Message = fmt.Sprintf("%d of %d pods updated", ds.Status.UpdatedNumberScheduled, desired)
+	default:
+		status.Phase = "Available"
+	}
+
+	if ds.Status.NumberMisscheduled > 0 && status.Reason == "" {
+		status.Reason = "Misscheduled"
+		status.Message = fmt.Sprintf("%d pods running on nodes they should not run on", ds.Status.NumberMisscheduled)
+	}
+
+	status.Ready = status.Phase == "Available"
+	return status
+}
+
+// getReplicaSetStatus derives a replicaset's status from its ready replicas and ReplicaFailure condition
+func getReplicaSetStatus(rs *appsv1.ReplicaSet) models.ResourceStatus {
+	desired := desiredReplicas(rs.Spec.Replicas)
+	status := models.ResourceStatus{Phase: "Available", LastUpdated: time.Now()}
+
+	switch {
+	case desired > 0 && rs.Status.ReadyReplicas == 0:
+		status.Phase = "Unavailable"
+	case rs.Status.ReadyReplicas < desired:
+		status.Phase = "Progressing"
+	}
+
+	for _, condition := range rs.Status.Conditions {
+		if condition.Type == appsv1.ReplicaSetReplicaFailure && condition.Status == v1.ConditionTrue {
+			status.Reason = condition.Reason
+			status.Message = condition.Message
+		}
+	}
+
+	status.Ready = status.Phase == "Available"
+	return status
+}
@@ -0,0 +1,125 @@
+package kubernetes
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGetClusterNamespacesListsOncePerKind(t *testing.T) {
+	cs := fake.NewSimpleClientset(
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop"}},
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "system"}},
+		&appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "shop"}},
+		&appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "system"}},
+	)
+
+	namespaces, err := getClusterNamespaces(cs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(namespaces) != 2 {
+		t.Fatalf("expected 2 namespaces, got %d", len(namespaces))
+	}
+	shop, system := namespaces[0], namespaces[1]
+	if len(shop.StatefulSets) != 1 || len(shop.DaemonSets) != 0 || len(system.DaemonSets) != 1 || system.StatefulSets == nil {
+		t.Fatalf("unexpected grouping: shop=%+v system=%+v", shop, system)
+	}
+
+	lists := map[string]int{}
+	for _, action := range cs.Actions() {
+		if action.GetVerb() == "list" {
+			lists[action.GetResource().Resource]++
+		}
+	}
+	for _, resource := range []string{"statefulsets", "daemonsets", "replicasets", "pods", "endpointslices"} {
+		if lists[resource] != 1 {
+			t.Errorf("%s listed %d times, want once", resource, lists[resource])
+		}
+	}
+}
+
+func TestGetStatefulSetStatus(t *testing.T) {
+	int32Ptr := func(v int32) *int32 { return &v }
+	statefulSet := func(partition int32, status appsv1.StatefulSetStatus) *appsv1.StatefulSet {
+		return &appsv1.StatefulSet{
+			Spec: appsv1.StatefulSetSpec{
+				Replicas: int32Ptr(3),
+				UpdateStrategy: appsv1.StatefulSetUpdateStrategy{
+					Type:          appsv1.RollingUpdateStatefulSetStrategyType,
+					RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{Partition: int32Ptr(partition)},
+				},
+			},
+			Status: status,
+		}
+	}
+
+	tests := []struct {
+		name   string
+		sts    *appsv1.StatefulSet
+		phase  string
+		reason string
+	}{
+		{
+			name:  "rolled out",
+			sts:   statefulSet(0, appsv1.StatefulSetStatus{ReadyReplicas: 3, UpdatedReplicas: 3, CurrentRevision: "r2", UpdateRevision: "r2"}),
+			phase: "Available",
+		},
+		{
+			name:   "rolling",
+			sts:    statefulSet(0, appsv1.StatefulSetStatus{ReadyReplicas: 3, UpdatedReplicas: 1, CurrentRevision: "r1", UpdateRevision: "r2"}),
+			phase:  "Progressing",
+			reason: "RollingUpdate",
+		},
+		{
+			name:   "partition reached",
+			sts:    statefulSet(2, appsv1.StatefulSetStatus{ReadyReplicas: 3, UpdatedReplicas: 1, CurrentRevision: "r1", UpdateRevision: "r2"}),
+			phase:  "Available",
+			reason: "PartitionedRollout",
+		},
+		{
+			name:   "not ready",
+			sts:    statefulSet(0, appsv1.StatefulSetStatus{ReadyReplicas: 2, UpdatedReplicas: 3, CurrentRevision: "r2", UpdateRevision: "r2"}),
+			phase:  "Progressing",
+			reason: "ReplicasNotReady",
+		},
+		{
+			name:   "none ready",
+			sts:    statefulSet(0, appsv1.StatefulSetStatus{}),
+			phase:  "Unavailable",
+			reason: "NoReadyReplicas",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := getStatefulSetStatus(tt.sts)
+			if status.Phase != tt.phase || status.Reason != tt.reason || status.Ready != (tt.phase == "Available") {
+				t.Fatalf("expected %s/%s, got %+v", tt.phase, tt.reason, status)
+			}
+		})
+	}
+}
+
+func TestGetDaemonSetStatus(t *testing.T) {
+	ds := &appsv1.DaemonSet{
+		Spec: appsv1.DaemonSetSpec{UpdateStrategy: appsv1.DaemonSetUpdateStrategy{Type: appsv1.RollingUpdateDaemonSetStrategyType}},
+		Status: appsv1.DaemonSetStatus{
+			DesiredNumberScheduled: 4,
+			NumberReady:            4,
+			UpdatedNumberScheduled: 2,
+		},
+	}
+	if status := getDaemonSetStatus(ds); status.Phase != "Progressing" || status.Reason != "RollingUpdate" {
+		t.Fatalf("expected a rolling update, got %+v", status)
+	}
+
+	ds.Status.UpdatedNumberScheduled = 4
+	ds.Status.NumberMisscheduled = 1
+	if status := getDaemonSetStatus(ds); !status.Ready || status.Reason != "Misscheduled" {
+		t.Fatalf("expected an available daemonset with misscheduled pods, got %+v", status)
+	}
+}
@@ -0,0 +1,62 @@
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"kubey/api/internal/models"
+)
+
+// PodContainer identifies a container of a specific pod
+type PodContainer struct {
+	Namespace string
+	Pod       string
+	Container string
+}
+
+// QueryUsagePercentiles returns the given percentiles (0-100) of CPU and memory usage over the
+// window for every container in the namespace, or in all namespaces when namespace is empty
+func QueryUsagePercentiles(ctx context.Context, client *Client, namespace string, window time.Duration, cpuPercentile, memoryPercentile float64, at time.Time) (map[PodContainer]models.UsageSample, error) {
+	matchers := `container!="",container!="POD"`
+	if namespace != "" {
+		matchers += ",namespace=" + strconv.Quote(namespace)
+	}
+	subquery := fmt.Sprintf("[%ds:%s]", int64(window.Seconds()), rateWindow)
+
+	cpuQuery := fmt.Sprintf(`quantile_over_time(%g, sum by (namespace, pod, container) (rate(container_cpu_usage_seconds_total{%s}[%s]))%s) * 1000`,
+		cpuPercentile/100, matchers, rateWindow, subquery)
+	memoryQuery := fmt.Sprintf(`quantile_over_time(%g, sum by (namespace, pod, container) (container_memory_working_set_bytes{%s})%s)`,
+		memoryPercentile/100, matchers, subquery)
+
+	usage := map[PodContainer]models.UsageSample{}
+
+	cpu, err := client.Query(ctx, cpuQuery, at)
+	if err != nil {
+		return nil, err
+	}
+	for _, series := range cpu {
+		key := podContainerKey(series.Labels)
+		sample := usage[key]
+		sample.CPU = series.Values[0]
+		usage[key] = sample
+	}
+
+	memory, err := client.Query(ctx, memoryQuery, at)
+	if err != nil {
+		return nil, err
+	}
+	for _, series := range memory {
+		key := podContainerKey(series.Labels)
+		sample := usage[key]
+		sample.Memory = int64(series.Values[0])
+		usage[key] = sample
+	}
+
+	return usage, nil
+}
+
+func podContainerKey(labels map[string]string) PodContainer {
+	return PodContainer{Namespace: labels["namespace"], Pod: labels["pod"], Container: labels["container"]}
+}
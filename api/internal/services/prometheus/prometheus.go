@@ -0,0 +1,154 @@
+package prometheus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNotConfigured is returned when no Prometheus endpoint is configured for a cluster
+var ErrNotConfigured = errors.New("prometheus not configured for cluster")
+
+// Client queries a Prometheus HTTP API
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// Series is a time series returned by a range query
+type Series struct {
+	Labels map[string]string
+	Times  []time.Time
+	Values []float64
+}
+
+// NewClient returns a client for the Prometheus server at baseURL
+func NewClient(baseURL string, timeout time.Duration) *Client {
+	return &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// queryResponse mirrors the Prometheus API envelope for matrix results
+type queryResponse struct {
+	Status    string `json:"status"`
+	ErrorType string `json:"errorType"`
+	Error     string `json:"error"`
+	Data      struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			Metric map[string]string `json:"metric"`
+			Values [][2]interface{}  `json:"values"`
+			Value  [2]interface{}    `json:"value"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// QueryRange evaluates a PromQL expression over a time range
+func (c *Client) QueryRange(ctx context.Context, query string, start, end time.Time, step time.Duration) ([]Series, error) {
+	form := url.Values{}
+	form.Set("query", query)
+	form.Set("start", strconv.FormatInt(start.Unix(), 10))
+	form.Set("end", strconv.FormatInt(end.Unix(), 10))
+	form.Set("step", strconv.FormatFloat(step.Seconds(), 'f', -1, 64))
+
+	decoded, err := c.post(ctx, "/api/v1/query_range", form, "matrix")
+	if err != nil {
+		return nil, err
+	}
+
+	series := make([]Series, 0, len(decoded.Data.Result))
+	for _, result := range decoded.Data.Result {
+		s := Series{Labels: result.Metric}
+		for _, pair := range result.Values {
+			if t, value, ok := parseSample(pair); ok {
+				s.Times = append(s.Times, t)
+				s.Values = append(s.Values, value)
+			}
+		}
+		series = append(series, s)
+	}
+
+	return series, nil
+}
+
+// Query evaluates a PromQL expression at a single point in time and returns one sample per series
+func (c *Client) Query(ctx context.Context, query string, at time.Time) ([]Series, error) {
+	form := url.Values{}
+	form.Set("query", query)
+	form.Set("time", strconv.FormatInt(at.Unix(), 10))
+
+	decoded, err := c.post(ctx, "/api/v1/query", form, "vector")
+	if err != nil {
+		return nil, err
+	}
+
+	series := make([]Series, 0, len(decoded.Data.Result))
+	for _, result := range decoded.Data.Result {
+		if t, value, ok := parseSample(result.Value); ok {
+			series = append(series, Series{Labels: result.Metric, Times: []time.Time{t}, Values: []float64{value}})
+		}
+	}
+
+	return series, nil
+}
+
+// post sends a query to the Prometheus API and checks the result type of the response
+func (c *Client) post(ctx context.Context, path string, form url.Values, resultType string) (*queryResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build prometheus request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query prometheus: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read prometheus response: %v", err)
+	}
+
+	var decoded queryResponse
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return nil, fmt.Errorf("failed to decode prometheus response (HTTP %d): %v", resp.StatusCode, err)
+	}
+	if decoded.Status != "success" {
+		return nil, fmt.Errorf("prometheus query failed: %s: %s", decoded.ErrorType, decoded.Error)
+	}
+	if decoded.Data.ResultType != resultType {
+		return nil, fmt.Errorf("unexpected prometheus result type %q", decoded.Data.ResultType)
+	}
+
+	return &decoded, nil
+}
+
+// parseSample decodes a [timestamp, "value"] pair
+func parseSample(pair [2]interface{}) (time.Time, float64, bool) {
+	timestamp, ok := pair[0].(float64)
+	if !ok {
+		return time.Time{}, 0, false
+	}
+	raw, ok := pair[1].(string)
+	if !ok {
+		return time.Time{}, 0, false
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	// NaN and Inf (e.g. from division by zero) cannot be encoded as JSON
+	if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+		return time.Time{}, 0, false
+	}
+	return time.Unix(0, int64(timestamp*float64(time.Second))), value, true
+}
@@ -0,0 +1,196 @@
+package prometheus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"kubey/api/internal/models"
+)
+
+// ErrInvalidQuery is returned for unknown scopes or metrics and out-of-range query windows
+var ErrInvalidQuery = errors.New("invalid metrics query")
+
+// maxPoints is the largest number of points per series Prometheus returns for a range query
+const maxPoints = 11000
+
+// rateWindow is the range used for rate() over counters
+const rateWindow = "5m"
+
+// SeriesQuery selects a predefined metric for a pod, container, node or namespace
+type SeriesQuery struct {
+	Scope     string // pod, container, node, namespace
+	Metric    string // cpu, memory, network_receive, network_transmit, restarts
+	Namespace string
+	Pod       string
+	Container string
+	Node      string
+	Start     time.Time
+	End       time.Time
+	Step      time.Duration
+}
+
+// metricTemplate is a PromQL expression with placeholders for the label selector (%[1]s) and the
+// grouping labels (%[2]s). matchers are always part of the selector.
+type metricTemplate struct {
+	unit     string
+	matchers []string
+	query    string
+	// nodeQuery is used for node scope when the metric has no node label of its own
+	nodeQuery string
+}
+
+var metricTemplates = map[string]metricTemplate{
+	"cpu": {
+		unit:     "millicores",
+		matchers: []string{`container!=""`, `container!="POD"`},
+		query:    `sum by (%[2]s) (rate(container_cpu_usage_seconds_total{%[1]s}[` + rateWindow + `])) * 1000`,
+	},
+	"memory": {
+		unit:     "bytes",
+		matchers: []string{`container!=""`, `container!="POD"`},
+		query:    `sum by (%[2]s) (container_memory_working_set_bytes{%[1]s})`,
+	},
+	"network_receive": {
+		unit:     "bytes/s",
+		matchers: []string{`pod!=""`},
+		query:    `sum by (%[2]s) (rate(container_network_receive_bytes_total{%[1]s}[` + rateWindow + `]))`,
+	},
+	"network_transmit": {
+		unit:     "bytes/s",
+		matchers: []string{`pod!=""`},
+		query:    `sum by (%[2]s) (rate(container_network_transmit_bytes_total{%[1]s}[` + rateWindow + `]))`,
+	},
+	"restarts": {
+		unit:      "count",
+		query:     `sum by (%[2]s) (kube_pod_container_status_restarts_total{%[1]s})`,
+		nodeQuery: `sum by (%[2]s) (kube_pod_container_status_restarts_total * on (namespace, pod) group_left (node) kube_pod_info{%[1]s})`,
+	},
+}
+
+// scopeGrouping is the set of labels each scope aggregates by
+var scopeGrouping = map[string][]string{
+	"pod":       {"namespace", "pod"},
+	"container": {"namespace", "pod", "container"},
+	"node":      {"node"},
+	"namespace": {"namespace"},
+}
+
+// BuildQuery renders the PromQL expression for q and returns it with the unit of its values
+func BuildQuery(q SeriesQuery) (string, string, error) {
+	template, ok := metricTemplates[q.Metric]
+	if !ok {
+		return "", "", fmt.Errorf("%w: unknown metric %q", ErrInvalidQuery, q.Metric)
+	}
+	grouping, ok := scopeGrouping[q.Scope]
+	if !ok {
+		return "", "", fmt.Errorf("%w: unknown scope %q", ErrInvalidQuery, q.Scope)
+	}
+
+	matchers := append([]string{}, template.matchers...)
+	switch q.Scope {
+	case "pod", "container":
+		if q.Namespace == "" {
+			return "", "", fmt.Errorf("%w: namespace is required for %s scope", ErrInvalidQuery, q.Scope)
+		}
+		if q.Scope == "container" && strings.HasPrefix(q.Metric, "network_") {
+			return "", "", fmt.Errorf("%w: network metrics are only reported per pod", ErrInvalidQuery)
+		}
+		matchers = appendMatcher(matchers, "namespace", q.Namespace)
+		matchers = appendMatcher(matchers, "pod", q.Pod)
+		matchers = appendMatcher(matchers, "container", q.Container)
+	case "node":
+		matchers = appendMatcher(matchers, "node", q.Node)
+	case "namespace":
+		matchers = appendMatcher(matchers, "namespace", q.Namespace)
+	}
+
+	expression := template.query
+	if q.Scope == "node" && template.nodeQuery != "" {
+		expression = template.nodeQuery
+	}
+
+	return fmt.Sprintf(expression, strings.Join(matchers, ","), strings.Join(grouping, ", ")), template.unit, nil
+}
+
+// QuerySeries runs a predefined metric query and maps the results to the pods, containers,
+// nodes or namespaces they belong to
+func QuerySeries(ctx context.Context, client *Client, clusterID string, q SeriesQuery) (*models.MetricSeriesResponse, error) {
+	if q.Step <= 0 || !q.End.After(q.Start) {
+		return nil, fmt.Errorf("%w: step must be positive and end after start", ErrInvalidQuery)
+	}
+	if points := q.End.Sub(q.Start) / q.Step; points > maxPoints {
+		return nil, fmt.Errorf("%w: %d points per series exceeds the maximum of %d, increase the step", ErrInvalidQuery, points, maxPoints)
+	}
+
+	query, unit, err := BuildQuery(q)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := client.QueryRange(ctx, query, q.Start, q.End, q.Step)
+	if err != nil {
+		return nil, err
+	}
+
+	series := make([]models.MetricSeries, 0, len(results))
+	for _, result := range results {
+		s := models.MetricSeries{
+			Object:  seriesObject(q.Scope, result.Labels),
+			Labels:  result.Labels,
+			Samples: make([]models.MetricSample, len(result.Values)),
+		}
+		if q.Scope == "container" {
+			s.Container = result.Labels["container"]
+		}
+		for i := range result.Values {
+			s.Samples[i] = models.MetricSample{Time: result.Times[i], Value: result.Values[i]}
+		}
+		series = append(series, s)
+	}
+
+	sort.Slice(series, func(i, j int) bool {
+		a, b := series[i], series[j]
+		if a.Object.Namespace != b.Object.Namespace {
+			return a.Object.Namespace < b.Object.Namespace
+		}
+		if a.Object.Name != b.Object.Name {
+			return a.Object.Name < b.Object.Name
+		}
+		return a.Container < b.Container
+	})
+
+	return &models.MetricSeriesResponse{
+		ClusterID: clusterID,
+		Scope:     q.Scope,
+		Metric:    q.Metric,
+		Unit:      unit,
+		Start:     q.Start,
+		End:       q.End,
+		Step:      q.Step.String(),
+		Series:    series,
+	}, nil
+}
+
+// seriesObject identifies the object a result series belongs to from its labels
+func seriesObject(scope string, labels map[string]string) models.ObjectReference {
+	switch scope {
+	case "node":
+		return models.ObjectReference{Kind: "Node", Name: labels["node"]}
+	case "namespace":
+		return models.ObjectReference{Kind: "Namespace", Name: labels["namespace"]}
+	default:
+		return models.ObjectReference{Kind: "Pod", Namespace: labels["namespace"], Name: labels["pod"]}
+	}
+}
+
+func appendMatcher(matchers []string, label, value string) []string {
+	if value == "" {
+		return matchers
+	}
+	return append(matchers, label+"="+strconv.Quote(value))
+}
@@ -0,0 +1,105 @@
+package prometheus
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestBuildQuery(t *testing.T) {
+	query, unit, err := BuildQuery(SeriesQuery{Scope: "pod", Metric: "cpu", Namespace: "shop", Pod: "api-0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `sum by (namespace, pod) (rate(container_cpu_usage_seconds_total{container!="",container!="POD",namespace="shop",pod="api-0"}[5m])) * 1000`
+	if query != want || unit != "millicores" {
+		t.Errorf("got %q (%s), want %q", query, unit, want)
+	}
+
+	query, _, err = BuildQuery(SeriesQuery{Scope: "node", Metric: "restarts", Node: "node-a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = `sum by (node) (kube_pod_container_status_restarts_total * on (namespace, pod) group_left (node) kube_pod_info{node="node-a"})`
+	if query != want {
+		t.Errorf("got %q, want %q", query, want)
+	}
+
+	for _, q := range []SeriesQuery{
+		{Scope: "pod", Metric: "disk", Namespace: "shop"},
+		{Scope: "cluster", Metric: "cpu"},
+		{Scope: "pod", Metric: "cpu"},
+		{Scope: "container", Metric: "network_receive", Namespace: "shop", Pod: "api-0"},
+	} {
+		if _, _, err := BuildQuery(q); !errors.Is(err, ErrInvalidQuery) {
+			t.Errorf("BuildQuery(%+v) error = %v, want ErrInvalidQuery", q, err)
+		}
+	}
+}
+
+func TestQuerySeries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/query_range" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.FormValue("step") != "60" {
+			t.Errorf("step = %q, want 60", r.FormValue("step"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[
+			{"metric":{"namespace":"shop","pod":"web-1","container":"nginx"},"values":[[1700000000,"12.5"],[1700000060,"NaN"]]},
+			{"metric":{"namespace":"shop","pod":"api-0","container":"app"},"values":[[1700000000,"3"]]}
+		]}}`))
+	}))
+	defer server.Close()
+
+	start := time.Unix(1700000000, 0)
+	resp, err := QuerySeries(context.Background(), NewClient(server.URL, time.Second), "context-test", SeriesQuery{
+		Scope:     "container",
+		Metric:    "memory",
+		Namespace: "shop",
+		Start:     start,
+		End:       start.Add(time.Hour),
+		Step:      time.Minute,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.Unit != "bytes" || len(resp.Series) != 2 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	first := resp.Series[0]
+	if first.Object.Kind != "Pod" || first.Object.Name != "api-0" || first.Container != "app" {
+		t.Errorf("series not sorted or keyed by pod: %+v", first)
+	}
+	second := resp.Series[1]
+	if len(second.Samples) != 1 || second.Samples[0].Value != 12.5 || !second.Samples[0].Time.Equal(start) {
+		t.Errorf("unexpected samples: %+v", second.Samples)
+	}
+}
+
+func TestQuerySeriesErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"status":"error","errorType":"bad_data","error":"parse error"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, time.Second)
+	start := time.Unix(1700000000, 0)
+	q := SeriesQuery{Scope: "namespace", Metric: "cpu", Start: start, End: start.Add(time.Hour), Step: time.Minute}
+
+	if _, err := QuerySeries(context.Background(), client, "c", q); err == nil {
+		t.Error("expected prometheus error to be returned")
+	}
+
+	q.Step = time.Millisecond
+	if _, err := QuerySeries(context.Background(), client, "c", q); !errors.Is(err, ErrInvalidQuery) {
+		t.Errorf("error = %v, want ErrInvalidQuery for too many points", err)
+	}
+}
@@ -0,0 +1,84 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"kubey/api/internal/models"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// changesBucket holds changes keyed by "<timestamp>|<cluster>|<uid>|<resource version>"
+var changesBucket = []byte("changes")
+
+// SaveChange records a resource change
+func (s *Store) SaveChange(change models.ResourceChange) error {
+	if change.Timestamp.IsZero() {
+		change.Timestamp = time.Now()
+	}
+
+	value, err := json.Marshal(change)
+	if err != nil {
+		return fmt.Errorf("failed to encode change: %v", err)
+	}
+
+	key := []byte(timeKey(change.Timestamp) + "|" + change.ClusterID + "|" + change.Object.UID + "|" + change.ResourceVersion)
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(changesBucket).Put(key, value)
+	})
+}
+
+// QueryChanges returns changes matching the query, newest first
+func (s *Store) QueryChanges(query models.ChangeQuery) ([]models.ResourceChange, error) {
+	changes := []models.ResourceChange{}
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return scanNewestFirst(tx.Bucket(changesBucket), query.Since, query.Until, func(k, v []byte) (bool, error) {
+			var change models.ResourceChange
+			if err := json.Unmarshal(v, &change); err != nil {
+				return false, fmt.Errorf("failed to decode change %s: %v", k, err)
+			}
+			if matchesChangeQuery(&change, &query) {
+				changes = append(changes, change)
+			}
+			return query.Limit <= 0 || len(changes) < query.Limit, nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// PruneChanges deletes changes recorded before the cutoff and returns how many were removed
+func (s *Store) PruneChanges(before time.Time) (int, error) {
+	removed := 0
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		values, err := deleteBefore(tx.Bucket(changesBucket), before)
+		removed = len(values)
+		return err
+	})
+
+	return removed, err
+}
+
+func matchesChangeQuery(change *models.ResourceChange, query *models.ChangeQuery) bool {
+	if query.ClusterID != "" && change.ClusterID != query.ClusterID {
+		return false
+	}
+	if query.Namespace != "" && change.Object.Namespace != query.Namespace {
+		return false
+	}
+	if query.Kind != "" && change.Object.Kind != query.Kind {
+		return false
+	}
+	if query.Name != "" && change.Object.Name != query.Name {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,94 @@
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"kubey/api/internal/models"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// costUsageBucket holds hourly resource-hours keyed by "<hour timestamp>|<cluster>"
+var costUsageBucket = []byte("costUsage")
+
+// AddCostUsage adds resource-hours to the cluster's record for the hour containing delta.Hour
+func (s *Store) AddCostUsage(clusterID string, delta models.CostUsage) error {
+	hour := delta.Hour.Truncate(time.Hour)
+	key := summaryKey(hour, clusterID)
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(costUsageBucket)
+
+		usage := models.CostUsage{
+			Hour:     hour,
+			Capacity: map[string]models.ResourceHours{},
+			Groups:   map[string]models.AllocationHours{},
+		}
+		if existing := b.Get(key); existing != nil {
+			if err := json.Unmarshal(existing, &usage); err != nil {
+				return fmt.Errorf("failed to decode cost usage %s: %v", key, err)
+			}
+		}
+
+		mergeCostUsage(&usage, delta)
+
+		value, err := json.Marshal(usage)
+		if err != nil {
+			return fmt.Errorf("failed to encode cost usage: %v", err)
+		}
+		return b.Put(key, value)
+	})
+}
+
+// QueryCostUsage returns a cluster's hourly records for hours starting in [since, until], oldest first
+func (s *Store) QueryCostUsage(clusterID string, since, until time.Time) ([]models.CostUsage, error) {
+	var records []models.CostUsage
+	suffix := []byte("|" + clusterID)
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return scanNewestFirst(tx.Bucket(costUsageBucket), since, until, func(k, v []byte) (bool, error) {
+			if !bytes.HasSuffix(k, suffix) {
+				return true, nil
+			}
+			var usage models.CostUsage
+			if err := json.Unmarshal(v, &usage); err != nil {
+				return false, fmt.Errorf("failed to decode cost usage %s: %v", k, err)
+			}
+			records = append([]models.CostUsage{usage}, records...)
+			return true, nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+// PruneCostUsage deletes hourly records before the cutoff and returns how many were removed
+func (s *Store) PruneCostUsage(before time.Time) (int, error) {
+	removed := 0
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		values, err := deleteBefore(tx.Bucket(costUsageBucket), before)
+		removed = len(values)
+		return err
+	})
+
+	return removed, err
+}
+
+func mergeCostUsage(usage *models.CostUsage, delta models.CostUsage) {
+	usage.Samples += delta.Samples
+	usage.UsageSamples += delta.UsageSamples
+
+	for pool, hours := range delta.Capacity {
+		usage.Capacity[pool] = usage.Capacity[pool].Add(hours)
+	}
+	for group, hours := range delta.Groups {
+		usage.Groups[group] = usage.Groups[group].Add(hours)
+	}
+}
@@ -0,0 +1,124 @@
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"kubey/api/internal/models"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	// eventsBucket holds events keyed by "<last timestamp>|<cluster>|<uid>"
+	eventsBucket = []byte("events")
+	// eventIndexBucket maps "<cluster>|<uid>" to the event's current key in eventsBucket
+	eventIndexBucket = []byte("event_index")
+)
+
+// SaveEvent inserts or updates an event. Repeated events with the same UID replace the earlier record.
+func (s *Store) SaveEvent(event models.KubeEvent) error {
+	if event.LastTimestamp.IsZero() {
+		event.LastTimestamp = time.Now()
+	}
+
+	value, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %v", err)
+	}
+
+	indexKey := []byte(event.ClusterID + "|" + event.UID)
+	key := []byte(timeKey(event.LastTimestamp) + "|" + event.ClusterID + "|" + event.UID)
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		events := tx.Bucket(eventsBucket)
+		index := tx.Bucket(eventIndexBucket)
+
+		// Drop the previous version so the event moves to its new position in time
+		if previous := index.Get(indexKey); previous != nil && !bytes.Equal(previous, key) {
+			if err := events.Delete(previous); err != nil {
+				return err
+			}
+		}
+
+		if err := events.Put(key, value); err != nil {
+			return err
+		}
+		return index.Put(indexKey, key)
+	})
+}
+
+// QueryEvents returns events matching the query, newest first
+func (s *Store) QueryEvents(query models.EventQuery) ([]models.KubeEvent, error) {
+	events := []models.KubeEvent{}
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return scanNewestFirst(tx.Bucket(eventsBucket), query.Since, query.Until, func(k, v []byte) (bool, error) {
+			var event models.KubeEvent
+			if err := json.Unmarshal(v, &event); err != nil {
+				return false, fmt.Errorf("failed to decode event %s: %v", k, err)
+			}
+			if matchesEventQuery(&event, &query) {
+				events = append(events, event)
+			}
+			return query.Limit <= 0 || len(events) < query.Limit, nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// PruneEvents deletes events last seen before the cutoff and returns how many were removed
+func (s *Store) PruneEvents(before time.Time) (int, error) {
+	removed := 0
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		values, err := deleteBefore(tx.Bucket(eventsBucket), before)
+		if err != nil {
+			return err
+		}
+
+		index := tx.Bucket(eventIndexBucket)
+		for _, v := range values {
+			var event models.KubeEvent
+			if err := json.Unmarshal(v, &event); err != nil {
+				continue
+			}
+			if err := index.Delete([]byte(event.ClusterID + "|" + event.UID)); err != nil {
+				return err
+			}
+		}
+
+		removed = len(values)
+		return nil
+	})
+
+	return removed, err
+}
+
+func matchesEventQuery(event *models.KubeEvent, query *models.EventQuery) bool {
+	if query.ClusterID != "" && event.ClusterID != query.ClusterID {
+		return false
+	}
+	if query.Namespace != "" && event.Namespace != query.Namespace {
+		return false
+	}
+	if query.Kind != "" && event.Object.Kind != query.Kind {
+		return false
+	}
+	if query.Name != "" && event.Object.Name != query.Name {
+		return false
+	}
+	if query.Reason != "" && event.Reason != query.Reason {
+		return false
+	}
+	if query.Type != "" && event.Type != query.Type {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,87 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"kubey/api/internal/models"
+)
+
+func openTestStore(t *testing.T) *Store {
+	t.Helper()
+
+	s, err := Open(filepath.Join(t.TempDir(), "kubey.db"))
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestEventsSaveQueryPrune(t *testing.T) {
+	s := openTestStore(t)
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	events := []models.KubeEvent{
+		{ClusterID: "context-a", UID: "1", Namespace: "default", Reason: "Pulled", Type: "Normal", LastTimestamp: base},
+		{ClusterID: "context-a", UID: "2", Namespace: "kube-system", Reason: "BackOff", Type: "Warning", LastTimestamp: base.Add(time.Minute)},
+		{ClusterID: "context-b", UID: "3", Namespace: "default", Reason: "BackOff", Type: "Warning", LastTimestamp: base.Add(2 * time.Minute)},
+	}
+	for _, event := range events {
+		if err := s.SaveEvent(event); err != nil {
+			t.Fatalf("failed to save event: %v", err)
+		}
+	}
+
+	// Updating an event replaces it rather than adding a duplicate.
+	updated := events[0]
+	updated.Count = 5
+	updated.LastTimestamp = base.Add(3 * time.Minute)
+	if err := s.SaveEvent(updated); err != nil {
+		t.Fatalf("failed to update event: %v", err)
+	}
+
+	all, err := s.QueryEvents(models.EventQuery{})
+	if err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("expected 3 events, got %d", len(all))
+	}
+	if all[0].UID != "1" || all[0].Count != 5 {
+		t.Fatalf("expected updated event first, got %+v", all[0])
+	}
+
+	warnings, err := s.QueryEvents(models.EventQuery{ClusterID: "context-a", Reason: "BackOff"})
+	if err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	if len(warnings) != 1 || warnings[0].UID != "2" {
+		t.Fatalf("expected only event 2, got %+v", warnings)
+	}
+
+	window, err := s.QueryEvents(models.EventQuery{Since: base.Add(time.Minute), Until: base.Add(2 * time.Minute)})
+	if err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	if len(window) != 2 || window[0].UID != "3" || window[1].UID != "2" {
+		t.Fatalf("expected events 3 and 2 in the time window, got %+v", window)
+	}
+
+	removed, err := s.PruneEvents(base.Add(90 * time.Second))
+	if err != nil {
+		t.Fatalf("prune failed: %v", err)
+	}
+	if removed != 1 {
+		t.Fatalf("expected 1 pruned event, got %d", removed)
+	}
+
+	remaining, err := s.QueryEvents(models.EventQuery{})
+	if err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	if len(remaining) != 2 {
+		t.Fatalf("expected 2 remaining events, got %d", len(remaining))
+	}
+}
@@ -0,0 +1,269 @@
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"kubey/api/internal/models"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Summary history is kept at two resolutions, both keyed by "<timestamp>|<cluster>":
+// raw samples as recorded, and rollups that average older samples into fixed windows.
+var (
+	summarySamplesBucket = []byte("summarySamples")
+	summaryRollupsBucket = []byte("summaryRollups")
+)
+
+// summaryRecord is a stored sample; Count is the number of raw samples it averages
+type summaryRecord struct {
+	ClusterID string               `json:"clusterId"`
+	Count     int                  `json:"count"`
+	Sample    models.SummarySample `json:"sample"`
+}
+
+// SaveSummarySample records a raw summary sample for a cluster
+func (s *Store) SaveSummarySample(clusterID string, sample models.SummarySample) error {
+	if sample.Time.IsZero() {
+		sample.Time = time.Now()
+	}
+
+	value, err := json.Marshal(summaryRecord{ClusterID: clusterID, Count: 1, Sample: sample})
+	if err != nil {
+		return fmt.Errorf("failed to encode summary sample: %v", err)
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(summarySamplesBucket).Put(summaryKey(sample.Time, clusterID), value)
+	})
+}
+
+// QuerySummaryHistory returns a cluster's samples in [start, end], averaged into windows of
+// step starting at start, oldest first. Raw samples and rollups are combined.
+func (s *Store) QuerySummaryHistory(clusterID string, start, end time.Time, step time.Duration) ([]models.SummarySample, error) {
+	if step <= 0 {
+		return nil, fmt.Errorf("step must be positive")
+	}
+
+	windows := map[int64][]summaryRecord{}
+	suffix := []byte("|" + clusterID)
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{summaryRollupsBucket, summarySamplesBucket} {
+			err := scanNewestFirst(tx.Bucket(name), start, end, func(k, v []byte) (bool, error) {
+				if !bytes.HasSuffix(k, suffix) {
+					return true, nil
+				}
+				var record summaryRecord
+				if err := json.Unmarshal(v, &record); err != nil {
+					return false, fmt.Errorf("failed to decode summary sample %s: %v", k, err)
+				}
+				window := int64(record.Sample.Time.Sub(start) / step)
+				windows[window] = append(windows[window], record)
+				return true, nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	samples := make([]models.SummarySample, 0, len(windows))
+	for window, records := range windows {
+		merged := mergeSummaryRecords(records)
+		merged.Sample.Time = start.Add(time.Duration(window) * step)
+		samples = append(samples, merged.Sample)
+	}
+	sort.Slice(samples, func(i, j int) bool {
+		return samples[i].Time.Before(samples[j].Time)
+	})
+
+	return samples, nil
+}
+
+// CompactSummaryHistory averages raw samples recorded before the cutoff into rollups of the
+// given resolution and deletes them. It returns how many raw samples were compacted.
+func (s *Store) CompactSummaryHistory(before time.Time, resolution time.Duration) (int, error) {
+	compacted := 0
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		values, err := deleteBefore(tx.Bucket(summarySamplesBucket), before)
+		if err != nil {
+			return err
+		}
+		compacted = len(values)
+
+		rollups := tx.Bucket(summaryRollupsBucket)
+		groups := map[string][]summaryRecord{}
+		for _, v := range values {
+			var record summaryRecord
+			if err := json.Unmarshal(v, &record); err != nil {
+				return fmt.Errorf("failed to decode summary sample: %v", err)
+			}
+			key := string(summaryKey(record.Sample.Time.Truncate(resolution), record.ClusterID))
+			groups[key] = append(groups[key], record)
+		}
+
+		for key, records := range groups {
+			// A window can be compacted in several passes; fold in the existing rollup
+			if existing := rollups.Get([]byte(key)); existing != nil {
+				var record summaryRecord
+				if err := json.Unmarshal(existing, &record); err != nil {
+					return fmt.Errorf("failed to decode summary rollup %s: %v", key, err)
+				}
+				records = append(records, record)
+			}
+
+			merged := mergeSummaryRecords(records)
+			merged.Sample.Time = records[0].Sample.Time.Truncate(resolution)
+			value, err := json.Marshal(merged)
+			if err != nil {
+				return fmt.Errorf("failed to encode summary rollup: %v", err)
+			}
+			if err := rollups.Put([]byte(key), value); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	return compacted, err
+}
+
+// PruneSummaryHistory deletes raw samples and rollups before the cutoff and returns how many were removed
+func (s *Store) PruneSummaryHistory(before time.Time) (int, error) {
+	removed := 0
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{summarySamplesBucket, summaryRollupsBucket} {
+			values, err := deleteBefore(tx.Bucket(name), before)
+			if err != nil {
+				return err
+			}
+			removed += len(values)
+		}
+		return nil
+	})
+
+	return removed, err
+}
+
+func summaryKey(t time.Time, clusterID string) []byte {
+	return []byte(timeKey(t) + "|" + clusterID)
+}
+
+// mergeSummaryRecords averages records, weighting each by the number of raw samples it holds.
+// Utilization is averaged only over samples where it was available.
+func mergeSummaryRecords(records []summaryRecord) summaryRecord {
+	var weight, utilizationWeight float64
+	var nodes, readyNodes, pods, runningPods, pendingPods, failedPods, namespaces, deployments, services float64
+	var statefulSets, daemonSets, replicaSets, failedJobs float64
+	var cpuRequested, memoryRequested, cpuUtilization, memoryUtilization float64
+	namespaceTotals := map[string]*[6]float64{}
+	count := 0
+
+	for _, record := range records {
+		w := float64(record.Count)
+		if w <= 0 {
+			continue
+		}
+		count += record.Count
+		weight += w
+
+		summary := record.Sample.Summary
+		nodes += w * float64(summary.TotalNodes)
+		readyNodes += w * float64(summary.ReadyNodes)
+		pods += w * float64(summary.TotalPods)
+		runningPods += w * float64(summary.RunningPods)
+		pendingPods += w * float64(summary.PendingPods)
+		failedPods += w * float64(summary.FailedPods)
+		namespaces += w * float64(summary.TotalNamespaces)
+		deployments += w * float64(summary.TotalDeployments)
+		statefulSets += w * float64(summary.TotalStatefulSets)
+		daemonSets += w * float64(summary.TotalDaemonSets)
+		replicaSets += w * float64(summary.TotalReplicaSets)
+		services += w * float64(summary.TotalServices)
+		failedJobs += w * float64(summary.FailedJobs)
+		cpuRequested += w * summary.CPURequested
+		memoryRequested += w * summary.MemoryRequested
+		if summary.UtilizationAvailable {
+			utilizationWeight += w
+			cpuUtilization += w * summary.CPUUtilization
+			memoryUtilization += w * summary.MemoryUtilization
+		}
+
+		for name, usage := range record.Sample.Namespaces {
+			totals, ok := namespaceTotals[name]
+			if !ok {
+				totals = &[6]float64{}
+				namespaceTotals[name] = totals
+			}
+			totals[0] += w * float64(usage.Pods)
+			totals[1] += w * float64(usage.RunningPods)
+			totals[2] += w * usage.CPUUsage
+			totals[3] += w * float64(usage.MemoryUsage)
+			totals[4] += w * usage.CPURequest
+			totals[5] += w * float64(usage.MemoryRequest)
+		}
+	}
+
+	merged := summaryRecord{Count: count}
+	if len(records) > 0 {
+		merged.ClusterID = records[0].ClusterID
+	}
+	if weight == 0 {
+		return merged
+	}
+
+	avg := func(total float64) float64 { return total / weight }
+	avgInt := func(total float64) int { return int(math.Round(avg(total))) }
+	round1 := func(v float64) float64 { return math.Round(v*10) / 10 }
+
+	merged.Sample.Summary = models.ClusterSummary{
+		TotalNodes:        avgInt(nodes),
+		ReadyNodes:        avgInt(readyNodes),
+		TotalPods:         avgInt(pods),
+		RunningPods:       avgInt(runningPods),
+		PendingPods:       avgInt(pendingPods),
+		FailedPods:        avgInt(failedPods),
+		TotalNamespaces:   avgInt(namespaces),
+		TotalDeployments:  avgInt(deployments),
+		TotalStatefulSets: avgInt(statefulSets),
+		TotalDaemonSets:   avgInt(daemonSets),
+		TotalReplicaSets:  avgInt(replicaSets),
+		TotalServices:     avgInt(services),
+		FailedJobs:        avgInt(failedJobs),
+		CPURequested:      round1(avg(cpuRequested)),
+		MemoryRequested:   round1(avg(memoryRequested)),
+	}
+	if utilizationWeight > 0 {
+		merged.Sample.Summary.UtilizationAvailable = true
+		merged.Sample.Summary.CPUUtilization = round1(cpuUtilization / utilizationWeight)
+		merged.Sample.Summary.MemoryUtilization = round1(memoryUtilization / utilizationWeight)
+	}
+
+	if len(namespaceTotals) > 0 {
+		merged.Sample.Namespaces = make(map[string]models.NamespaceUsage, len(namespaceTotals))
+		for name, totals := range namespaceTotals {
+			merged.Sample.Namespaces[name] = models.NamespaceUsage{
+				Pods:          avgInt(totals[0]),
+				RunningPods:   avgInt(totals[1]),
+				CPUUsage:      round1(avg(totals[2])),
+				MemoryUsage:   int64(math.Round(avg(totals[3]))),
+				CPURequest:    round1(avg(totals[4])),
+				MemoryRequest: int64(math.Round(avg(totals[5]))),
+			}
+		}
+	}
+
+	return merged
+}
@@ -0,0 +1,137 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"kubey/api/internal/models"
+)
+
+func TestSummaryHistoryDownsampling(t *testing.T) {
+	s := openTestStore(t)
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	// Four samples a minute apart: pods 10, 20, 30, 40; utilization only measured in two of them
+	for i := 0; i < 4; i++ {
+		sample := models.SummarySample{
+			Time: base.Add(time.Duration(i) * time.Minute),
+			Summary: models.ClusterSummary{
+				TotalPods:            10 * (i + 1),
+				CPURequested:         50,
+				UtilizationAvailable: i%2 == 0,
+				CPUUtilization:       float64(20 * (i + 1)),
+			},
+			Namespaces: map[string]models.NamespaceUsage{"shop": {Pods: i + 1, CPUUsage: 100}},
+		}
+		if err := s.SaveSummarySample("context-a", sample); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.SaveSummarySample("context-b", models.SummarySample{Time: base, Summary: models.ClusterSummary{TotalPods: 999}}); err != nil {
+		t.Fatal(err)
+	}
+
+	samples, err := s.QuerySummaryHistory("context-a", base, base.Add(time.Hour), 2*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 2 {
+		t.Fatalf("expected 2 windows, got %+v", samples)
+	}
+	if samples[0].Summary.TotalPods != 15 || samples[1].Summary.TotalPods != 35 {
+		t.Errorf("unexpected averages: %d, %d", samples[0].Summary.TotalPods, samples[1].Summary.TotalPods)
+	}
+	if samples[0].Summary.CPUUtilization != 20 || !samples[0].Summary.UtilizationAvailable {
+		t.Errorf("utilization should average only measured samples: %+v", samples[0].Summary)
+	}
+	if !samples[1].Time.Equal(base.Add(2 * time.Minute)) {
+		t.Errorf("window time = %v", samples[1].Time)
+	}
+
+	// Compact the first three samples into an hourly rollup, then query across both resolutions
+	compacted, err := s.CompactSummaryHistory(base.Add(3*time.Minute), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if compacted != 4 { // Includes context-b's sample
+		t.Fatalf("compacted %d samples, want 4", compacted)
+	}
+
+	samples, err = s.QuerySummaryHistory("context-a", base, base.Add(time.Hour), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 1 || samples[0].Summary.TotalPods != 25 || samples[0].Summary.CPURequested != 50 {
+		t.Fatalf("unexpected hourly sample: %+v", samples)
+	}
+	if ns := samples[0].Namespaces["shop"]; ns.CPUUsage != 100 || ns.Pods != 3 {
+		t.Errorf("unexpected namespace usage: %+v", ns)
+	}
+
+	removed, err := s.PruneSummaryHistory(base.Add(2 * time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed != 3 { // Two rollups and the last raw sample
+		t.Errorf("removed %d entries, want 3", removed)
+	}
+}
+
+func TestWorkloadUsage(t *testing.T) {
+	s := openTestStore(t)
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	web := models.ContainerKey{Kind: "Deployment", Namespace: "shop", Name: "web", Container: "app"}
+	job := models.ContainerKey{Kind: "StatefulSet", Namespace: "batch", Name: "worker", Container: "main"}
+
+	for i := 0; i < 3; i++ {
+		usage := map[models.ContainerKey]models.UsageSample{
+			web: {CPU: float64(100 * (i + 1)), Memory: 64 << 20},
+			job: {CPU: 5},
+		}
+		if err := s.SaveWorkloadUsage("context-a", base.Add(time.Duration(i)*time.Minute), usage); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	usage, err := s.QueryWorkloadUsage("context-a", "shop", base.Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(usage) != 1 || len(usage[web]) != 2 || usage[web][0].Memory != 64<<20 {
+		t.Fatalf("unexpected usage: %+v", usage)
+	}
+
+	if removed, err := s.PruneWorkloadUsage(base.Add(2 * time.Minute)); err != nil || removed != 2 {
+		t.Fatalf("PruneWorkloadUsage = %d, %v; want 2", removed, err)
+	}
+}
+
+func TestCostUsageAccumulates(t *testing.T) {
+	s := openTestStore(t)
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 3; i++ {
+		delta := models.CostUsage{
+			Hour:     base.Add(time.Duration(i*40) * time.Minute),
+			Samples:  1,
+			Capacity: map[string]models.ResourceHours{"general": {CPUCoreHours: 1}},
+			Groups: map[string]models.AllocationHours{
+				"general|namespace|shop": {Requests: models.ResourceHours{MemoryGiBHours: 0.5}},
+			},
+		}
+		if err := s.AddCostUsage("context-a", delta); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	records, err := s.QueryCostUsage("context-a", base, base.Add(2*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || !records[0].Hour.Equal(base) {
+		t.Fatalf("expected two hourly records, got %+v", records)
+	}
+	if records[0].Samples != 2 || records[0].Capacity["general"].CPUCoreHours != 2 || records[0].Groups["general|namespace|shop"].Requests.MemoryGiBHours != 1 {
+		t.Errorf("first hour not accumulated: %+v", records[0])
+	}
+}
@@ -0,0 +1,63 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Store is kubey's embedded local database
+type Store struct {
+	db *bolt.DB
+}
+
+// buckets lists every top-level bucket created when the store is opened
+var buckets = [][]byte{
+	eventsBucket,
+	eventIndexBucket,
+	changesBucket,
+	summarySamplesBucket,
+	summaryRollupsBucket,
+	workloadUsageBucket,
+	costUsageBucket,
+}
+
+// Open opens (or creates) the store at the given path
+func Open(path string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create store directory: %v", err)
+	}
+
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open store %s: %v", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range buckets {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize store: %v", err)
+	}
+
+	return &Store{db: db}, nil
+}
+
+// Close closes the underlying database
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// timeKey encodes a timestamp as a fixed-width, lexically sortable key prefix
+func timeKey(t time.Time) string {
+	return fmt.Sprintf("%020d", t.UnixNano())
+}
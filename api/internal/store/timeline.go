@@ -0,0 +1,68 @@
+package store
+
+import (
+	"bytes"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Buckets whose keys start with timeKey are scanned and pruned with the helpers below.
+
+// scanNewestFirst calls fn for every entry in the bucket whose key time lies in [since, until],
+// newest first. Zero times leave that end of the range open. fn returns false to stop.
+func scanNewestFirst(b *bolt.Bucket, since, until time.Time, fn func(k, v []byte) (bool, error)) error {
+	c := b.Cursor()
+
+	var k, v []byte
+	if until.IsZero() {
+		k, v = c.Last()
+	} else {
+		upper := []byte(timeKey(until.Add(time.Nanosecond)))
+		if k, v = c.Seek(upper); k == nil {
+			k, v = c.Last()
+		} else {
+			k, v = c.Prev()
+		}
+	}
+
+	var lower []byte
+	if !since.IsZero() {
+		lower = []byte(timeKey(since))
+	}
+
+	for ; k != nil; k, v = c.Prev() {
+		if lower != nil && bytes.Compare(k, lower) < 0 {
+			break
+		}
+		more, err := fn(k, v)
+		if err != nil {
+			return err
+		}
+		if !more {
+			break
+		}
+	}
+	return nil
+}
+
+// deleteBefore removes every entry whose key time is before the cutoff and returns the deleted values
+func deleteBefore(b *bolt.Bucket, before time.Time) ([][]byte, error) {
+	cutoff := []byte(timeKey(before))
+
+	// Collect keys first; deleting while iterating can make the cursor skip entries
+	var keys, values [][]byte
+	c := b.Cursor()
+	for k, v := c.First(); k != nil && bytes.Compare(k, cutoff) < 0; k, v = c.Next() {
+		keys = append(keys, bytes.Clone(k))
+		values = append(values, bytes.Clone(v))
+	}
+
+	for _, k := range keys {
+		if err := b.Delete(k); err != nil {
+			return nil, err
+		}
+	}
+
+	return values, nil
+}
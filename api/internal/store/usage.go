@@ -0,0 +1,91 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"kubey/api/internal/models"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// workloadUsageBucket holds per-container workload usage keyed by "<timestamp>|<cluster>".
+// Each value maps "kind/namespace/name/container" to [cpu, memory].
+var workloadUsageBucket = []byte("workloadUsage")
+
+// SaveWorkloadUsage records one usage sample per workload container
+func (s *Store) SaveWorkloadUsage(clusterID string, t time.Time, usage map[models.ContainerKey]models.UsageSample) error {
+	encoded := make(map[string][2]float64, len(usage))
+	for key, sample := range usage {
+		encoded[encodeContainerKey(key)] = [2]float64{sample.CPU, float64(sample.Memory)}
+	}
+
+	value, err := json.Marshal(encoded)
+	if err != nil {
+		return fmt.Errorf("failed to encode workload usage: %v", err)
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(workloadUsageBucket).Put(summaryKey(t, clusterID), value)
+	})
+}
+
+// QueryWorkloadUsage returns the usage samples recorded since the given time for a cluster's
+// workload containers, optionally limited to one namespace
+func (s *Store) QueryWorkloadUsage(clusterID, namespace string, since time.Time) (map[models.ContainerKey][]models.UsageSample, error) {
+	usage := map[models.ContainerKey][]models.UsageSample{}
+	suffix := "|" + clusterID
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return scanNewestFirst(tx.Bucket(workloadUsageBucket), since, time.Time{}, func(k, v []byte) (bool, error) {
+			if !strings.HasSuffix(string(k), suffix) {
+				return true, nil
+			}
+			var decoded map[string][2]float64
+			if err := json.Unmarshal(v, &decoded); err != nil {
+				return false, fmt.Errorf("failed to decode workload usage %s: %v", k, err)
+			}
+			for encodedKey, values := range decoded {
+				key, ok := decodeContainerKey(encodedKey)
+				if !ok || (namespace != "" && key.Namespace != namespace) {
+					continue
+				}
+				usage[key] = append(usage[key], models.UsageSample{CPU: values[0], Memory: int64(values[1])})
+			}
+			return true, nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return usage, nil
+}
+
+// PruneWorkloadUsage deletes usage samples recorded before the cutoff and returns how many were removed
+func (s *Store) PruneWorkloadUsage(before time.Time) (int, error) {
+	removed := 0
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		values, err := deleteBefore(tx.Bucket(workloadUsageBucket), before)
+		removed = len(values)
+		return err
+	})
+
+	return removed, err
+}
+
+// Kubernetes names cannot contain "/", so it safely separates the key fields
+func encodeContainerKey(key models.ContainerKey) string {
+	return key.Kind + "/" + key.Namespace + "/" + key.Name + "/" + key.Container
+}
+
+func decodeContainerKey(encoded string) (models.ContainerKey, bool) {
+	parts := strings.Split(encoded, "/")
+	if len(parts) != 4 {
+		return models.ContainerKey{}, false
+	}
+	return models.ContainerKey{Kind: parts[0], Namespace: parts[1], Name: parts[2], Container: parts[3]}, true
+}
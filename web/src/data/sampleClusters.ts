@@ -154,7 +154,8 @@ export const sampleClusters: KubeCluster[] = [
             pods: [],
             clusterIP: '10.96.1.1',
             nodePort: 30001,
-            selector: 'app=web-frontend',
+            selector: { 'app': 'web-frontend' },
+            endpoints: [],
             ports: {
               80: {
                 containers: [{ name: 'nginx', image: 'nginx:1.24-alpine' }],
@@ -177,7 +178,8 @@ export const sampleClusters: KubeCluster[] = [
             pods: [],
             clusterIP: '10.96.1.2',
             nodePort: 30002,
-            selector: 'app=api-server',
+            selector: { 'app': 'api-server' },
+            endpoints: [],
             ports: {
               8080: {
                 containers: [{ name: 'api-server', image: 'node:18-alpine' }],
@@ -213,7 +215,8 @@ export const sampleClusters: KubeCluster[] = [
             pods: [],
             clusterIP: '10.96.2.1',
             nodePort: 30003,
-            selector: 'app=redis',
+            selector: { 'app': 'redis' },
+            endpoints: [],
             ports: {
               6379: {
                 containers: [{ name: 'redis', image: 'redis:7-alpine' }],
@@ -249,7 +252,8 @@ export const sampleClusters: KubeCluster[] = [
             pods: [],
             clusterIP: '10.96.3.1',
             nodePort: 30004,
-            selector: 'app=prometheus',
+            selector: { 'app': 'prometheus' },
+            endpoints: [],
             ports: {
               9090: {
                 containers: [{ name: 'prometheus', image: 'prom/prometheus:v2.45.0' }],
@@ -265,7 +269,8 @@ export const sampleClusters: KubeCluster[] = [
             pods: [],
             clusterIP: '10.96.3.2',
             nodePort: 30005,
-            selector: 'app=grafana',
+            selector: { 'app': 'grafana' },
+            endpoints: [],
             ports: {
               3000: {
                 containers: [{ name: 'grafana', image: 'grafana/grafana:9.5.2' }],
@@ -382,7 +387,8 @@ export const sampleClusters: KubeCluster[] = [
             pods: [],
             clusterIP: '10.97.1.1',
             nodePort: 31001,
-            selector: 'app=web-frontend,environment=staging',
+            selector: { 'app': 'web-frontend', 'environment': 'staging' },
+            endpoints: [],
             ports: {
               80: {
                 containers: [{ name: 'nginx-staging', image: 'nginx:1.24-alpine' }],
@@ -472,7 +478,8 @@ export const sampleClusters: KubeCluster[] = [
             pods: [],
             clusterIP: '10.98.1.1',
             nodePort: 32001,
-            selector: 'app=dev-app',
+            selector: { 'app': 'dev-app' },
+            endpoints: [],
             ports: {
               3000: {
                 containers: [{ name: 'dev-app', image: 'node:18-alpine' }],
@@ -561,7 +568,8 @@ export const sampleClusters: KubeCluster[] = [
             pods: [],
             clusterIP: '10.99.1.1',
             nodePort: 33001,
-            selector: 'app=test-runner',
+            selector: { 'app': 'test-runner' },
+            endpoints: [],
             ports: {
               8080: {
                 containers: [{ name: 'test-runner', image: 'node:18-alpine' }],
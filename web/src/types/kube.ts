@@ -31,11 +31,22 @@ export interface KubeDeployment {
   role: string
 }
 
+export interface ServiceEndpoint {
+  address: string
+  pod?: string
+  nodeName?: string
+  zone?: string
+  ready: boolean
+  serving: boolean
+  terminating: boolean
+}
+
 export interface KubeService {
   pods: KubePod[]
   clusterIP: string
   nodePort: number
-  selector: string
+  selector: Record<string, string>
+  endpoints: ServiceEndpoint[]
   ports: Record<number, KubePod>
   role: string
 }